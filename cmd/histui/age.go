@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	ageDepth   int
+	ageFiles   int
+	ageNoBlame bool
+)
+
+// ageBandChars draws the age bands of a directory, youngest first
+var ageBandChars = []string{"█", "▓", "▒", "░"}
+
+var ageCmd = &cobra.Command{
+	Use:   "age [path]",
+	Short: "Map the age of the code: volatile, active, stable and frozen",
+	Long: `age shows how old the code is in a directory tree. Every line is dated with
+git blame and every file with its last modification, and code is classified
+as volatile (changed within days, mostly young lines), active, stable or
+frozen. Volatile code is still settling; stable and frozen code rarely
+changes and is the safest to refactor. The age bands are configured in
+` + config.FileName + ` ("age").`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAge,
+}
+
+func init() {
+	ageCmd.Flags().IntVar(&ageDepth, "depth", 2, "Directory depth of the tree")
+	ageCmd.Flags().IntVar(&ageFiles, "files", 0, "Also list the N youngest files")
+	ageCmd.Flags().BoolVar(&ageNoBlame, "no-blame", false, "Skip git blame and date files by their last modification only (faster)")
+	registerRenderer("codeage", renderCodeAge)
+	rootCmd.AddCommand(ageCmd)
+}
+
+func runAge(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+
+	settings := analysisSettings(repo, cfg)
+	settings.Depth = ageDepth
+	settings.NoBlame = ageNoBlame
+	result, commits, err := runAnalyzer(repo, settings, "codeage", loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+	renderCodeAge(result, commits)
+	return nil
+}
+
+func renderCodeAge(result analysis.AnalysisResult, commits []git.Commit) {
+	results := result.(*analysis.CodeAgeResults)
+	if len(results.Files) == 0 {
+		fmt.Println("No files found.")
+		return
+	}
+
+	bands := results.Bands
+	fmt.Printf("\nCode Age (%s volatile ≤%dd, %s active ≤%dd, %s stable ≤%dd, %s frozen):\n",
+		ageBandChars[0], bands.VolatileDays, ageBandChars[1], bands.ActiveDays, ageBandChars[2], bands.StableDays, ageBandChars[3])
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-40s  %5s  %7s  %-11s  %-8s  %s\n", "Directory", "Files", "Lines", "Last Change", "Class", "Lines by Age")
+	fmt.Println(strings.Repeat("-", 100))
+	printAgeRow := func(name string, d analysis.DirectoryAge) {
+		lastChange := "-"
+		if !d.LastModified.IsZero() {
+			lastChange = d.LastModified.Format("2006-01-02")
+		}
+		fmt.Printf("%-40s  %5d  %7d  %-11s  %-8s  %s\n", truncatePath(name, 40), d.Files, d.Lines, lastChange, d.Class, ageBar(d.Bands, 20))
+	}
+	printAgeRow(results.Overall.Path, results.Overall)
+	for _, d := range results.Directories {
+		parts := strings.Split(strings.TrimSuffix(d.Path, "/"), "/")
+		printAgeRow(strings.Repeat("  ", d.Depth)+parts[len(parts)-1]+"/", d)
+	}
+	fmt.Println(strings.Repeat("-", 100))
+	if results.Blame && results.Blamed < len(results.Files) {
+		fmt.Printf("Blamed the %d largest of %d files; the others are dated by their last change (age.max_blame).\n", results.Blamed, len(results.Files))
+	}
+
+	if ageFiles <= 0 {
+		return
+	}
+	files := append([]analysis.FileAge(nil), results.Files...)
+	sort.SliceStable(files, func(i, j int) bool { return files[i].LastModified.After(files[j].LastModified) })
+	fmt.Printf("\nYoungest Files:\n")
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-50s  %7s  %-11s  %11s  %-8s\n", "File", "Lines", "Last Change", "Median Line", "Class")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, f := range files[:min(ageFiles, len(files))] {
+		median := "-"
+		if f.Bands.Total() > 0 {
+			median = formatAge(f.MedianLineAge)
+		}
+		lastChange := "-"
+		if !f.LastModified.IsZero() {
+			lastChange = f.LastModified.Format("2006-01-02")
+		}
+		fmt.Printf("%-50s  %7d  %-11s  %11s  %-8s\n", truncatePath(f.Path, 50), f.Lines, lastChange, median, f.Class)
+	}
+	fmt.Println(strings.Repeat("-", 100))
+}
+
+// ageBar draws the shares of the age bands as a bar of width characters
+func ageBar(bands analysis.AgeBands, width int) string {
+	total := bands.Total()
+	if total == 0 {
+		return ""
+	}
+	var bar strings.Builder
+	drawn, seen := 0, 0
+	for i, n := range bands {
+		seen += n
+		// Round the cumulative share, so the bar always has the full width
+		end := (seen*width + total/2) / total
+		bar.WriteString(strings.Repeat(ageBandChars[i], end-drawn))
+		drawn = end
+	}
+	return bar.String()
+}
+
+// formatAge formats a duration in days, months or years
+func formatAge(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	switch {
+	case days < 60:
+		return fmt.Sprintf("%dd", days)
+	case days < 730:
+		return fmt.Sprintf("%dmo", days/30)
+	}
+	return fmt.Sprintf("%.1fy", float64(days)/365)
+}
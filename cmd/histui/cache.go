@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/cache"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var noCache bool
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear the analysis cache",
+	Long: `histui caches parsed commits and analyzer results in .git/` + cache.DirName + `.
+The cache is reused while the analyzed revision, load options, analyzer
+versions and settings are unchanged, and expires after 7 days.`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear [path]",
+	Short: "Remove the analysis cache",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runCacheClear,
+}
+
+var cacheInfoCmd = &cobra.Command{
+	Use:   "info [path]",
+	Short: "Show what the analysis cache contains",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runCacheInfo,
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Do not read or write the analysis cache")
+	cacheCmd.AddCommand(cacheClearCmd, cacheInfoCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// openCache returns the file cache of a repository
+func openCache(repo git.Repository) (*cache.FileCache, error) {
+	gitDir, err := repo.GetGitDir()
+	if err != nil {
+		return nil, err
+	}
+	return cache.NewFileCache(cache.Dir(gitDir), 0), nil
+}
+
+// analysisCache returns the cache analyses of a repository go through, or nil
+// with --no-cache
+func analysisCache(repo git.Repository) (cache.CacheManager, error) {
+	if noCache {
+		return nil, nil
+	}
+	return openCache(repo)
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	c, err := openCache(repo)
+	if err != nil {
+		return err
+	}
+	if err := c.Invalidate(); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Cleared %s\n", c.Dir())
+	return nil
+}
+
+func runCacheInfo(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	c, err := openCache(repo)
+	if err != nil {
+		return err
+	}
+
+	entries, err := c.Entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Printf("No cache at %s\n", c.Dir())
+		return nil
+	}
+	size, err := c.Size()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(strings.Repeat("-", 60))
+	fmt.Printf("Location:        %s\n", c.Dir())
+	fmt.Printf("Size:            %.1f KB\n", float64(size)/1024)
+	fmt.Printf("Entries:         %d\n", len(entries))
+	for _, meta := range entries {
+		// An entry is current if loading with its options now would hit it,
+		// which also checks the revisions its history excluded
+		state := "stale (history moved since)"
+		if key, err := analysis.CacheKey(repo, meta.Options); err != nil {
+			state = "stale (revision no longer exists)"
+		} else if c.IsValid(key) {
+			state = "current"
+		}
+
+		fmt.Println(strings.Repeat("-", 60))
+		fmt.Printf("Options:         %s\n", describeLoadOptions(meta.Options))
+		fmt.Printf("Built:           %s\n", meta.AnalysisTime.Format("2006-01-02 15:04:05"))
+		fmt.Printf("Revision:        %s\n", meta.LatestCommitSHA)
+		fmt.Printf("State:           %s\n", state)
+		fmt.Printf("Commits:         %d\n", meta.CommitCount)
+
+		names := make([]string, 0, len(meta.AnalyzerVersions))
+		for name := range meta.AnalyzerVersions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Println("Cached analyzer results:")
+		if len(names) == 0 {
+			fmt.Println("  (none)")
+		}
+		for _, name := range names {
+			entry := meta.AnalyzerVersions[name]
+			fmt.Printf("  %-20s v%-6s %s\n", name, entry.Version, entry.SavedAt.Format("2006-01-02 15:04:05"))
+		}
+	}
+	fmt.Println(strings.Repeat("-", 60))
+	return nil
+}
+
+// describeLoadOptions summarizes the options a cache entry was loaded with
+func describeLoadOptions(opts git.LoadOptions) string {
+	revision := opts.Branch
+	if revision == "" {
+		revision = "HEAD"
+	}
+	parts := []string{revision}
+	if len(opts.Exclude) > 0 {
+		parts = append(parts, "excluding "+strings.Join(opts.Exclude, ", "))
+	}
+	if opts.Since != nil {
+		parts = append(parts, "since "+opts.Since.Format("2006-01-02"))
+	}
+	if opts.Until != nil {
+		parts = append(parts, "until "+opts.Until.Format("2006-01-02"))
+	}
+	if opts.Author != "" {
+		parts = append(parts, "by "+opts.Author)
+	}
+	if opts.MaxCommits > 0 {
+		parts = append(parts, fmt.Sprintf("last %d commits", opts.MaxCommits))
+	}
+	if opts.IncludeMerges {
+		parts = append(parts, "with merges")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkRulesFile string
+	checkSince     string
+)
+
+// checkMinCoChanges is the number of co-changes a pair needs before limit
+// rules judge its score
+const checkMinCoChanges = 3
+
+var checkCmd = &cobra.Command{
+	Use:   "check [path]",
+	Short: "Check file coupling against architecture rules",
+	Long: `check evaluates the repository's file coupling against the architecture
+rules declared in ` + config.FileName + ` (or the file given with --rules) and
+exits with a non-zero status when any rule is violated, for use in CI. A
+single co-change breaks a forbid rule. With --since, only commits not
+reachable from the given ref are checked, so a violation fixed long ago does
+not fail every build ("histui check --since origin/main").`,
+	Args:          cobra.MaximumNArgs(1),
+	RunE:          runCheck,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	checkCmd.Flags().StringVar(&checkRulesFile, "rules", "", "Rules file (default: "+config.FileName+" in the repository)")
+	checkCmd.Flags().StringVar(&checkSince, "since", "", "Only check commits not reachable from this ref")
+	rootCmd.AddCommand(checkCmd)
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	var cfg config.Config
+	if checkRulesFile != "" {
+		cfg, err = config.LoadFile(checkRulesFile)
+	} else {
+		cfg, err = config.Load(repo.GetPath())
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Rules) == 0 {
+		return fmt.Errorf("no architecture rules defined")
+	}
+	if err := config.ValidateRules(cfg.Rules); err != nil {
+		return err
+	}
+
+	opts := loadOptionsFromFlags()
+	if checkSince != "" {
+		if _, err := repo.GetCommit(checkSince); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		opts.Exclude = []string{checkSince}
+	}
+	// Forbid rules see every co-change, however rare
+	settings := analysisSettings(repo, cfg)
+	settings.Coupling.MinCoChanges = 1
+	result, _, err := runAnalyzer(repo, settings, "coupling", opts)
+	if err != nil {
+		return err
+	}
+	couplingResults := *result.(*analysis.CouplingResults)
+	violations := analysis.EvaluateRules(couplingResults, cfg.Rules, checkMinCoChanges)
+
+	fmt.Printf("Checked %d coupled file pairs against %d rules\n", len(couplingResults.Pairs), len(cfg.Rules))
+
+	if len(violations) == 0 {
+		fmt.Println("✓ No architecture rule violations")
+		return nil
+	}
+
+	fmt.Println(strings.Repeat("-", 110))
+	currentRule := ""
+	for _, v := range violations {
+		if label := v.Rule.Label(); label != currentRule {
+			currentRule = label
+			fmt.Printf("\n✗ %s\n", label)
+		}
+		fmt.Printf("    %-35s  %-35s  %s\n",
+			truncatePath(v.Pair.FileA, 35),
+			truncatePath(v.Pair.FileB, 35),
+			v.Reason)
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 110))
+
+	return fmt.Errorf("%d architecture rule violation(s)", len(violations))
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	churnBy       string
+	churnTimezone string
+	churnPaths    []string
+	churnPeriods  int
+)
+
+// sparkLevels draw sparklines from the lowest value to the highest
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+var churnCmd = &cobra.Command{
+	Use:   "churn [path]",
+	Short: "Show commits, authors and lines changed over time",
+	Long: `churn buckets commits by day, week (ISO, starting on Monday), month or
+quarter and shows commits, authors, lines added and deleted and files touched
+per period, as sparklines and a table. Commits are bucketed by author time in
+their own timezone, unless --tz (or "timezone" in ` + config.FileName + `) names
+one. --path limits the series to files matching the patterns ("src/api/",
+"*.go"), following renames.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runChurn,
+}
+
+func init() {
+	churnCmd.Flags().StringVar(&churnBy, "by", "", "Period: "+strings.Join(analysis.ChurnGranularities, ", ")+" (default from the configuration)")
+	churnCmd.Flags().StringVar(&churnTimezone, "tz", "", `Timezone to bucket in: an IANA name or "local" (default: each commit's own)`)
+	churnCmd.Flags().StringSliceVarP(&churnPaths, "path", "p", nil, "Only count files matching these patterns")
+	churnCmd.Flags().IntVar(&churnPeriods, "periods", 26, "Number of latest periods to show (0 = all)")
+	registerRenderer("churn", renderChurn)
+	rootCmd.AddCommand(churnCmd)
+}
+
+func runChurn(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+	if churnBy != "" {
+		cfg.Churn.Granularity = churnBy
+	}
+	if churnTimezone != "" {
+		cfg.Churn.Timezone = churnTimezone
+	}
+
+	settings := analysisSettings(repo, cfg)
+	settings.Paths = churnPaths
+	result, commits, err := runAnalyzer(repo, settings, "churn", loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+	renderChurn(result, commits)
+	return nil
+}
+
+func renderChurn(result analysis.AnalysisResult, commits []git.Commit) {
+	results := result.(*analysis.ChurnResults)
+	if len(results.Periods) == 0 {
+		fmt.Println("No commits to chart.")
+		return
+	}
+
+	periods := results.Periods
+	if churnPeriods > 0 && len(periods) > churnPeriods {
+		periods = periods[len(periods)-churnPeriods:]
+	}
+
+	timezone := "each commit's timezone"
+	if results.Timezone != "" {
+		timezone = results.Timezone
+	}
+	scope := ""
+	if len(results.Paths) > 0 {
+		scope = " in " + strings.Join(results.Paths, ", ")
+	}
+	fmt.Printf("\nChurn by %s%s (%s, %s to %s):\n", results.Granularity, scope, timezone, periods[0].Label, periods[len(periods)-1].Label)
+	fmt.Println(strings.Repeat("-", 100))
+
+	series := []struct {
+		name  string
+		value func(analysis.ChurnPeriod) int
+	}{
+		{"Commits", func(p analysis.ChurnPeriod) int { return p.Commits }},
+		{"Authors", func(p analysis.ChurnPeriod) int { return p.Authors }},
+		{"Added", func(p analysis.ChurnPeriod) int { return p.Added }},
+		{"Deleted", func(p analysis.ChurnPeriod) int { return p.Deleted }},
+		{"Files", func(p analysis.ChurnPeriod) int { return p.Files }},
+	}
+	for _, s := range series {
+		values := make([]int, len(periods))
+		peak := 0
+		for i, p := range periods {
+			values[i] = s.value(p)
+			peak = max(peak, values[i])
+		}
+		fmt.Printf("%-8s  %s  max %d\n", s.name, sparkline(values), peak)
+	}
+	fmt.Println(strings.Repeat("-", 100))
+
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-10s  %7s  %7s  %8s  %8s  %6s  %s\n", "Period", "Commits", "Authors", "Added", "Deleted", "Files", "Churn")
+	fmt.Println(strings.Repeat("-", 100))
+	peak := 0
+	for _, p := range periods {
+		peak = max(peak, p.Churn())
+	}
+	for _, p := range periods {
+		fmt.Printf("%-10s  %7d  %7d  %8d  %8d  %6d  %s\n",
+			p.Label, p.Commits, p.Authors, p.Added, p.Deleted, p.Files, strings.Repeat("█", p.Churn()*40/max(peak, 1)))
+	}
+	fmt.Println(strings.Repeat("-", 100))
+	t := results.Total
+	fmt.Printf("%-10s  %7d  %7d  %8d  %8d  %6d\n", "Total", t.Commits, t.Authors, t.Added, t.Deleted, t.Files)
+}
+
+// sparkline draws values as a line of block characters scaled to the highest
+// value; zero is always the lowest block
+func sparkline(values []int) string {
+	peak := 0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	line := make([]rune, len(values))
+	for i, v := range values {
+		level := 0
+		if peak > 0 {
+			level = v * (len(sparkLevels) - 1) / peak
+			if v > 0 && level == 0 {
+				// Keep small values apart from empty periods
+				level = 1
+			}
+		}
+		line[i] = sparkLevels[level]
+	}
+	return string(line)
+}
@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []int
+		want   string
+	}{
+		{nil, ""},
+		{[]int{0, 0, 0}, "▁▁▁"},
+		{[]int{5}, "█"},
+		{[]int{0, 1, 7, 14}, "▁▂▄█"},
+		{[]int{1, 1000}, "▂█"},
+		{[]int{0, 1, 2, 3, 4, 5, 6, 7}, "▁▂▃▄▅▆▇█"},
+	}
+
+	for _, tt := range tests {
+		if got := sparkline(tt.values); got != tt.want {
+			t.Errorf("sparkline(%v) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	complexityRev   string
+	complexityTrend bool
+)
+
+var complexityCmd = &cobra.Command{
+	Use:   "complexity <file>...",
+	Short: "Estimate the complexity of files at a revision",
+	Long: `complexity estimates how complex files are: Go files are measured with
+go/ast (cyclomatic complexity), other languages from their indentation and
+branching keywords. With --trend, it shows how the complexity of a single file
+evolved over every commit that changed it.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runComplexity,
+}
+
+func init() {
+	complexityCmd.Flags().StringVar(&complexityRev, "rev", "HEAD", "Revision to measure")
+	complexityCmd.Flags().BoolVar(&complexityTrend, "trend", false, "Show the complexity of one file after every commit that changed it")
+	rootCmd.AddCommand(complexityCmd)
+}
+
+func runComplexity(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromFlags())
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	if complexityTrend {
+		if len(args) != 1 {
+			return fmt.Errorf("--trend takes exactly one file")
+		}
+		return printComplexityTrend(repo, args[0])
+	}
+
+	measured, err := analysis.ComplexityAt(repo, complexityRev, args)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Complexity at %s:\n", complexityRev)
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-45s  %6s  %10s  %10s  %9s  %10s  %-9s\n", "File", "Lines", "Mean Ind.", "Max Ind.", "Decisions", "Cyclomatic", "Method")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, path := range args {
+		c, ok := measured[path]
+		if !ok {
+			fmt.Printf("%-45s  (not a text file at %s)\n", truncatePath(path, 45), complexityRev)
+			continue
+		}
+		fmt.Printf("%-45s  %6d  %10.2f  %10d  %9d  %10d  %-9s\n",
+			truncatePath(path, 45),
+			c.Lines,
+			c.MeanIndent(),
+			c.MaxIndent,
+			c.Decisions,
+			c.Cyclomatic,
+			complexityMethod(c))
+	}
+	fmt.Println(strings.Repeat("-", 100))
+	return nil
+}
+
+// printComplexityTrend prints the complexity of a file after each commit that changed it
+func printComplexityTrend(repo git.Repository, path string) error {
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+	commits, err := loadCommits(repo, cfg, loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+
+	trend, err := analysis.ComplexityTrend(repo, commits, path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Complexity of %s over %d commits (%s):\n", path, len(trend), complexityMethod(trend[len(trend)-1].Complexity))
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-10s  %-8s  %-20s  %6s  %10s  %10s  %6s\n", "Date", "Commit", "Author", "Lines", "Mean Ind.", "Cyclomatic", "Delta")
+	fmt.Println(strings.Repeat("-", 100))
+	previous := 0
+	for i, p := range trend {
+		delta := ""
+		if i > 0 {
+			delta = fmt.Sprintf("%+d", p.Complexity.Cyclomatic-previous)
+		}
+		previous = p.Complexity.Cyclomatic
+
+		fmt.Printf("%-10s  %-8s  %-20s  %6d  %10.2f  %10d  %6s\n",
+			p.Timestamp.Format("2006-01-02"),
+			p.SHA[:min(7, len(p.SHA))],
+			truncatePath(p.Author, 20),
+			p.Complexity.Lines,
+			p.Complexity.MeanIndent(),
+			p.Complexity.Cyclomatic,
+			delta)
+	}
+	fmt.Println(strings.Repeat("-", 100))
+	return nil
+}
+
+// complexityMethod names how a complexity was computed
+func complexityMethod(c analysis.Complexity) string {
+	if c.Precise {
+		return "go/ast"
+	}
+	return "heuristic"
+}
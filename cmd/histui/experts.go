@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	expertsTopN    int
+	expertsNoBlame bool
+)
+
+var expertsCmd = &cobra.Command{
+	Use:   "experts <path>",
+	Short: "Rank who knows a file or directory best",
+	Long: `experts ranks the developers who know a file or directory (relative to the
+repository root) by their commits to it, weighted by how recent they are,
+the lines they changed and the lines they last changed today (git blame).
+Experts who have not committed anywhere for a while are flagged as inactive:
+their knowledge is a candidate for transfer. The half-life of commits and
+the inactivity period are configured in ` + config.FileName + `.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExperts,
+}
+
+func init() {
+	expertsCmd.Flags().IntVar(&expertsTopN, "top", 10, "Number of experts to show (0 = all)")
+	expertsCmd.Flags().BoolVar(&expertsNoBlame, "no-blame", false, "Skip git blame (faster)")
+	rootCmd.AddCommand(expertsCmd)
+}
+
+func runExperts(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromFlags())
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+
+	commits, err := loadCommits(repo, cfg, loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+
+	revision := branch
+	if revision == "" {
+		revision = "HEAD"
+	}
+	results, err := analysis.ExpertsAt(repo, revision, args[0], commits, ignoreFiles, cfg, expertsNoBlame)
+	if err != nil {
+		return err
+	}
+	if len(results.Experts) == 0 {
+		fmt.Printf("No history found for %s.\n", args[0])
+		return nil
+	}
+
+	fmt.Printf("\nExperts for %s (%d files", results.Path, results.Files)
+	if results.Blamed > 0 {
+		fmt.Printf(", %d blamed", results.Blamed)
+	}
+	fmt.Printf("):\n")
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-25s  %6s  %7s  %7s  %8s  %7s  %-11s  %s\n", "Author", "Score", "Commits", "Recency", "Lines", "Blamed", "Last Change", "Status")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, e := range results.Experts[:limit(expertsTopN, len(results.Experts))] {
+		lastChange := "-"
+		if !e.LastChange.IsZero() {
+			lastChange = e.LastChange.Format("2006-01-02")
+		}
+		status := "active"
+		switch {
+		case e.Inactive:
+			status = "inactive since " + e.LastActive.Format("2006-01")
+		case e.LastActive.IsZero():
+			status = "no commits in history"
+		}
+		fmt.Printf("%-25s  %5.0f%%  %7d  %7.1f  %8d  %7d  %-11s  %s\n",
+			truncatePath(e.Author, 25), e.Score*100, e.Commits, e.Recency, e.Lines, e.Blamed, lastChange, status)
+	}
+	fmt.Println(strings.Repeat("-", 100))
+
+	var inactive []string
+	for _, e := range results.Experts[:limit(expertsTopN, len(results.Experts))] {
+		if e.Inactive {
+			inactive = append(inactive, e.Author)
+		}
+	}
+	if len(inactive) > 0 {
+		fmt.Printf("\n⚠ Inactive experts, consider a knowledge transfer: %s\n", strings.Join(inactive, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"histui/internal/config"
+	"histui/internal/git"
+	"histui/internal/hooks"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	hookNames   []string
+	hookCommand string
+	hookForce   bool
+)
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Install or remove histui git hooks",
+	Long: `hooks manages the git hooks that run histui checks. Hooks are written to the
+directory git runs hooks from, honouring core.hooksPath. The checks each hook
+performs and whether they warn or block are configured in ` + config.FileName + `.`,
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install [path]",
+	Short: "Install histui git hooks",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runHooksInstall,
+}
+
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall [path]",
+	Short: "Remove histui git hooks and restore backed up hooks",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runHooksUninstall,
+}
+
+var hookCmd = &cobra.Command{
+	Use:    "hook",
+	Short:  "Entry points invoked by installed git hooks",
+	Hidden: true,
+}
+
+var hookRunCmd = &cobra.Command{
+	Use:           "run <name> [hook args...]",
+	Short:         "Run the checks configured for a git hook",
+	Args:          cobra.MinimumNArgs(1),
+	RunE:          runHook,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{hooksInstallCmd, hooksUninstallCmd} {
+		c.Flags().StringSliceVar(&hookNames, "hooks", hooks.Supported, "Hooks to manage")
+	}
+	hooksInstallCmd.Flags().StringVar(&hookCommand, "command", "histui", "Path of the histui executable the hooks run")
+	hooksInstallCmd.Flags().BoolVar(&hookForce, "force", false, "Back up and replace existing hooks not installed by histui")
+
+	hooksCmd.AddCommand(hooksInstallCmd, hooksUninstallCmd)
+	hookCmd.AddCommand(hookRunCmd)
+	rootCmd.AddCommand(hooksCmd, hookCmd)
+}
+
+func runHooksInstall(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	dir, err := repo.GetHooksDir()
+	if err != nil {
+		return err
+	}
+
+	if err := hooks.Install(dir, hookNames, hookCommand, hookForce); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Installed %s into %s\n", strings.Join(hookNames, ", "), dir)
+	return nil
+}
+
+func runHooksUninstall(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	dir, err := repo.GetHooksDir()
+	if err != nil {
+		return err
+	}
+
+	if err := hooks.Uninstall(dir, hookNames); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Removed histui hooks from %s\n", dir)
+	return nil
+}
+
+func runHook(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if !hooks.IsSupported(name) {
+		return fmt.Errorf("unsupported hook: %s", name)
+	}
+
+	// Git runs hooks from the root of the working tree
+	repo, err := git.NewCLIRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+
+	store, err := analysisCache(repo)
+	if err != nil {
+		return err
+	}
+	report, err := hooks.NewRunner(repo, cfg, store).Run(name, args[1:], os.Stdin)
+	if err != nil {
+		// Broken checks must not get in the way unless the hook is blocking;
+		// an unknown mode is reported as blocking so the typo gets fixed
+		if report.Mode == config.HookModeBlock {
+			return fmt.Errorf("histui %s: %w", name, err)
+		}
+		fmt.Fprintf(os.Stderr, "histui %s: %v\n", name, err)
+		return nil
+	}
+
+	if len(report.Findings) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "histui %s:\n", name)
+	for _, f := range report.Findings {
+		fmt.Fprintf(os.Stderr, "  - [%s] %s\n", f.Check, f.Message)
+	}
+
+	if report.Blocking() {
+		return fmt.Errorf("histui %s: %d problem(s) found, aborting (set mode to \"warn\" in %s to allow)",
+			name, len(report.Findings), config.FileName)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	hotspotsDays       int
+	hotspotsDepth      int
+	hotspotsTopN       int
+	hotspotsComplexity bool
+	hotspotsDefects    bool
+)
+
+var hotspotsCmd = &cobra.Command{
+	Use:   "hotspots [path]",
+	Short: "Rank files that change often and are large",
+	Long: `hotspots combines how often and how much each file changed (commits and
+lines added plus deleted) with its current line count, and ranks files by the
+resulting score. Large files that keep changing are where defects and
+maintenance cost concentrate.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHotspots,
+}
+
+func init() {
+	hotspotsCmd.Flags().IntVar(&hotspotsDays, "days", 0, "Only count changes from the last N days of history (0 = all)")
+	hotspotsCmd.Flags().IntVar(&hotspotsDepth, "depth", 0, "Roll files up into directories N levels deep (0 = files)")
+	hotspotsCmd.Flags().IntVar(&hotspotsTopN, "top", 20, "Number of hotspots to show (0 = all)")
+	hotspotsCmd.Flags().BoolVar(&hotspotsComplexity, "complexity", false, "Measure size by estimated complexity instead of line count")
+	hotspotsCmd.Flags().BoolVar(&hotspotsDefects, "defects", false, "Weight scores by defect density from bug-introducing commits (slower)")
+	registerRenderer("hotspots", renderHotspots)
+	rootCmd.AddCommand(hotspotsCmd)
+}
+
+func runHotspots(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+
+	settings := analysisSettings(repo, cfg)
+	settings.Hotspots = analysis.HotspotOptions{
+		Window:       time.Duration(hotspotsDays) * 24 * time.Hour,
+		Depth:        hotspotsDepth,
+		ByComplexity: hotspotsComplexity,
+		ByDefects:    hotspotsDefects,
+	}
+	result, commits, err := runAnalyzer(repo, settings, "hotspots", loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+	renderHotspots(result, commits)
+	return nil
+}
+
+func renderHotspots(result analysis.AnalysisResult, commits []git.Commit) {
+	results := result.(*analysis.HotspotResults)
+	if len(results.Hotspots) == 0 {
+		fmt.Println("No hotspots found.")
+		return
+	}
+
+	window := "all history"
+	if !results.Since.IsZero() {
+		window = "since " + results.Since.Format("2006-01-02")
+	}
+	unit := "Files"
+	if results.Depth > 0 {
+		unit = "Directories"
+	}
+
+	n := limit(hotspotsTopN, len(results.Hotspots))
+	width := 100
+	if results.ByDefects {
+		width += 18
+	}
+	fmt.Printf("\nTop %d Hotspot %s (%s):\n", n, unit, window)
+	fmt.Println(strings.Repeat("-", width))
+	sizeColumn := "Lines"
+	if results.ByComplexity {
+		sizeColumn = "Complex."
+	}
+	defectColumn := ""
+	if results.ByDefects {
+		defectColumn = fmt.Sprintf("  %7s  %7s", "Defects", "/KLOC")
+	}
+	fmt.Printf("%-3s  %-50s  %7s  %8s  %8s  %5s%s\n", "#", "Path", "Changes", "Churn", sizeColumn, "Score", defectColumn)
+	fmt.Println(strings.Repeat("-", width))
+	for i, h := range results.Hotspots[:n] {
+		size := h.Lines
+		if results.ByComplexity {
+			size = h.Complexity
+		}
+		defects := ""
+		if results.ByDefects {
+			defects = fmt.Sprintf("  %7d  %7.1f", h.Defects, h.Density)
+		}
+		fmt.Printf("%-3d  %-50s  %7d  %8d  %8d  %5.2f%s\n",
+			i+1,
+			truncatePath(h.Path, 50),
+			h.Changes,
+			h.Churn,
+			size,
+			h.Score,
+			defects)
+	}
+	fmt.Println(strings.Repeat("-", width))
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	impactFromStdin bool
+	impactMinScore  float64
+	impactTopN      int
+	impactStrict    bool
+)
+
+var impactCmd = &cobra.Command{
+	Use:   "impact [path]",
+	Short: "Suggest files that usually change with the staged files",
+	Long: `impact compares the staged files (git diff --cached --name-only), or a list
+of paths read from stdin, against the repository's file coupling history and
+reports files that historically change together with them but are not part
+of the change set.`,
+	Args:          cobra.MaximumNArgs(1),
+	RunE:          runImpact,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	impactCmd.Flags().BoolVar(&impactFromStdin, "stdin", false, "Read changed paths from stdin (one per line) instead of the index")
+	impactCmd.Flags().Float64Var(&impactMinScore, "min-score", 0.5, "Minimum coupling score for a suggestion")
+	impactCmd.Flags().IntVar(&impactTopN, "top", 10, "Maximum number of suggestions to show (0 = all)")
+	impactCmd.Flags().BoolVar(&impactStrict, "strict", false, "Exit with a non-zero status when suggestions are found")
+	rootCmd.AddCommand(impactCmd)
+}
+
+func runImpact(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	var changed []string
+	if impactFromStdin {
+		changed, err = readPaths(os.Stdin)
+	} else {
+		changed, err = repo.GetStagedFiles()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read changed files: %w", err)
+	}
+
+	if len(changed) == 0 {
+		fmt.Println("No changed files to check.")
+		return nil
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+	result, _, err := runAnalyzer(repo, analysisSettings(repo, cfg), "coupling", loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+	suggestions := analysis.SuggestMissingFiles(*result.(*analysis.CouplingResults), changed, impactMinScore)
+
+	if len(suggestions) == 0 {
+		fmt.Printf("✓ No missing coupled files for %d changed file(s)\n", len(changed))
+		return nil
+	}
+
+	fmt.Printf("Files that usually change with your %d changed file(s):\n", len(changed))
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-3s  %-40s  %-6s  %-5s  %-40s\n", "#", "Missing File", "Score", "Co-ch", "Coupled With")
+	fmt.Println(strings.Repeat("-", 100))
+
+	for i := 0; i < limit(impactTopN, len(suggestions)); i++ {
+		s := suggestions[i]
+		fmt.Printf("%-3d  %-40s  %6.2f  %5d  %-40s\n",
+			i+1,
+			truncatePath(s.File, 40),
+			s.Confidence,
+			s.CoChanges,
+			truncatePath(strings.Join(s.CoupledWith, ", "), 40))
+	}
+	fmt.Println(strings.Repeat("-", 100))
+
+	if impactStrict {
+		return fmt.Errorf("%d coupled file(s) missing from the change set", len(suggestions))
+	}
+	return nil
+}
+
+// readPaths reads newline-separated file paths, skipping blank lines
+func readPaths(f *os.File) ([]string, error) {
+	var paths []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths, scanner.Err()
+}
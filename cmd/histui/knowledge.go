@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	knowledgeBlame        bool
+	knowledgeOrphanMonths int
+	knowledgeDepth        int
+	knowledgeTopN         int
+	knowledgeShowFiles    bool
+)
+
+var knowledgeCmd = &cobra.Command{
+	Use:   "knowledge [path]",
+	Short: "Report bus factor and knowledge loss",
+	Long: `knowledge measures who knows which files from the lines each author
+added over the history (or, with --blame, the lines each author owns today).
+It reports the bus factor of the repository and of each module: how many
+people would have to leave before more than half of the files have nobody
+left who knows them. Files whose owners have not committed for a while are
+listed as orphaned knowledge. Defaults are configured in ` + config.FileName + `.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runKnowledge,
+}
+
+func init() {
+	knowledgeCmd.Flags().BoolVar(&knowledgeBlame, "blame", false, "Measure authorship with git blame (slower)")
+	knowledgeCmd.Flags().IntVar(&knowledgeOrphanMonths, "orphan-months", 0, "Months without commits after which knowledge counts as lost (default from config)")
+	knowledgeCmd.Flags().IntVar(&knowledgeDepth, "depth", 1, "Directory depth of the module breakdown")
+	knowledgeCmd.Flags().IntVar(&knowledgeTopN, "top", 20, "Maximum number of rows per section (0 = all)")
+	knowledgeCmd.Flags().BoolVar(&knowledgeShowFiles, "files", false, "Also list the knowledge distribution of every file")
+	registerRenderer("knowledge", renderKnowledge)
+	rootCmd.AddCommand(knowledgeCmd)
+}
+
+func runKnowledge(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+	if knowledgeBlame {
+		cfg.Knowledge.Blame = true
+	}
+	if knowledgeOrphanMonths > 0 {
+		cfg.Knowledge.OrphanMonths = knowledgeOrphanMonths
+	}
+
+	settings := analysisSettings(repo, cfg)
+	settings.Depth = knowledgeDepth
+	result, commits, err := runAnalyzer(repo, settings, "knowledge", loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+	renderKnowledge(result, commits)
+	return nil
+}
+
+func renderKnowledge(result analysis.AnalysisResult, commits []git.Commit) {
+	results := result.(*analysis.KnowledgeResults)
+	if len(results.Files) == 0 {
+		fmt.Println("No files with known authors.")
+		return
+	}
+
+	source := "lines added in history"
+	if results.Blame {
+		source = "git blame"
+	}
+	fmt.Printf("\nKnowledge Distribution (%d files, from %s):\n", len(results.Files), source)
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("Bus Factor:  %d", results.BusFactor)
+	if len(results.KeyPeople) > 0 {
+		fmt.Printf(" (%s)", strings.Join(results.KeyPeople, ", "))
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 100))
+
+	fmt.Printf("\nModules:\n")
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-30s  %5s  %6s  %-25s  %5s  %3s  %-15s\n", "Module", "Files", "People", "Top Author", "Share", "BF", "Key People")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, d := range results.Directories[:limit(knowledgeTopN, len(results.Directories))] {
+		fmt.Printf("%-30s  %5d  %6d  %-25s  %4.0f%%  %3d  %-15s\n",
+			truncatePath(d.Path, 30),
+			d.Files,
+			d.Contributors,
+			truncatePath(d.Top.Author, 25),
+			d.Top.Share*100,
+			d.BusFactor,
+			strings.Join(d.KeyPeople, ", "))
+	}
+	fmt.Println(strings.Repeat("-", 100))
+
+	if knowledgeShowFiles {
+		fmt.Printf("\nFiles:\n")
+		fmt.Println(strings.Repeat("-", 100))
+		fmt.Printf("%-45s  %-25s  %5s  %7s  %-10s\n", "File", "Top Author", "Share", "Authors", "Owners")
+		fmt.Println(strings.Repeat("-", 100))
+		for _, f := range results.Files[:limit(knowledgeTopN, len(results.Files))] {
+			fmt.Printf("%-45s  %-25s  %4.0f%%  %7d  %-10s\n",
+				truncatePath(f.Path, 45),
+				truncatePath(f.Authors[0].Author, 25),
+				f.Authors[0].Share*100,
+				len(f.Authors),
+				strings.Join(f.Owners, ", "))
+		}
+		fmt.Println(strings.Repeat("-", 100))
+	}
+
+	months := int(results.OrphanAfter / (30 * 24 * time.Hour))
+	if len(results.Orphaned) == 0 {
+		fmt.Printf("\n✓ No orphaned files (owners inactive for more than %d months)\n", months)
+		return
+	}
+	fmt.Printf("\nOrphaned Knowledge (%d files whose owners have not committed for %d months):\n", len(results.Orphaned), months)
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-50s  %-30s  %-12s\n", "File", "Owners", "Last Active")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, f := range results.Orphaned[:limit(knowledgeTopN, len(results.Orphaned))] {
+		fmt.Printf("%-50s  %-30s  %-12s\n",
+			truncatePath(f.Path, 50),
+			truncatePath(strings.Join(f.Owners, ", "), 30),
+			f.LastActive.Format("2006-01-02"))
+	}
+	fmt.Println(strings.Repeat("-", 100))
+}
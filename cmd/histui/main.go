@@ -3,10 +3,12 @@ package main
 import (
 	"fmt"
 	"os"
+	"runtime"
 	"strings"
 	"time"
 
 	"histui/internal/analysis"
+	"histui/internal/config"
 	"histui/internal/git"
 
 	"github.com/spf13/cobra"
@@ -20,6 +22,10 @@ var (
 	includeMerges bool
 	showCoupling  bool
 	ignoreFiles   []string
+
+	couplingWorkers int
+	spillThreshold  int
+	extraAnalyzers  []string
 )
 
 var rootCmd = &cobra.Command{
@@ -32,20 +38,108 @@ insights, and potential issues in your codebase.`,
 }
 
 func init() {
-	rootCmd.Flags().IntVarP(&maxCommits, "max-commits", "n", 0, "Maximum number of commits to analyze (0 = unlimited)")
-	rootCmd.Flags().StringVarP(&branch, "branch", "b", "", "Analyze specific branch (default: all branches)")
-	rootCmd.Flags().StringVarP(&author, "author", "a", "", "Filter commits by author")
-	rootCmd.Flags().BoolVarP(&includeMerges, "include-merges", "m", false, "Include merge commits in analysis")
+	// Commit loading flags are shared by all subcommands
+	rootCmd.PersistentFlags().StringVarP(&repoPath, "repo", "r", "", "Repository path, for commands whose argument is not one (default: current directory)")
+	rootCmd.PersistentFlags().IntVarP(&maxCommits, "max-commits", "n", 0, "Maximum number of commits to analyze (0 = unlimited)")
+	rootCmd.PersistentFlags().StringVarP(&branch, "branch", "b", "", "Analyze specific branch (default: all branches)")
+	rootCmd.PersistentFlags().StringVarP(&author, "author", "a", "", "Filter commits by author")
+	rootCmd.PersistentFlags().BoolVarP(&includeMerges, "include-merges", "m", false, "Include merge commits in analysis")
+	rootCmd.PersistentFlags().StringSliceVarP(&ignoreFiles, "ignore", "i", []string{"*.md", "*.txt", "*.json", "*.yaml", "*.yml"}, "File patterns to ignore in coupling analysis")
+	rootCmd.PersistentFlags().IntVar(&couplingWorkers, "workers", runtime.NumCPU(), "Parallel workers for coupling analysis")
+	rootCmd.PersistentFlags().IntVar(&spillThreshold, "spill-threshold", 0, "File pairs held in memory per worker before spilling to disk (0 = never)")
 	rootCmd.Flags().BoolVarP(&showCoupling, "coupling", "c", false, "Show file coupling analysis")
-	rootCmd.Flags().StringSliceVarP(&ignoreFiles, "ignore", "i", []string{"*.md", "*.txt", "*.json", "*.yaml", "*.yml"}, "File patterns to ignore in coupling analysis")
+	rootCmd.Flags().StringSliceVar(&extraAnalyzers, "analyzers", nil,
+		"Additional analyzers to run ("+strings.Join(analysis.DefaultRegistry.Names(), ", ")+")")
+
+	registerRenderer("stats", renderStats)
+	registerRenderer("coupling", renderCoupling)
 }
 
-func runAnalysis(cmd *cobra.Command, args []string) error {
-	// Determine repository path
-	path := "."
+// repoPathFromArgs returns the repository path given as the first positional
+// argument, or else with --repo
+func repoPathFromArgs(args []string) string {
 	if len(args) > 0 {
-		path = strings.Trim(args[0], "\"'")
+		return strings.Trim(args[0], "\"'")
+	}
+	return repoPathFromFlags()
+}
+
+// repoPathFromFlags returns the repository path given with --repo (default ".")
+func repoPathFromFlags() string {
+	if repoPath != "" {
+		return repoPath
+	}
+	return "."
+}
+
+// loadOptionsFromFlags builds commit load options from the shared command-line flags
+func loadOptionsFromFlags() git.LoadOptions {
+	return git.LoadOptions{
+		Branch:        branch,
+		Author:        author,
+		MaxCommits:    maxCommits,
+		IncludeMerges: includeMerges,
+	}
+}
+
+// runAnalyzer runs a registered analyzer over the commits selected by opts,
+// through the analysis cache, and returns its result along with the commits
+func runAnalyzer(repo git.Repository, settings analysis.Settings, name string, opts git.LoadOptions) (analysis.AnalysisResult, []git.Commit, error) {
+	coordinator, err := newCoordinator(repo, settings, []string{name})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := coordinator.Run(opts); err != nil {
+		return nil, nil, err
+	}
+	result, _ := coordinator.Result(name)
+	return result, coordinator.Commits(), nil
+}
+
+// loadCommits loads the commits selected by opts, with their file stats,
+// through the analysis cache
+func loadCommits(repo git.Repository, cfg config.Config, opts git.LoadOptions) ([]git.Commit, error) {
+	// Loading file stats shares the cached commits with the analyzers
+	opts.IncludeFileStats = true
+	coordinator, err := newCoordinator(repo, analysisSettings(repo, cfg), nil)
+	if err != nil {
+		return nil, err
 	}
+	if err := coordinator.Run(opts); err != nil {
+		return nil, err
+	}
+	return coordinator.Commits(), nil
+}
+
+// newCoordinator creates a coordinator running the named analyzers, with the
+// analysis cache unless --no-cache is given
+func newCoordinator(repo git.Repository, settings analysis.Settings, names []string) (*analysis.AnalysisCoordinator, error) {
+	store, err := analysisCache(repo)
+	if err != nil {
+		return nil, err
+	}
+	return analysis.NewCoordinator(repo, analysis.DefaultRegistry, settings, names, store)
+}
+
+// truncatePath shortens a path to width characters, keeping its end
+func truncatePath(path string, width int) string {
+	if len(path) > width {
+		return "..." + path[len(path)-(width-3):]
+	}
+	return path
+}
+
+// limit returns how many of length items to show for a --top value (0 = all)
+func limit(top, length int) int {
+	if top <= 0 {
+		return length
+	}
+	return min(top, length)
+}
+
+func runAnalysis(cmd *cobra.Command, args []string) error {
+	// Determine repository path
+	path := repoPathFromArgs(args)
 
 	// Open repository
 	fmt.Printf("Opening repository at: %s\n", path)
@@ -78,34 +172,106 @@ func runAnalysis(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Latest Commit:   %s\n", latestSHA[:7])
 	fmt.Println(strings.Repeat("═", 60) + "\n")
 
-	// Load commits (fast - metadata only, no diff computation)
-	fmt.Println("Loading commits...")
-	startTime := time.Now()
+	// Select analyzers: statistics always, coupling on request, plus any extras
+	names := []string{"stats"}
+	if showCoupling {
+		names = append(names, "coupling")
+	}
+	names = append(names, extraAnalyzers...)
 
-	opts := git.LoadOptions{
-		Branch:        branch,
-		Author:        author,
-		MaxCommits:    maxCommits,
-		IncludeMerges: includeMerges,
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
 	}
 
-	commits, totalFiles, totalIns, totalDel, err := repo.LoadCommits(opts)
+	coordinator, err := newCoordinator(repo, analysisSettings(repo, cfg), names)
 	if err != nil {
-		return fmt.Errorf("failed to load commits: %w", err)
+		return err
 	}
 
-	loadDuration := time.Since(startTime)
+	// Load commits once and run all analyzers over them
+	startTime := time.Now()
+	coordinator.OnProgress = func(status analysis.AnalysisStatus) {
+		switch status.Phase {
+		case analysis.PhaseLoadingCommits:
+			fmt.Println("Loading commits...")
+		case analysis.PhaseRunningAnalysis:
+			if status.Progress == 0 {
+				source := ""
+				switch coordinator.LoadMode() {
+				case analysis.LoadCached:
+					source = " (loaded from cache)"
+				case analysis.LoadIncremental:
+					source = fmt.Sprintf(" (+%d new since cache)", coordinator.NewCommits())
+				case analysis.LoadRewritten:
+					source = " (history rewritten, full rebuild)"
+				}
+				fmt.Printf("✓ Loaded %d commits in %v%s\n", len(coordinator.Commits()), time.Since(startTime), source)
+				fmt.Printf("Running %d analyzers...\n", len(names))
+			} else {
+				source := ""
+				if status.FromCache {
+					source = " (cached)"
+				} else if status.Updated {
+					source = " (updated)"
+				}
+				fmt.Printf("  [%3.0f%%] %s done%s\n", status.Progress*100, status.CurrentStep, source)
+			}
+		}
+	}
 
-	// Display commit summary
-	fmt.Printf("✓ Loaded %d commits in %v\n\n", len(commits), loadDuration)
+	if err := coordinator.Run(loadOptionsFromFlags()); err != nil {
+		return err
+	}
+	fmt.Println()
 
+	commits := coordinator.Commits()
 	if len(commits) == 0 {
 		fmt.Println("No commits found matching the filters.")
 		return nil
 	}
 
-	// Calculate statistics from commit metadata (instant)
-	stats := calculateStats(commits)
+	// Render each result with its dedicated renderer, or its summary
+	for _, a := range coordinator.Analyzers() {
+		result, _ := coordinator.Result(a.Name())
+		if render, ok := renderers[result.Type()]; ok {
+			render(result, commits)
+		} else {
+			fmt.Printf("\n%s: %s\n", a.Name(), result.Summary())
+		}
+	}
+
+	return nil
+}
+
+// renderer prints an analysis result in full
+type renderer func(result analysis.AnalysisResult, commits []git.Commit)
+
+// renderers maps result types to their renderers; each command registers the
+// renderer of its analyzer
+var renderers = make(map[string]renderer)
+
+// registerRenderer registers the renderer of a result type
+func registerRenderer(resultType string, render renderer) {
+	renderers[resultType] = render
+}
+
+// analysisSettings builds analyzer settings from the shared command-line flags
+func analysisSettings(repo git.Repository, cfg config.Config) analysis.Settings {
+	return analysis.Settings{
+		IgnorePatterns: ignoreFiles,
+		Coupling: analysis.CouplingOptions{
+			Workers:        couplingWorkers,
+			SpillThreshold: spillThreshold,
+		},
+		Config:   cfg,
+		Repo:     repo,
+		Revision: branch,
+	}
+}
+
+func renderStats(result analysis.AnalysisResult, commits []git.Commit) {
+	stats := result.(*analysis.RepositoryStats)
 
 	fmt.Println("Repository Statistics:")
 	fmt.Println(strings.Repeat("-", 60))
@@ -115,12 +281,11 @@ func runAnalysis(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Contributors:    %d\n", len(stats.Authors))
 	fmt.Printf("Merge Commits:   %d (%.1f%%)\n",
 		stats.MergeCommits,
-		float64(stats.MergeCommits)/float64(len(commits))*100)
+		float64(stats.MergeCommits)/float64(stats.Commits)*100)
 
-	// Wait for file stats (running in background since before commit load)
-	fmt.Printf("Files Changed:   %d\n", totalFiles)
-	fmt.Printf("Lines Added:     %d\n", totalIns)
-	fmt.Printf("Lines Deleted:   %d\n", totalDel)
+	fmt.Printf("Files Changed:   %d\n", stats.TotalFilesChanged)
+	fmt.Printf("Lines Added:     %d\n", stats.TotalInsertions)
+	fmt.Printf("Lines Deleted:   %d\n", stats.TotalDeletions)
 
 	fmt.Println(strings.Repeat("-", 60))
 
@@ -131,7 +296,7 @@ func runAnalysis(cmd *cobra.Command, args []string) error {
 			i+1,
 			author.Name,
 			author.Count,
-			float64(author.Count)/float64(len(commits))*100)
+			float64(author.Count)/float64(stats.Commits)*100)
 	}
 
 	// Display recent commits
@@ -143,110 +308,43 @@ func runAnalysis(cmd *cobra.Command, args []string) error {
 			c.Author.Name,
 			c.Subject)
 	}
-
-	// Analyze file coupling (only if flag is set)
-	if showCoupling {
-		fmt.Println("\n" + strings.Repeat("═", 60))
-		fmt.Println("File Coupling Analysis")
-		fmt.Println(strings.Repeat("═", 60))
-		fmt.Println("Analyzing file change patterns...")
-
-		couplingResults := analysis.AnalyzeFileCoupling(commits, ignoreFiles)
-
-		if len(couplingResults.Pairs) == 0 {
-			fmt.Println("No file coupling detected (all commits modify single files)")
-		} else {
-			fmt.Printf("\nTop 10 Strongly Coupled File Pairs:\n")
-			fmt.Println(strings.Repeat("-", 110))
-			fmt.Printf("%-3s  %-35s  %-35s  %-6s  %-4s  %-8s\n",
-				"#", "File A", "File B", "Score", "Co-ch", "Strength")
-			fmt.Println(strings.Repeat("-", 110))
-
-			topN := min(10, len(couplingResults.Pairs))
-			for i := 0; i < topN; i++ {
-				pair := couplingResults.Pairs[i]
-				strength := analysis.GetCouplingStrength(pair.ScoreValue)
-
-				// Truncate if too long
-				fileA := pair.FileA
-				fileB := pair.FileB
-				if len(fileA) > 35 {
-					fileA = "..." + fileA[len(fileA)-32:]
-				}
-				if len(fileB) > 35 {
-					fileB = "..." + fileB[len(fileB)-32:]
-				}
-
-				fmt.Printf("%-3d  %-35s  %-35s  %6.2f  %4d  %-8s\n",
-					i+1, fileA, fileB, pair.ScoreValue, pair.CoChanges, strength)
-			}
-
-			fmt.Println(strings.Repeat("-", 110))
-
-			fmt.Printf("Total file pairs analyzed: %d\n", len(couplingResults.Pairs))
-			fmt.Println(strings.Repeat("-", 80))
-		}
-	}
-
-	return nil
 }
 
-type RepositoryStats struct {
-	FirstCommit       time.Time
-	LastCommit        time.Time
-	Authors           map[string]int
-	TopAuthors        []AuthorStat
-	TotalFilesChanged int
-	TotalInsertions   int
-	TotalDeletions    int
-	MergeCommits      int
-}
+func renderCoupling(result analysis.AnalysisResult, commits []git.Commit) {
+	couplingResults := result.(*analysis.CouplingResults)
 
-type AuthorStat struct {
-	Name  string
-	Count int
-}
+	fmt.Println("\n" + strings.Repeat("═", 60))
+	fmt.Println("File Coupling Analysis")
+	fmt.Println(strings.Repeat("═", 60))
 
-func calculateStats(commits []git.Commit) RepositoryStats {
-	stats := RepositoryStats{
-		Authors: make(map[string]int),
+	if len(couplingResults.Pairs) == 0 {
+		fmt.Println("No file coupling detected (all commits modify single files)")
+		return
 	}
 
-	if len(commits) == 0 {
-		return stats
-	}
+	fmt.Printf("\nTop 10 Strongly Coupled File Pairs:\n")
+	fmt.Println(strings.Repeat("-", 110))
+	fmt.Printf("%-3s  %-35s  %-35s  %-6s  %-4s  %-8s\n",
+		"#", "File A", "File B", "Score", "Co-ch", "Strength")
+	fmt.Println(strings.Repeat("-", 110))
 
-	stats.FirstCommit = commits[len(commits)-1].Timestamp
-	stats.LastCommit = commits[0].Timestamp
+	topN := min(10, len(couplingResults.Pairs))
+	for i := 0; i < topN; i++ {
+		pair := couplingResults.Pairs[i]
+		strength := analysis.GetCouplingStrength(pair.ScoreValue)
 
-	for _, c := range commits {
-		stats.Authors[c.Author.Name]++
-		stats.TotalFilesChanged += c.Stats.FilesChanged
-		stats.TotalInsertions += c.Stats.Insertions
-		stats.TotalDeletions += c.Stats.Deletions
-		if c.IsMerge {
-			stats.MergeCommits++
-		}
-	}
+		// Truncate if too long
+		fileA := truncatePath(pair.FileA, 35)
+		fileB := truncatePath(pair.FileB, 35)
 
-	// Create sorted author list
-	for name, count := range stats.Authors {
-		stats.TopAuthors = append(stats.TopAuthors, AuthorStat{
-			Name:  name,
-			Count: count,
-		})
+		fmt.Printf("%-3d  %-35s  %-35s  %6.2f  %4d  %-8s\n",
+			i+1, fileA, fileB, pair.ScoreValue, pair.CoChanges, strength)
 	}
 
-	// Sort by count (descending)
-	for i := 0; i < len(stats.TopAuthors); i++ {
-		for j := i + 1; j < len(stats.TopAuthors); j++ {
-			if stats.TopAuthors[j].Count > stats.TopAuthors[i].Count {
-				stats.TopAuthors[i], stats.TopAuthors[j] = stats.TopAuthors[j], stats.TopAuthors[i]
-			}
-		}
-	}
+	fmt.Println(strings.Repeat("-", 110))
 
-	return stats
+	fmt.Printf("Total file pairs analyzed: %d\n", len(couplingResults.Pairs))
+	fmt.Println(strings.Repeat("-", 80))
 }
 
 func min(a, b int) int {
@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestRepoPathFromArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		flag string
+		want string
+	}{
+		{"default", nil, "", "."},
+		{"flag", nil, "/src/app", "/src/app"},
+		{"argument", []string{"../other"}, "", "../other"},
+		{"argument wins over flag", []string{"../other"}, "/src/app", "../other"},
+		{"quoted argument", []string{`"my repo"`}, "", "my repo"},
+	}
+
+	defer func(saved string) { repoPath = saved }(repoPath)
+	for _, tt := range tests {
+		repoPath = tt.flag
+		if got := repoPathFromArgs(tt.args); got != tt.want {
+			t.Errorf("%s: repoPathFromArgs(%q) with --repo %q = %q, want %q", tt.name, tt.args, tt.flag, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	messagesTopN   int
+	messagesMonths int
+)
+
+var messagesCmd = &cobra.Command{
+	Use:   "messages [path]",
+	Short: "Score the quality of commit messages",
+	Long: `messages scores every commit message from 0 to 100: subjects that are too
+short, too long, meaningless ("wip", "fix") or not in the imperative mood, and
+large diffs without a body explaining them all cost points. Conventional
+Commits and issue references are reported, and cost points when required by
+the "messages" rules in ` + config.FileName + `. Merges, reverts and fixup!
+commits are not scored.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMessages,
+}
+
+func init() {
+	messagesCmd.Flags().IntVar(&messagesTopN, "top", 10, "Number of worst messages to show (0 = all kept)")
+	messagesCmd.Flags().IntVar(&messagesMonths, "months", 12, "Months shown in the trend (0 = all)")
+	registerRenderer("messagequality", renderMessageQuality)
+	rootCmd.AddCommand(messagesCmd)
+}
+
+func runMessages(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+
+	result, commits, err := runAnalyzer(repo, analysisSettings(repo, cfg), "messagequality", loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+	renderMessageQuality(result, commits)
+	return nil
+}
+
+func renderMessageQuality(result analysis.AnalysisResult, commits []git.Commit) {
+	results := result.(*analysis.MessageQualityResults)
+	if results.Overall.Commits == 0 {
+		fmt.Println("No commit messages to score.")
+		return
+	}
+
+	o := results.Overall
+	fmt.Printf("\nCommit Message Quality: average %.0f/100 over %d messages (%d merges, reverts and fixups not scored)\n",
+		o.Average, o.Commits, results.Exempt)
+	fmt.Printf("Conventional Commits: %.0f%%, issue references: %.0f%%\n", o.Conventional*100, o.IssueRefs*100)
+
+	if len(results.Problems) > 0 {
+		checks := make([]string, 0, len(results.Problems))
+		for check := range results.Problems {
+			checks = append(checks, check)
+		}
+		sort.Slice(checks, func(i, j int) bool {
+			if results.Problems[checks[i]] != results.Problems[checks[j]] {
+				return results.Problems[checks[i]] > results.Problems[checks[j]]
+			}
+			return checks[i] < checks[j]
+		})
+
+		fmt.Printf("\nProblems:\n")
+		fmt.Println(strings.Repeat("-", 40))
+		for _, check := range checks {
+			n := results.Problems[check]
+			fmt.Printf("%-15s  %7d  %5.0f%%\n", check, n, float64(n)/float64(o.Commits)*100)
+		}
+		fmt.Println(strings.Repeat("-", 40))
+	}
+
+	fmt.Printf("\nBy Author:\n")
+	printMessageGroups("Author", results.Authors[:min(10, len(results.Authors))])
+
+	trend := results.Trend
+	if messagesMonths > 0 && len(trend) > messagesMonths {
+		trend = trend[len(trend)-messagesMonths:]
+	}
+	fmt.Printf("\nMonthly Trend:\n")
+	printMessageGroups("Month", trend)
+
+	if len(results.Worst) == 0 {
+		fmt.Println("\n✓ No message has problems")
+		return
+	}
+	fmt.Printf("\nWorst Messages:\n")
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-8s  %5s  %-20s  %-40s  %s\n", "Commit", "Score", "Author", "Subject", "Problems")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, m := range results.Worst[:limit(messagesTopN, len(results.Worst))] {
+		checks := make([]string, len(m.Problems))
+		for i, p := range m.Problems {
+			checks[i] = p.Check
+		}
+		fmt.Printf("%-8s  %5d  %-20s  %-40s  %s\n",
+			m.ShortSHA, m.Score, truncatePath(m.Author, 20), truncateSubject(m.Subject, 40), strings.Join(checks, ", "))
+	}
+	fmt.Println(strings.Repeat("-", 100))
+}
+
+// printMessageGroups prints a table of message quality aggregates
+func printMessageGroups(label string, groups []analysis.MessageQualityGroup) {
+	fmt.Println(strings.Repeat("-", 70))
+	fmt.Printf("%-30s  %7s  %5s  %12s  %9s\n", label, "Commits", "Score", "Conventional", "Issue Ref")
+	fmt.Println(strings.Repeat("-", 70))
+	for _, g := range groups {
+		fmt.Printf("%-30s  %7d  %5.0f  %11.0f%%  %8.0f%%\n",
+			truncatePath(g.Name, 30), g.Commits, g.Average, g.Conventional*100, g.IssueRefs*100)
+	}
+	fmt.Println(strings.Repeat("-", 70))
+}
+
+// truncateSubject shortens a commit subject to width characters, keeping its start
+func truncateSubject(subject string, width int) string {
+	if len(subject) > width {
+		return subject[:width-3] + "..."
+	}
+	return subject
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	networkFormat string
+	networkWindow int
+	networkTopN   int
+)
+
+var networkCmd = &cobra.Command{
+	Use:     "network [path]",
+	Aliases: []string{"collaboration"},
+	Short:   "Show who works with whom: communities, connectors and isolated contributors",
+	Long: `network connects authors who changed the same files within a time window of
+each other, and the authors and co-authors (Co-authored-by trailers) of a
+commit. It splits the network into communities of authors who mostly work
+together, shows the modules each community works on, and ranks the
+connectors whose loss would cut others apart. Compare the communities with
+the architecture and the teams you have.
+
+Use --format dot or --format json to export the network for visualization,
+e.g. "histui network --format dot | dot -Tsvg > network.svg". The window and
+thresholds are configured in ` + config.FileName + ` ("network").`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runNetwork,
+}
+
+func init() {
+	networkCmd.Flags().StringVar(&networkFormat, "format", "table", "Output format: table, dot or json")
+	networkCmd.Flags().IntVar(&networkWindow, "window", 0, "Days between changes to a file that connect their authors (default from config)")
+	networkCmd.Flags().IntVar(&networkTopN, "top", 15, "Maximum number of rows per section (0 = all)")
+	registerRenderer("network", renderNetwork)
+	rootCmd.AddCommand(networkCmd)
+}
+
+func runNetwork(cmd *cobra.Command, args []string) error {
+	if networkFormat != "table" && networkFormat != "dot" && networkFormat != "json" {
+		return fmt.Errorf("unknown format %q (want table, dot or json)", networkFormat)
+	}
+
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+	if networkWindow > 0 {
+		cfg.Network.WindowDays = networkWindow
+	}
+
+	result, commits, err := runAnalyzer(repo, analysisSettings(repo, cfg), "network", loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+
+	results := result.(*analysis.NetworkResults)
+	switch networkFormat {
+	case "dot":
+		return results.WriteDOT(os.Stdout)
+	case "json":
+		return results.WriteJSON(os.Stdout)
+	}
+	renderNetwork(results, commits)
+	return nil
+}
+
+func renderNetwork(result analysis.AnalysisResult, commits []git.Commit) {
+	results := result.(*analysis.NetworkResults)
+	if len(results.Authors) == 0 {
+		fmt.Println("No commits found.")
+		return
+	}
+
+	fmt.Printf("\nCollaboration Network (%d authors, %d connections, changes within %d days):\n",
+		len(results.Authors), len(results.Edges), results.WindowDays)
+
+	fmt.Printf("\nCommunities:\n")
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-3s  %7s  %-40s  %s\n", "#", "Commits", "Members", "Main Modules")
+	fmt.Println(strings.Repeat("-", 100))
+	for i, c := range results.Communities[:limit(networkTopN, len(results.Communities))] {
+		members := strings.Join(c.Members[:min(4, len(c.Members))], ", ")
+		if len(c.Members) > 4 {
+			members += fmt.Sprintf(" +%d", len(c.Members)-4)
+		}
+		fmt.Printf("%-3d  %7d  %-40s  %s\n", i+1, c.Commits, truncateSubject(members, 40), strings.Join(c.Modules, ", "))
+	}
+	fmt.Println(strings.Repeat("-", 100))
+
+	byName := make(map[string]analysis.Collaborator, len(results.Authors))
+	for _, a := range results.Authors {
+		byName[a.Name] = a
+	}
+
+	if len(results.Connectors) > 0 {
+		fmt.Printf("\nConnectors (authors linking the others):\n")
+		fmt.Println(strings.Repeat("-", 70))
+		fmt.Printf("%-30s  %9s  %10s  %10s\n", "Author", "Community", "Connected", "Centrality")
+		fmt.Println(strings.Repeat("-", 70))
+		for _, name := range results.Connectors[:limit(networkTopN, len(results.Connectors))] {
+			a := byName[name]
+			fmt.Printf("%-30s  %9d  %10d  %10.2f\n", truncatePath(a.Name, 30), a.Community+1, a.Degree, a.Centrality)
+		}
+		fmt.Println(strings.Repeat("-", 70))
+	}
+
+	fmt.Printf("\nStrongest Connections:\n")
+	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("%-25s  %-25s  %12s  %11s\n", "Author", "Author", "Shared Files", "Co-authored")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, e := range results.Edges[:limit(networkTopN, len(results.Edges))] {
+		fmt.Printf("%-25s  %-25s  %12d  %11d\n", truncatePath(e.A, 25), truncatePath(e.B, 25), e.SharedFiles, e.CoAuthored)
+	}
+	fmt.Println(strings.Repeat("-", 80))
+
+	if len(results.Isolated) == 0 {
+		fmt.Println("\n✓ Every author works with someone else")
+		return
+	}
+	fmt.Printf("\nIsolated Contributors (%d):\n", len(results.Isolated))
+	for _, name := range results.Isolated[:limit(networkTopN, len(results.Isolated))] {
+		a := byName[name]
+		fmt.Printf("  %-30s  %5d commits  %5d files\n", truncatePath(a.Name, 30), a.Commits, a.Files)
+	}
+}
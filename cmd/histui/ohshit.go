@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var ohShitTopN int
+
+var ohShitCmd = &cobra.Command{
+	Use:     "firefighting [path]",
+	Aliases: []string{"ohshit"},
+	Short:   "Find reverts, hotfixes and quick follow-up fixes",
+	Long: `firefighting lists the commits made to undo or repair earlier ones: reverts
+(linked to the commit they revert), fixup! and squash! commits that were
+never squashed, hotfix branch merges and hotfix commits, and "oops", "typo"
+or "forgot" fixes made shortly after the same author's previous commit. It
+then ranks the files and authors most involved. Keywords, the follow-up
+window and hotfix branch names are configured in ` + config.FileName + `.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runOhShit,
+}
+
+func init() {
+	ohShitCmd.Flags().IntVar(&ohShitTopN, "top", 20, "Maximum number of rows per section (0 = all)")
+	registerRenderer("ohshit", renderOhShit)
+	rootCmd.AddCommand(ohShitCmd)
+}
+
+func runOhShit(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+
+	// The analyzer loads merges, in which hotfix branches are visible
+	result, commits, err := runAnalyzer(repo, analysisSettings(repo, cfg), "ohshit", loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+	renderOhShit(result, commits)
+	return nil
+}
+
+func renderOhShit(result analysis.AnalysisResult, commits []git.Commit) {
+	results := result.(*analysis.OhShitResults)
+	if len(results.Events) == 0 {
+		fmt.Printf("\n✓ No reverts, hotfixes or follow-up fixes in %d commits\n", results.Commits)
+		return
+	}
+
+	fmt.Printf("\nFirefighting: %d of %d commits (%.1f%%)\n", len(results.Events), results.Commits, results.Ratio()*100)
+	fmt.Printf("  %d reverts, %d fixups, %d hotfixes, %d follow-ups\n",
+		results.Kinds[analysis.FirefightRevert], results.Kinds[analysis.FirefightFixup],
+		results.Kinds[analysis.FirefightHotfix], results.Kinds[analysis.FirefightFollowUp])
+
+	fmt.Printf("\nRecent Firefighting:\n")
+	fmt.Println(strings.Repeat("-", 110))
+	fmt.Printf("%-8s  %-9s  %-10s  %-20s  %-55s\n", "Commit", "Kind", "Date", "Author", "Subject")
+	fmt.Println(strings.Repeat("-", 110))
+	for _, e := range results.Events[:limit(ohShitTopN, len(results.Events))] {
+		fmt.Printf("%-8s  %-9s  %-10s  %-20s  %-55s\n",
+			e.ShortSHA, e.Kind, e.Timestamp.Format("2006-01-02"), truncatePath(e.Author, 20), truncateSubject(e.Subject, 55))
+		switch {
+		case e.RelatedSubject != "":
+			fmt.Printf("%-8s  └ %s %s by %s, %s earlier\n", "", shortSHA(e.RelatedSHA), truncateSubject(e.RelatedSubject, 50),
+				e.RelatedAuthor, formatDelay(e.Delay))
+		case e.RelatedSHA != "":
+			fmt.Printf("%-8s  └ %s (not in analyzed history)\n", "", shortSHA(e.RelatedSHA))
+		case e.Branch != "":
+			fmt.Printf("%-8s  └ branch %s\n", "", e.Branch)
+		}
+	}
+	fmt.Println(strings.Repeat("-", 110))
+
+	if len(results.Files) > 0 {
+		fmt.Printf("\nFiles Most Involved:\n")
+		fmt.Println(strings.Repeat("-", 70))
+		fmt.Printf("%-60s  %7s\n", "File", "Commits")
+		fmt.Println(strings.Repeat("-", 70))
+		for _, f := range results.Files[:limit(ohShitTopN, len(results.Files))] {
+			fmt.Printf("%-60s  %7d\n", truncatePath(f.Path, 60), f.Events)
+		}
+		fmt.Println(strings.Repeat("-", 70))
+	}
+
+	fmt.Printf("\nAuthors Most Involved:\n")
+	fmt.Println(strings.Repeat("-", 55))
+	fmt.Printf("%-30s  %11s  %8s\n", "Author", "Firefights", "Reverted")
+	fmt.Println(strings.Repeat("-", 55))
+	for _, a := range results.Authors[:limit(ohShitTopN, len(results.Authors))] {
+		fmt.Printf("%-30s  %11d  %8d\n", truncatePath(a.Author, 30), a.Events, a.Reverted)
+	}
+	fmt.Println(strings.Repeat("-", 55))
+}
+
+// shortSHA abbreviates a commit hash for display
+func shortSHA(sha string) string {
+	return sha[:min(7, len(sha))]
+}
+
+// formatDelay describes a duration in its largest sensible unit
+func formatDelay(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
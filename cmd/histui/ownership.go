@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	ownershipMonths  int
+	ownershipDepth   int
+	ownershipTopN    int
+	ownershipSuggest bool
+)
+
+var ownershipCmd = &cobra.Command{
+	Use:   "ownership [path]",
+	Short: "Compare actual code ownership with CODEOWNERS",
+	Long: `ownership computes who actually changes each directory from recent
+commits and validates the CODEOWNERS file (GitHub or GitLab syntax) against
+it: files without an owner, owners who never touch their paths and heavy
+contributors missing from CODEOWNERS. With --suggest, it prints a CODEOWNERS
+file derived from recent contributions instead.
+
+CODEOWNERS handles are matched to commit authors by email (@login matches
+login@... and GitHub noreply addresses); other mappings and team members are
+configured in ` + config.FileName + ` ("ownership.aliases" and "teams").`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runOwnership,
+}
+
+func init() {
+	ownershipCmd.Flags().IntVar(&ownershipMonths, "months", 0, "Months of history that count as recent (default from config)")
+	ownershipCmd.Flags().IntVar(&ownershipDepth, "depth", 1, "Directory depth of the breakdown and suggestion")
+	ownershipCmd.Flags().IntVar(&ownershipTopN, "top", 20, "Maximum number of rows per section (0 = all)")
+	ownershipCmd.Flags().BoolVar(&ownershipSuggest, "suggest", false, "Print a suggested CODEOWNERS file")
+	registerRenderer("ownership", renderOwnership)
+	rootCmd.AddCommand(ownershipCmd)
+}
+
+func runOwnership(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+	if ownershipMonths > 0 {
+		cfg.Ownership.Months = ownershipMonths
+	}
+
+	settings := analysisSettings(repo, cfg)
+	settings.Depth = ownershipDepth
+	result, commits, err := runAnalyzer(repo, settings, "ownership", loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+
+	if ownershipSuggest {
+		fmt.Print(result.(*analysis.OwnershipResults).Suggested)
+		return nil
+	}
+	renderOwnership(result, commits)
+	return nil
+}
+
+func renderOwnership(result analysis.AnalysisResult, commits []git.Commit) {
+	results := result.(*analysis.OwnershipResults)
+
+	since := "all history"
+	if !results.Since.IsZero() {
+		since = "since " + results.Since.Format("2006-01-02")
+	}
+	fmt.Printf("\nActual Ownership (%s):\n", since)
+	fmt.Println(strings.Repeat("-", 110))
+	fmt.Printf("%-30s  %5s  %7s  %-30s  %-30s\n", "Directory", "Files", "Changes", "Top Contributors", "Declared Owners")
+	fmt.Println(strings.Repeat("-", 110))
+	for _, d := range results.Directories[:limit(ownershipTopN, len(results.Directories))] {
+		var top []string
+		for _, a := range d.Authors[:min(2, len(d.Authors))] {
+			top = append(top, fmt.Sprintf("%s %.0f%%", a.Author, a.Share*100))
+		}
+		fmt.Printf("%-30s  %5d  %7d  %-30s  %-30s\n",
+			truncatePath(d.Path, 30),
+			d.Files,
+			d.Changes,
+			truncatePath(strings.Join(top, ", "), 30),
+			truncatePath(strings.Join(d.Declared, " "), 30))
+	}
+	fmt.Println(strings.Repeat("-", 110))
+
+	if results.Codeowners == "" {
+		fmt.Println("\nNo CODEOWNERS file found; run with --suggest to generate one.")
+		return
+	}
+	fmt.Printf("\nCODEOWNERS: %s (%d rules)\n", results.Codeowners, results.Rules)
+
+	if len(results.Unowned) > 0 {
+		fmt.Printf("\nFiles Without Owner (%d):\n", len(results.Unowned))
+		fmt.Println(strings.Repeat("-", 60))
+		for _, path := range results.Unowned[:limit(ownershipTopN, len(results.Unowned))] {
+			fmt.Printf("  %s\n", path)
+		}
+		fmt.Println(strings.Repeat("-", 60))
+	} else {
+		fmt.Println("\n✓ Every file has an owner")
+	}
+
+	if len(results.InactiveOwners) > 0 {
+		fmt.Printf("\nOwners Who Did Not Touch Their Paths (%s):\n", since)
+		fmt.Println(strings.Repeat("-", 90))
+		fmt.Printf("%-25s  %-40s  %5s  %5s  %7s\n", "Owner", "Pattern", "Line", "Files", "Changes")
+		fmt.Println(strings.Repeat("-", 90))
+		for _, o := range results.InactiveOwners[:limit(ownershipTopN, len(results.InactiveOwners))] {
+			fmt.Printf("%-25s  %-40s  %5d  %5d  %7d\n", truncatePath(o.Owner, 25), truncatePath(o.Pattern, 40), o.Line, o.Files, o.Changes)
+		}
+		fmt.Println(strings.Repeat("-", 90))
+	}
+
+	if len(results.MissingOwners) > 0 {
+		fmt.Printf("\nHeavy Contributors Missing From CODEOWNERS (%s):\n", since)
+		fmt.Println(strings.Repeat("-", 90))
+		fmt.Printf("%-30s  %-35s  %5s  %6s\n", "Contributor", "Pattern", "Line", "Share")
+		fmt.Println(strings.Repeat("-", 90))
+		for _, m := range results.MissingOwners[:limit(ownershipTopN, len(results.MissingOwners))] {
+			fmt.Printf("%-30s  %-35s  %5d  %5.0f%%\n", truncatePath(m.Handle, 30), truncatePath(m.Pattern, 35), m.Line, m.Share*100)
+		}
+		fmt.Println(strings.Repeat("-", 90))
+	}
+
+	if len(results.Unresolved) > 0 {
+		fmt.Printf("\nOwners not checked (define their members under \"teams\" in %s): %s\n",
+			config.FileName, strings.Join(results.Unresolved, ", "))
+	}
+}
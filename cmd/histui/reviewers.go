@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	reviewersTopN int
+)
+
+var reviewersCmd = &cobra.Command{
+	Use:   "reviewers [rev-range]",
+	Short: "Suggest reviewers for a range of commits or the staged changes",
+	Long: `reviewers suggests who should review a change: the commits of a revision
+range ("main..feature", "HEAD~3..", or a single commit), or the staged changes
+when no range is given. Candidates are ranked by their expertise in the
+changed files (recency-weighted commits and lines changed; for new files,
+their directories), the earlier changes to the files they reviewed
+(Reviewed-by trailers) and their ownership of files coupled with the change.
+The authors of the change and experts who stopped committing are left out.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReviewers,
+}
+
+func init() {
+	reviewersCmd.Flags().IntVar(&reviewersTopN, "top", 5, "Number of reviewers to suggest (0 = all)")
+	rootCmd.AddCommand(reviewersCmd)
+}
+
+func runReviewers(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromFlags())
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+
+	revRange, what := "", "the staged changes"
+	if len(args) > 0 {
+		revRange, what = args[0], args[0]
+	}
+	results, err := analysis.ReviewersAt(repo, revRange, loadOptionsFromFlags(), ignoreFiles, cfg)
+	if err != nil {
+		return err
+	}
+	if len(results.Files) == 0 {
+		fmt.Printf("No changed files in %s.\n", what)
+		return nil
+	}
+
+	authors := make([]string, len(results.Authors))
+	for i, a := range results.Authors {
+		authors[i] = a.Name
+	}
+	by := ""
+	if len(authors) > 0 {
+		by = " by " + strings.Join(authors, ", ")
+	}
+	fmt.Printf("\nReviewers for %s (%d files%s):\n", what, len(results.Files), by)
+
+	switch {
+	case len(results.Suggestions) > 0:
+		fmt.Println(strings.Repeat("-", 100))
+		fmt.Printf("%-25s  %5s  %s\n", "Reviewer", "Score", "Why")
+		fmt.Println(strings.Repeat("-", 100))
+		for _, s := range results.Suggestions[:limit(reviewersTopN, len(results.Suggestions))] {
+			reasons := s.Reasons()
+			fmt.Printf("%-25s  %4.0f%%  %s\n", truncatePath(s.Reviewer, 25), s.Score*100, reasons[0])
+			for _, reason := range reasons[1:] {
+				fmt.Printf("%-25s  %5s  %s\n", "", "", reason)
+			}
+		}
+		fmt.Println(strings.Repeat("-", 100))
+	case len(results.Inactive) > 0:
+		fmt.Println("No active reviewers: everyone else who worked on these files has stopped committing.")
+	default:
+		fmt.Println("No one else has worked on these files.")
+	}
+
+	if len(results.Inactive) > 0 {
+		fmt.Printf("\nLeft out as inactive: %s\n", strings.Join(results.Inactive, ", "))
+	}
+	return nil
+}
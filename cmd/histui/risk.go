@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	riskTopN    int
+	riskDetails bool
+)
+
+var riskCmd = &cobra.Command{
+	Use:   "risk <rev-range>",
+	Short: "Rank the commits of a range by how risky they are to merge",
+	Long: `risk scores every commit of a revision range ("main..feature", "HEAD~20..",
+or a single commit) from 0 to 100 so that reviewers know where to look first.
+The score combines the size of the change, the number of top-level modules it
+touches, the hotspot scores of its files, how many of its files the author
+never changed before, the time of day it was made at, and coupled files that
+usually change together with its files but were left out. Hotspots and
+coupling are computed from the history before the range.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRisk,
+}
+
+func init() {
+	riskCmd.Flags().IntVar(&riskTopN, "top", 20, "Number of commits to show (0 = all)")
+	riskCmd.Flags().BoolVar(&riskDetails, "details", false, "Show every risk factor of each commit")
+	rootCmd.AddCommand(riskCmd)
+}
+
+func runRisk(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromFlags())
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+
+	results, err := analysis.RiskAt(repo, args[0], loadOptionsFromFlags(), ignoreFiles, cfg)
+	if err != nil {
+		return err
+	}
+	if len(results.Commits) == 0 {
+		fmt.Printf("No commits in %s.\n", args[0])
+		return nil
+	}
+
+	fmt.Printf("\nCommit Risk in %s (%d commits, riskiest first):\n", args[0], len(results.Commits))
+	fmt.Println(strings.Repeat("-", 110))
+	fmt.Printf("%-8s  %5s  %-20s  %-40s  %s\n", "Commit", "Risk", "Author", "Subject", "Main Factors")
+	fmt.Println(strings.Repeat("-", 110))
+	for _, r := range results.Commits[:limit(riskTopN, len(results.Commits))] {
+		var top []string
+		for _, f := range r.Top(2) {
+			top = append(top, f.Name)
+		}
+		fmt.Printf("%-8s  %5.0f  %-20s  %-40s  %s\n",
+			r.ShortSHA, r.Score, truncatePath(r.Author, 20), truncateSubject(r.Subject, 40), strings.Join(top, ", "))
+		if riskDetails {
+			for _, f := range r.Factors {
+				fmt.Printf("%-8s  %-10s %4.0f%%  %s\n", "", f.Name, f.Value*100, f.Detail)
+			}
+		}
+	}
+	fmt.Println(strings.Repeat("-", 110))
+	return nil
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	survivalDepth   int
+	survivalTopN    int
+	survivalSamples int
+)
+
+// survivalCohorts is the number of year cohorts shown in the stratigraphy;
+// older years are folded together
+const survivalCohorts = 6
+
+// survivalChars draws the cohorts of the stratigraphy, oldest first
+var survivalChars = []string{"░", "▒", "▓", "█", "▚", "■"}
+
+// survivalAges are the ages, in months, shown in the survival tables
+var survivalAges = []int{3, 6, 12, 24}
+
+var survivalCmd = &cobra.Command{
+	Use:   "survival [path]",
+	Short: "Show how long added lines survive, per author, directory and year",
+	Long: `survival blames every file at revisions sampled evenly over the history and
+estimates how long added lines survive before they are rewritten or deleted.
+It reports the share of lines still alive by age and the half-life of the
+lines of every author, top-level directory and year cohort, and a code
+stratigraphy: the lines alive at each sample by the year they were written.
+Blaming many revisions is slow; the number of samples and the minimum lines
+an author or directory needs for a curve are configured in
+` + config.FileName + ` ("survival").`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSurvival,
+}
+
+func init() {
+	survivalCmd.Flags().IntVar(&survivalDepth, "depth", 1, "Directory depth of the directory curves")
+	survivalCmd.Flags().IntVar(&survivalTopN, "top", 10, "Number of authors to show (0 = all)")
+	survivalCmd.Flags().IntVar(&survivalSamples, "samples", 0, "Number of revisions to blame (0 = from the configuration)")
+	registerRenderer("survival", renderSurvival)
+	rootCmd.AddCommand(survivalCmd)
+}
+
+func runSurvival(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+	if survivalSamples > 0 {
+		cfg.Survival.Samples = survivalSamples
+	}
+
+	settings := analysisSettings(repo, cfg)
+	settings.Depth = survivalDepth
+	result, commits, err := runAnalyzer(repo, settings, "survival", loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+	renderSurvival(result, commits)
+	return nil
+}
+
+func renderSurvival(result analysis.AnalysisResult, commits []git.Commit) {
+	results := result.(*analysis.SurvivalResults)
+	if results.Overall.Added == 0 {
+		fmt.Println("No added lines to follow.")
+		return
+	}
+
+	renderStratigraphy(results)
+
+	fmt.Printf("\nLine Survival: %s\n", results.Summary())
+	renderSurvivalCurves("Author", append([]analysis.SurvivalCurve{results.Overall}, results.Authors[:limit(survivalTopN, len(results.Authors))]...))
+	renderSurvivalCurves("Directory", results.Directories)
+	renderSurvivalCurves("Cohort", results.Cohorts)
+}
+
+// renderStratigraphy prints the lines alive at every sample by the year they
+// were written in, the latest cohorts in their own columns
+func renderStratigraphy(results *analysis.SurvivalResults) {
+	years := results.Years
+	folded := max(len(years)-survivalCohorts, 0)
+	if folded == 1 {
+		// Folding a single year saves nothing
+		folded = 0
+	}
+	labels := make([]string, 0, len(years)-folded+1)
+	if folded > 0 {
+		labels = append(labels, "older")
+	}
+	for _, year := range years[folded:] {
+		label := strconv.Itoa(year)
+		if year == 0 {
+			label = "older"
+		}
+		labels = append(labels, label)
+	}
+
+	legend := make([]string, len(labels))
+	for i, label := range labels {
+		legend[i] = survivalChars[i%len(survivalChars)] + " " + label
+	}
+	fmt.Printf("\nCode Stratigraphy (%s):\n", strings.Join(legend, ", "))
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-10s  %8s", "Sample", "Lines")
+	for _, label := range labels {
+		fmt.Printf("  %6s", label)
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 100))
+
+	maxTotal := 0
+	for _, s := range results.Samples {
+		total := 0
+		for _, n := range s.Lines {
+			total += n
+		}
+		maxTotal = max(maxTotal, total)
+	}
+	width := max(100-22-8*len(labels), 10)
+	for _, s := range results.Samples {
+		layers := make([]int, 0, len(labels))
+		if folded > 0 {
+			older := 0
+			for _, n := range s.Lines[:folded] {
+				older += n
+			}
+			layers = append(layers, older)
+		}
+		layers = append(layers, s.Lines[folded:]...)
+
+		total := 0
+		for _, n := range layers {
+			total += n
+		}
+		fmt.Printf("%-10s  %8d", s.Time.Format("2006-01-02"), total)
+		for _, n := range layers {
+			fmt.Printf("  %6d", n)
+		}
+		fmt.Printf("  %s\n", stratumBar(layers, survivalChars, width*total/max(maxTotal, 1)))
+	}
+	fmt.Println(strings.Repeat("-", 100))
+}
+
+// stratumBar draws the layers of a sample as a bar of width characters
+func stratumBar(layers []int, chars []string, width int) string {
+	total := 0
+	for _, n := range layers {
+		total += n
+	}
+	if total == 0 {
+		return ""
+	}
+	var bar strings.Builder
+	drawn, seen := 0, 0
+	for i, n := range layers {
+		seen += n
+		end := (seen*width + total/2) / total
+		bar.WriteString(strings.Repeat(chars[i%len(chars)], end-drawn))
+		drawn = end
+	}
+	return bar.String()
+}
+
+// renderSurvivalCurves prints the share of lines surviving to some ages and
+// the half-life of every curve
+func renderSurvivalCurves(title string, curves []analysis.SurvivalCurve) {
+	if len(curves) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-30s  %8s  %8s", title, "Added", "Alive")
+	for _, months := range survivalAges {
+		fmt.Printf("  %6s", fmt.Sprintf("%dmo", months))
+	}
+	fmt.Printf("  %s\n", "Half-life")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, c := range curves {
+		fmt.Printf("%-30s  %8d  %8d", truncatePath(c.Name, 30), c.Added, c.Alive)
+		for _, months := range survivalAges {
+			if share, ok := c.At(months); ok {
+				fmt.Printf("  %5.0f%%", share*100)
+			} else {
+				fmt.Printf("  %6s", "-")
+			}
+		}
+		halfLife := fmt.Sprintf(">%dmo", c.MaxMonths())
+		if c.HalfLife > 0 {
+			halfLife = fmt.Sprintf("%.1fmo", c.HalfLife)
+		}
+		fmt.Printf("  %s\n", halfLife)
+	}
+	fmt.Println(strings.Repeat("-", 100))
+}
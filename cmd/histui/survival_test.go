@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestStratumBar(t *testing.T) {
+	chars := []string{"a", "b", "c"}
+	tests := []struct {
+		layers []int
+		width  int
+		want   string
+	}{
+		{nil, 10, ""},
+		{[]int{0, 0}, 10, ""},
+		{[]int{10}, 4, "aaaa"},
+		{[]int{1, 1}, 4, "aabb"},
+		{[]int{1, 0, 3}, 8, "aacccccc"},
+		{[]int{1, 1, 1, 1}, 8, "aabbccaa"},
+		{[]int{1, 1, 1}, 4, "abbc"},
+		{[]int{1, 100}, 10, "bbbbbbbbbb"},
+		{[]int{5, 5}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		if got := stratumBar(tt.layers, chars, tt.width); got != tt.want {
+			t.Errorf("stratumBar(%v, %d) = %q, want %q", tt.layers, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestStratumBarFillsWidth(t *testing.T) {
+	for width := 1; width <= 50; width++ {
+		bar := stratumBar([]int{3, 7, 11, 2, 5}, survivalChars, width)
+		if n := utf8.RuneCountInString(bar); n != width {
+			t.Errorf("width %d: bar has %d characters: %q", width, n, bar)
+		}
+	}
+}
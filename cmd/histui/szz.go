@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	defectsTopN  int
+	defectsFixes int
+)
+
+var defectsCmd = &cobra.Command{
+	Use:     "defects [path]",
+	Aliases: []string{"szz"},
+	Short:   "Find the commits that introduced bugs and rank authors, files and hours by them",
+	Long: `defects recognizes bug-fixing commits by their messages (keywords such as
+"fix" or "bug", trailers such as "Fixes:" and "fixes #12") and blames the
+lines each fix deleted or modified to find the commits that last changed
+them: the likely bug-introducing commits (the SZZ algorithm). It reports the
+share of bug-introducing commits per author and per hour of the day, and the
+files with the most of them.
+
+Blaming is slow on large histories, so only the most recent bug fixes are
+traced; keywords, trailers and limits are configured in ` + config.FileName + `.
+Use "histui hotspots --defects" to weight hotspots by defect density.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDefects,
+}
+
+func init() {
+	defectsCmd.Flags().IntVar(&defectsTopN, "top", 15, "Maximum number of authors and files to show (0 = all)")
+	defectsCmd.Flags().IntVar(&defectsFixes, "fixes", 10, "Number of traced bug fixes to list")
+	registerRenderer("szz", renderDefects)
+	rootCmd.AddCommand(defectsCmd)
+}
+
+func runDefects(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+
+	result, commits, err := runAnalyzer(repo, analysisSettings(repo, cfg), "szz", loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+	renderDefects(result, commits)
+	return nil
+}
+
+func renderDefects(result analysis.AnalysisResult, commits []git.Commit) {
+	results := result.(*analysis.SZZResults)
+	if results.BugFixes == 0 {
+		fmt.Printf("\nNo bug-fixing commits found in %d commits.\n", results.Commits)
+		return
+	}
+
+	fmt.Printf("\nBug Fixes: %d of %d commits, %d traced back to %d bug-introducing commits\n",
+		results.BugFixes, results.Commits, len(results.Traced), results.BugIntroducing)
+
+	shortSHAs := make(map[string]string, len(commits))
+	for _, c := range commits {
+		shortSHAs[c.SHA] = c.ShortSHA
+	}
+
+	if n := min(defectsFixes, len(results.Traced)); n > 0 {
+		fmt.Printf("\nRecent Bug Fixes:\n")
+		fmt.Println(strings.Repeat("-", 100))
+		for _, fix := range results.Traced[:n] {
+			fmt.Printf("%-8s  %-10s  %-20s  %s\n",
+				fix.ShortSHA, fix.Timestamp.Format("2006-01-02"), truncatePath(fix.Author, 20), truncateSubject(fix.Subject, 55))
+			if len(fix.Introducers) == 0 {
+				fmt.Printf("%-8s  └ no introducing commit found (only added lines)\n", "")
+				continue
+			}
+			introducers := make([]string, len(fix.Introducers))
+			for i, sha := range fix.Introducers {
+				introducers[i] = shortSHAs[sha]
+			}
+			fmt.Printf("%-8s  └ introduced by %s\n", "", strings.Join(introducers, ", "))
+		}
+		fmt.Println(strings.Repeat("-", 100))
+	}
+
+	fmt.Printf("\nBug-introducing Commits by Author:\n")
+	fmt.Println(strings.Repeat("-", 65))
+	fmt.Printf("%-30s  %7s  %15s  %6s\n", "Author", "Commits", "Bug-introducing", "Rate")
+	fmt.Println(strings.Repeat("-", 65))
+	for _, a := range results.Authors[:limit(defectsTopN, len(results.Authors))] {
+		fmt.Printf("%-30s  %7d  %15d  %5.0f%%\n", truncatePath(a.Name, 30), a.Commits, a.BugIntroducing, a.Rate*100)
+	}
+	fmt.Println(strings.Repeat("-", 65))
+
+	fmt.Printf("\nBug-introducing Commits by Hour of Day:\n")
+	fmt.Println(strings.Repeat("-", 65))
+	fmt.Printf("%-4s  %7s  %15s  %6s\n", "Hour", "Commits", "Bug-introducing", "Rate")
+	fmt.Println(strings.Repeat("-", 65))
+	for _, h := range results.Hours {
+		if h.Commits == 0 {
+			continue
+		}
+		fmt.Printf("%-4s  %7d  %15d  %5.0f%%  %s\n", h.Name, h.Commits, h.BugIntroducing, h.Rate*100,
+			strings.Repeat("█", int(h.Rate*20+0.5)))
+	}
+	fmt.Println(strings.Repeat("-", 65))
+
+	if len(results.Files) == 0 {
+		return
+	}
+	fmt.Printf("\nFiles With the Most Bug-introducing Commits:\n")
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-50s  %7s  %7s  %6s  %7s\n", "File", "Changes", "Defects", "Lines", "/KLOC")
+	fmt.Println(strings.Repeat("-", 90))
+	for _, f := range results.Files[:limit(defectsTopN, len(results.Files))] {
+		fmt.Printf("%-50s  %7d  %7d  %6d  %7.1f\n", truncatePath(f.Path, 50), f.Changes, f.BugIntroducing, f.Lines, f.Density)
+	}
+	fmt.Println(strings.Repeat("-", 90))
+}
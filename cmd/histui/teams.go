@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	teamsDepth    int
+	teamsTopN     int
+	teamsMinScore float64
+)
+
+var teamsCmd = &cobra.Command{
+	Use:     "teams [path]",
+	Aliases: []string{"conway"},
+	Short:   "Check whether coupling follows team boundaries (Conway's law)",
+	Long: `teams assigns every file and module to the team whose members change it
+most, using the teams configured in ` + config.FileName + `. It then reports
+strongly coupled file pairs and modules that belong to different teams, which
+need coordination on every change, and modules where several teams collide.
+The share of strongly coupled pairs within one team measures how well the
+architecture matches the organization.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTeams,
+}
+
+func init() {
+	teamsCmd.Flags().IntVar(&teamsDepth, "depth", 1, "Directory depth of modules")
+	teamsCmd.Flags().IntVar(&teamsTopN, "top", 15, "Maximum number of rows per section (0 = all)")
+	teamsCmd.Flags().Float64Var(&teamsMinScore, "min-score", 0, "Coupling score from which a pair counts as strongly coupled (default from config)")
+	registerRenderer("conway", renderConway)
+	rootCmd.AddCommand(teamsCmd)
+}
+
+func runTeams(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+	if len(cfg.Teams) == 0 {
+		return fmt.Errorf("no teams configured in %s", config.FileName)
+	}
+	if teamsMinScore > 0 {
+		cfg.Conway.MinScore = teamsMinScore
+	}
+
+	settings := analysisSettings(repo, cfg)
+	settings.Depth = teamsDepth
+	result, commits, err := runAnalyzer(repo, settings, "conway", loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+	renderConway(result, commits)
+	return nil
+}
+
+func renderConway(result analysis.AnalysisResult, commits []git.Commit) {
+	results := result.(*analysis.ConwayResults)
+
+	fmt.Printf("\nTeam Alignment: %.0f%% of %d strongly coupled file pairs stay within one team\n",
+		results.Alignment()*100, results.Pairs)
+
+	if len(results.ModulePairs) > 0 {
+		fmt.Printf("\nCoupled Modules Across Teams:\n")
+		fmt.Println(strings.Repeat("-", 110))
+		fmt.Printf("%-25s  %-20s  %-25s  %-20s  %5s  %10s\n", "Module", "Team", "Module", "Team", "Pairs", "Co-changes")
+		fmt.Println(strings.Repeat("-", 110))
+		for _, m := range results.ModulePairs[:limit(teamsTopN, len(results.ModulePairs))] {
+			fmt.Printf("%-25s  %-20s  %-25s  %-20s  %5d  %10d\n",
+				truncatePath(m.ModuleA, 25), truncatePath(m.TeamA, 20), truncatePath(m.ModuleB, 25), truncatePath(m.TeamB, 20), m.Pairs, m.CoChanges)
+		}
+		fmt.Println(strings.Repeat("-", 110))
+	}
+
+	if len(results.CrossTeam) > 0 {
+		fmt.Printf("\nCoupled Files Across Teams:\n")
+		fmt.Println(strings.Repeat("-", 110))
+		for _, p := range results.CrossTeam[:limit(teamsTopN, len(results.CrossTeam))] {
+			fmt.Printf("%-50s  %-20s %4.0f%%  score %.2f (%d)\n",
+				truncatePath(p.FileA, 50), truncatePath(p.TeamA.Team, 20), p.TeamA.Share*100, p.Score, p.CoChanges)
+			fmt.Printf("  └ %-46s  %-20s %4.0f%%\n",
+				truncatePath(p.FileB, 46), truncatePath(p.TeamB.Team, 20), p.TeamB.Share*100)
+		}
+		fmt.Println(strings.Repeat("-", 110))
+	} else {
+		fmt.Println("\n✓ No strongly coupled files across teams")
+	}
+
+	if len(results.Collisions) > 0 {
+		fmt.Printf("\nModules Where Teams Collide:\n")
+		fmt.Println(strings.Repeat("-", 100))
+		fmt.Printf("%-40s  %7s  %s\n", "Module", "Changes", "Teams")
+		fmt.Println(strings.Repeat("-", 100))
+		for _, m := range results.Collisions[:limit(teamsTopN, len(results.Collisions))] {
+			teams := make([]string, len(m.Teams))
+			for i, t := range m.Teams {
+				teams[i] = fmt.Sprintf("%s %.0f%%", t.Team, t.Share*100)
+			}
+			fmt.Printf("%-40s  %7d  %s\n", truncatePath(m.Path, 40), m.Changes, strings.Join(teams, ", "))
+		}
+		fmt.Println(strings.Repeat("-", 100))
+	}
+
+	if len(results.Unassigned) > 0 {
+		fmt.Printf("\n%d authors are in no team and left out: %s\n",
+			len(results.Unassigned), strings.Join(results.Unassigned[:min(5, len(results.Unassigned))], ", "))
+	}
+}
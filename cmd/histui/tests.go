@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	testsMinChanges int
+	testsMaxRatio   float64
+	testsTopN       int
+	testsShowAll    bool
+)
+
+var testsCmd = &cobra.Command{
+	Use:   "tests [path]",
+	Short: "Show how often source files change together with their tests",
+	Long: `tests pairs source files with their tests using naming conventions
+(foo.go/foo_test.go, x.ts/x.spec.ts, src/ -> tests/ mirrors, ...), reports the
+test co-change ratio per file and lists frequently changed files whose tests
+almost never change with them. Conventions are configured in ` + config.FileName + `.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTests,
+}
+
+func init() {
+	testsCmd.Flags().IntVar(&testsMinChanges, "min-changes", 0, "Source changes before a file is judged (default from config)")
+	testsCmd.Flags().Float64Var(&testsMaxRatio, "max-ratio", -1, "Flag files whose test co-change ratio is at or below this value (default from config)")
+	testsCmd.Flags().IntVar(&testsTopN, "top", 10, "Maximum number of files per section (0 = all)")
+	testsCmd.Flags().BoolVar(&testsShowAll, "all", false, "Also list the test co-change ratio of every paired file")
+	rootCmd.AddCommand(testsCmd)
+}
+
+func runTests(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+	if testsMinChanges > 0 {
+		cfg.Tests.MinChanges = testsMinChanges
+	}
+	if testsMaxRatio >= 0 {
+		cfg.Tests.MaxRatio = testsMaxRatio
+	}
+
+	result, _, err := runAnalyzer(repo, analysisSettings(repo, cfg), "tests", loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+	results := result.(*analysis.TestCouplingResults)
+
+	fmt.Printf("Source files with tests: %d\n", len(results.Files))
+	fmt.Printf("Test co-change ratio:    %.1f%%\n", results.OverallRatio*100)
+
+	if testsShowAll {
+		printTestCouplings("Test Co-change Ratio per File", results.Files)
+	}
+
+	if len(results.Neglected) > 0 {
+		printTestCouplings(fmt.Sprintf("Files Whose Tests Rarely Change With Them (ratio <= %.2f, >= %d changes)",
+			cfg.Tests.MaxRatio, cfg.Tests.MinChanges), results.Neglected)
+	} else {
+		fmt.Println("\n✓ No frequently changed files with neglected tests")
+	}
+
+	if len(results.Untested) > 0 {
+		fmt.Printf("\nFrequently Changed Files Without Tests (>= %d changes):\n", cfg.Tests.MinChanges)
+		fmt.Println(strings.Repeat("-", 60))
+		for i, tc := range results.Untested[:limit(testsTopN, len(results.Untested))] {
+			fmt.Printf("%-3d  %-45s  %5d\n", i+1, truncatePath(tc.Source, 45), tc.SourceChanges)
+		}
+		fmt.Println(strings.Repeat("-", 60))
+	}
+
+	return nil
+}
+
+// printTestCouplings prints a table of source files and their test co-change ratio
+func printTestCouplings(title string, files []analysis.TestCoupling) {
+	fmt.Printf("\n%s:\n", title)
+	fmt.Println(strings.Repeat("-", 110))
+	fmt.Printf("%-3s  %-40s  %-40s  %-7s  %-7s  %-5s\n", "#", "Source", "Test", "Changes", "W/ Test", "Ratio")
+	fmt.Println(strings.Repeat("-", 110))
+	for i, tc := range files[:limit(testsTopN, len(files))] {
+		fmt.Printf("%-3d  %-40s  %-40s  %7d  %7d  %5.2f\n",
+			i+1,
+			truncatePath(tc.Source, 40),
+			truncatePath(strings.Join(tc.Tests, ", "), 40),
+			tc.SourceChanges,
+			tc.TestCoChanges,
+			tc.Ratio)
+	}
+	fmt.Println(strings.Repeat("-", 110))
+}
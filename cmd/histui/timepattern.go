@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	timeAuthors int
+	timeTeams   bool
+	timeMonths  int
+)
+
+var timeCmd = &cobra.Command{
+	Use:   "time [path]",
+	Short: "Show when commits happen: heatmaps, weekend and out-of-hours work",
+	Long: `time renders hour-of-day by day-of-week commit heatmaps and reports the
+share of commits made on weekends and outside working hours, using the
+timezone each commit was made in. A rising off-hours ratio per author is
+reported as a burnout signal. Working hours and teams are configured in
+` + config.FileName + `.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTime,
+}
+
+func init() {
+	timeCmd.Flags().IntVar(&timeAuthors, "authors", 0, "Also show heatmaps of the N most active authors")
+	timeCmd.Flags().BoolVar(&timeTeams, "teams", false, "Also show a heatmap per configured team")
+	timeCmd.Flags().IntVar(&timeMonths, "months", 12, "Months shown in the trend (0 = all)")
+	registerRenderer("timepattern", renderTimePatterns)
+	rootCmd.AddCommand(timeCmd)
+}
+
+func runTime(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+
+	result, commits, err := runAnalyzer(repo, analysisSettings(repo, cfg), "timepattern", loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+	renderTimePatterns(result, commits)
+	return nil
+}
+
+func renderTimePatterns(result analysis.AnalysisResult, commits []git.Commit) {
+	results := result.(*analysis.TimePatternResults)
+	if results.Overall.Commits == 0 {
+		fmt.Println("No commits found.")
+		return
+	}
+
+	fmt.Printf("\nCommit Times (local to each commit, working hours %02d:00-%02d:00):\n", results.WorkStart, results.WorkEnd)
+	printHeatmap(results.Overall)
+
+	for _, p := range results.Authors[:min(timeAuthors, len(results.Authors))] {
+		printHeatmap(p)
+	}
+	if timeTeams {
+		if len(results.Teams) == 0 {
+			fmt.Printf("\nNo teams configured in %s\n", config.FileName)
+		}
+		for _, p := range results.Teams {
+			printHeatmap(p)
+		}
+	}
+
+	fmt.Printf("\nOff-hours Work by Author:\n")
+	fmt.Println(strings.Repeat("-", 70))
+	fmt.Printf("%-30s  %7s  %8s  %12s\n", "Author", "Commits", "Weekend", "Out of Hours")
+	fmt.Println(strings.Repeat("-", 70))
+	for _, p := range results.Authors[:min(10, len(results.Authors))] {
+		fmt.Printf("%-30s  %7d  %7.0f%%  %11.0f%%\n", truncatePath(p.Name, 30), p.Commits, p.WeekendRatio*100, p.OutOfHoursRatio*100)
+	}
+	fmt.Println(strings.Repeat("-", 70))
+
+	trend := results.Trend
+	if timeMonths > 0 && len(trend) > timeMonths {
+		trend = trend[len(trend)-timeMonths:]
+	}
+	fmt.Printf("\nMonthly Trend:\n")
+	fmt.Println(strings.Repeat("-", 70))
+	fmt.Printf("%-8s  %7s  %8s  %12s  %s\n", "Month", "Commits", "Weekend", "Out of Hours", "Off-hours")
+	fmt.Println(strings.Repeat("-", 70))
+	for _, t := range trend {
+		off := t.WeekendRatio + t.OutOfHoursRatio
+		fmt.Printf("%-8s  %7d  %7.0f%%  %11.0f%%  %s\n",
+			t.Month.Format("2006-01"), t.Commits, t.WeekendRatio*100, t.OutOfHoursRatio*100,
+			strings.Repeat("█", int(off*20+0.5)))
+	}
+	fmt.Println(strings.Repeat("-", 70))
+
+	if len(results.Burnout) == 0 {
+		fmt.Println("\n✓ No author's off-hours work is rising")
+		return
+	}
+	fmt.Printf("\nRising Off-hours Work (possible burnout):\n")
+	fmt.Println(strings.Repeat("-", 70))
+	fmt.Printf("%-30s  %15s  %15s\n", "Author", "Year Before", "Recently")
+	fmt.Println(strings.Repeat("-", 70))
+	for _, b := range results.Burnout {
+		fmt.Printf("%-30s  %5.0f%% (%4d)   %5.0f%% (%4d)\n",
+			truncatePath(b.Author, 30), b.PreviousRatio*100, b.PreviousCommits, b.RecentRatio*100, b.RecentCommits)
+	}
+	fmt.Println(strings.Repeat("-", 70))
+}
+
+// heatLevels shade heatmap cells from empty to the busiest hour
+var heatLevels = []string{" ", "░", "▒", "▓", "█"}
+
+// printHeatmap renders a day-of-week by hour-of-day heatmap, Monday first
+func printHeatmap(p analysis.TimeProfile) {
+	fmt.Printf("\n%s (%d commits, %.0f%% weekend, %.0f%% out of hours)\n",
+		p.Name, p.Commits, p.WeekendRatio*100, p.OutOfHoursRatio*100)
+	fmt.Printf("     %s\n", "0     3     6     9     12    15    18    21")
+
+	peak := p.Heatmap.Max()
+	days := []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+	for i, day := range days {
+		weekday := (i + 1) % 7
+		var row strings.Builder
+		for hour := 0; hour < 24; hour++ {
+			level := 0
+			if n := p.Heatmap[weekday][hour]; n > 0 {
+				level = 1 + (n*(len(heatLevels)-2))/peak
+			}
+			row.WriteString(heatLevels[level] + heatLevels[level])
+		}
+		fmt.Printf("%s  %s\n", day, row.String())
+	}
+}
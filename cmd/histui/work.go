@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/config"
+	"histui/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	workDepth  int
+	workMonths int
+	workTopN   int
+	workList   bool
+)
+
+var workCmd = &cobra.Command{
+	Use:   "work [path]",
+	Short: "Show the mix of features, fixes, refactoring and chores over time and per module",
+	Long: `work classifies every commit as feature, fix, refactor, test, docs, chore or
+other, from its Conventional Commits type, the shape of its diff (only
+renames, only tests, only documentation, only build files), bug-fix
+keywords and trailers, and subject keywords. It then shows the mix of work
+per month and per module. Keywords and path patterns are configured in
+` + config.FileName + ` ("classify").`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWork,
+}
+
+func init() {
+	workCmd.Flags().IntVar(&workDepth, "depth", 1, "Directory depth of modules")
+	workCmd.Flags().IntVar(&workMonths, "months", 12, "Months shown in the trend (0 = all)")
+	workCmd.Flags().IntVar(&workTopN, "top", 15, "Number of modules to show (0 = all)")
+	workCmd.Flags().BoolVar(&workList, "list", false, "List every commit with its category instead")
+	registerRenderer("workmix", renderWorkMix)
+	rootCmd.AddCommand(workCmd)
+}
+
+func runWork(cmd *cobra.Command, args []string) error {
+	repo, err := git.NewCLIRepository(repoPathFromArgs(args))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	cfg, err := config.Load(repo.GetPath())
+	if err != nil {
+		return err
+	}
+	// The coordinator classifies every commit it loads
+	settings := analysisSettings(repo, cfg)
+	settings.Depth = workDepth
+	result, commits, err := runAnalyzer(repo, settings, "workmix", loadOptionsFromFlags())
+	if err != nil {
+		return err
+	}
+
+	if workList {
+		for _, c := range commits {
+			fmt.Printf("%s  %-8s  %s\n", c.ShortSHA, c.Category, c.Subject)
+		}
+		return nil
+	}
+
+	renderWorkMix(result, commits)
+	return nil
+}
+
+func renderWorkMix(result analysis.AnalysisResult, commits []git.Commit) {
+	results := result.(*analysis.WorkMixResults)
+	if results.Overall.Commits == 0 {
+		fmt.Println("No commits found.")
+		return
+	}
+
+	fmt.Printf("\nWork Mix (%d commits):\n", results.Overall.Commits)
+	printWorkMix("", []analysis.WorkMix{results.Overall})
+
+	periods := results.Periods
+	if workMonths > 0 && len(periods) > workMonths {
+		periods = periods[len(periods)-workMonths:]
+	}
+	fmt.Printf("\nBy Month:\n")
+	printWorkMix("Month", periods)
+
+	fmt.Printf("\nBy Module:\n")
+	printWorkMix("Module", results.Modules[:limit(workTopN, len(results.Modules))])
+}
+
+// printWorkMix prints the category shares of each work mix as a table
+func printWorkMix(label string, mixes []analysis.WorkMix) {
+	width := 40 + 10*len(analysis.Categories)
+	fmt.Println(strings.Repeat("-", width))
+	fmt.Printf("%-30s  %7s", label, "Commits")
+	for _, category := range analysis.Categories {
+		fmt.Printf("  %8s", category)
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", width))
+	for _, m := range mixes {
+		fmt.Printf("%-30s  %7d", truncatePath(m.Name, 30), m.Commits)
+		for _, category := range analysis.Categories {
+			fmt.Printf("  %7.0f%%", m.Share(category)*100)
+		}
+		fmt.Println()
+	}
+	fmt.Println(strings.Repeat("-", width))
+}
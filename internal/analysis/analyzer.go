@@ -0,0 +1,152 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// Analyzer turns loaded commits into one kind of insight
+type Analyzer interface {
+	// Unique identifier for this analyzer
+	Name() string
+
+	// Version of the analysis logic; bump it when results change meaning
+	Version() string
+
+	// Commit data this analyzer depends on
+	Needs() DataNeeds
+
+	// Whether this analyzer's results can be cached
+	Cacheable() bool
+
+	// Empty result to decode cached results into
+	NewResult() AnalysisResult
+
+	// Run analysis on commits (newest first)
+	Analyze(commits []git.Commit) (AnalysisResult, error)
+}
+
+// IncrementalAnalyzer can fold new commits into a previous result instead of
+// re-analyzing the whole history
+type IncrementalAnalyzer interface {
+	Analyzer
+
+	// Update merges commits newer than those behind previous into it.
+	// Returns ErrFullRebuild if previous cannot be extended.
+	Update(previous AnalysisResult, newCommits []git.Commit) (AnalysisResult, error)
+}
+
+// ErrFullRebuild reports that an incremental update is impossible
+var ErrFullRebuild = errors.New("incremental update not possible, full rebuild required")
+
+// AnalysisResult is the generic result container produced by an Analyzer
+type AnalysisResult interface {
+	// Type identifier for deserialization
+	Type() string
+
+	// Summary for dashboard display
+	Summary() string
+}
+
+// DataNeeds declares which commit data an analyzer requires
+type DataNeeds struct {
+	FileStats bool // Per-file change statistics (git log --numstat)
+	Merges    bool // Merge commits, even when the user did not ask for them
+}
+
+// Settings carries run-wide configuration to analyzer factories
+type Settings struct {
+	IgnorePatterns []string
+	Coupling       CouplingOptions
+	Hotspots       HotspotOptions
+	Config         config.Config
+
+	// Repository and revision being analyzed, for analyzers that read file contents
+	Repo     git.Repository `json:"-"`
+	Revision string         // Empty = HEAD
+
+	// Scope of a single command's analysis
+	Depth   int      // Directory depth of module breakdowns (0 = the analyzer's default)
+	Paths   []string // Path patterns (see MatchPath) to limit the analysis to; empty = all files
+	NoBlame bool     // Skip git blame where an estimate without it is possible
+
+	// Incremental is set by the coordinator when results are cached and may
+	// later be updated with new commits, so incremental analyzers keep the
+	// state Update needs; otherwise they can drop it
+	Incremental bool `json:"-"`
+}
+
+// depthOr returns the configured directory depth, or fallback if none is set
+func (s Settings) depthOr(fallback int) int {
+	if s.Depth > 0 {
+		return s.Depth
+	}
+	return fallback
+}
+
+// Factory creates an analyzer configured for a run
+type Factory func(settings Settings) Analyzer
+
+// Registry maps analyzer names to their factories
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+// NewRegistry creates an empty analyzer registry
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// Register adds an analyzer factory under name
+func (r *Registry) Register(name string, factory Factory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.factories[name]; exists {
+		return fmt.Errorf("analyzer %q already registered", name)
+	}
+	r.factories[name] = factory
+	return nil
+}
+
+// Create instantiates the named analyzer
+func (r *Registry) Create(name string, settings Settings) (Analyzer, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown analyzer %q (available: %v)", name, r.Names())
+	}
+	return factory(settings), nil
+}
+
+// Names returns all registered analyzer names in alphabetical order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultRegistry holds the built-in analyzers, which register themselves in init
+var DefaultRegistry = NewRegistry()
+
+// Register adds an analyzer factory to the default registry. It panics on
+// duplicate names, which are programming errors.
+func Register(name string, factory Factory) {
+	if err := DefaultRegistry.Register(name, factory); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,267 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// Granularities of the churn time series
+const (
+	ChurnDay     = "day"
+	ChurnWeek    = "week" // ISO weeks, starting on Monday
+	ChurnMonth   = "month"
+	ChurnQuarter = "quarter"
+)
+
+// ChurnGranularities lists the granularities of the churn time series, finest first
+var ChurnGranularities = []string{ChurnDay, ChurnWeek, ChurnMonth, ChurnQuarter}
+
+// ChurnPeriod sums up the commits of one calendar period
+type ChurnPeriod struct {
+	Start   time.Time // Midnight at the start of the period, wall clock of the bucketing timezone
+	Label   string    // "2024-03-05", "2024-W10", "2024-03" or "2024-Q1"
+	Commits int
+	Authors int
+	Added   int
+	Deleted int
+	Files   int // Distinct files touched
+}
+
+// Churn returns the lines added and deleted in the period
+func (p ChurnPeriod) Churn() int {
+	return p.Added + p.Deleted
+}
+
+// ChurnResults holds the churn time series of a history
+type ChurnResults struct {
+	Granularity string
+	Timezone    string        // Empty when each commit is bucketed in its own timezone
+	Paths       []string      // Path patterns the series is limited to; empty = all files
+	Periods     []ChurnPeriod // Oldest first, including empty periods
+	Total       ChurnPeriod   // Over all periods; Authors and Files are distinct over the whole history
+}
+
+// ChurnOptions configures the churn time series
+type ChurnOptions struct {
+	Granularity string         // One of ChurnGranularities (default ChurnWeek)
+	Location    *time.Location // Timezone to bucket in (nil = each commit's own timezone)
+	Paths       []string       // Path patterns (see MatchPath) files must match; empty = all files
+}
+
+// ChurnLocation resolves a configured timezone: an IANA name, "local", or
+// empty for each commit's own timezone (nil)
+func ChurnLocation(name string) (*time.Location, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case "local":
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// periodStart returns the start of the period containing t, on its own wall clock
+func periodStart(t time.Time, granularity string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch granularity {
+	case ChurnDay:
+		return day
+	case ChurnMonth:
+		return day.AddDate(0, 0, 1-t.Day())
+	case ChurnQuarter:
+		return time.Date(t.Year(), (t.Month()-1)/3*3+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	// Weeks start on Monday
+	return day.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+}
+
+// nextPeriod returns the start of the period after the one starting at start
+func nextPeriod(start time.Time, granularity string) time.Time {
+	switch granularity {
+	case ChurnDay:
+		return start.AddDate(0, 0, 1)
+	case ChurnMonth:
+		return start.AddDate(0, 1, 0)
+	case ChurnQuarter:
+		return start.AddDate(0, 3, 0)
+	}
+	return start.AddDate(0, 0, 7)
+}
+
+// periodLabel names the period starting at start
+func periodLabel(start time.Time, granularity string) string {
+	switch granularity {
+	case ChurnDay:
+		return start.Format("2006-01-02")
+	case ChurnMonth:
+		return start.Format("2006-01")
+	case ChurnQuarter:
+		return fmt.Sprintf("%d-Q%d", start.Year(), (start.Month()+2)/3)
+	}
+	year, week := start.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// AnalyzeChurn buckets commits into calendar periods and counts commits,
+// authors, added and deleted lines and files touched per period. Commits are
+// bucketed by their author time in opts.Location, or in their own timezone
+// if it is nil, so a commit at 23:00 on a Sunday in Tokyo falls into that
+// week. With path patterns, only commits touching a matching file count, and
+// only the lines of matching files; renamed files are matched by their
+// current path. Ignored files never count.
+func AnalyzeChurn(commits []git.Commit, ignorePatterns []string, opts ChurnOptions) (ChurnResults, error) {
+	if opts.Granularity == "" {
+		opts.Granularity = ChurnWeek
+	}
+	if !contains(ChurnGranularities, opts.Granularity) {
+		return ChurnResults{}, fmt.Errorf("unknown granularity %q (want %s)", opts.Granularity, strings.Join(ChurnGranularities, ", "))
+	}
+	results := ChurnResults{Granularity: opts.Granularity, Paths: opts.Paths}
+	if opts.Location != nil {
+		results.Timezone = opts.Location.String()
+	}
+
+	type bucket struct {
+		period  ChurnPeriod
+		authors map[string]bool
+		files   map[string]bool
+	}
+	buckets := make(map[time.Time]*bucket)
+	authors := make(map[string]bool)
+	files := make(map[string]bool)
+
+	// Commits are newest first, so renames are followed back to current paths
+	renames := make(renameTracker)
+	for _, commit := range commits {
+		var added, deleted int
+		var touched []string
+		for _, fc := range commit.FilesChanged {
+			path := renames.current(fc)
+			if shouldIgnoreFile(path, ignorePatterns) || (len(opts.Paths) > 0 && !matchesAny(opts.Paths, path)) {
+				continue
+			}
+			added += fc.LinesAdded
+			deleted += fc.LinesDeleted
+			touched = append(touched, path)
+		}
+		if len(touched) == 0 && len(opts.Paths) > 0 {
+			continue
+		}
+
+		t := commit.Timestamp
+		if opts.Location != nil {
+			t = t.In(opts.Location)
+		}
+		start := periodStart(t, opts.Granularity)
+		b := buckets[start]
+		if b == nil {
+			b = &bucket{
+				period:  ChurnPeriod{Start: start, Label: periodLabel(start, opts.Granularity)},
+				authors: make(map[string]bool),
+				files:   make(map[string]bool),
+			}
+			buckets[start] = b
+		}
+
+		for _, target := range []*ChurnPeriod{&b.period, &results.Total} {
+			target.Commits++
+			target.Added += added
+			target.Deleted += deleted
+		}
+		b.authors[commit.Author.Name] = true
+		authors[commit.Author.Name] = true
+		for _, path := range touched {
+			b.files[path] = true
+			files[path] = true
+		}
+	}
+	if len(buckets) == 0 {
+		return results, nil
+	}
+
+	starts := make([]time.Time, 0, len(buckets))
+	for start := range buckets {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	// Every period from the first commit to the last, so gaps show as such
+	for start := starts[0]; !start.After(starts[len(starts)-1]); start = nextPeriod(start, opts.Granularity) {
+		b := buckets[start]
+		if b == nil {
+			results.Periods = append(results.Periods, ChurnPeriod{Start: start, Label: periodLabel(start, opts.Granularity)})
+			continue
+		}
+		b.period.Authors = len(b.authors)
+		b.period.Files = len(b.files)
+		results.Periods = append(results.Periods, b.period)
+	}
+	results.Total.Authors = len(authors)
+	results.Total.Files = len(files)
+
+	return results, nil
+}
+
+// ChurnAt computes the churn time series with the granularity and timezone
+// of cfg
+func ChurnAt(commits []git.Commit, ignorePatterns []string, paths []string, cfg config.ChurnConfig) (ChurnResults, error) {
+	loc, err := ChurnLocation(cfg.Timezone)
+	if err != nil {
+		return ChurnResults{}, err
+	}
+	return AnalyzeChurn(commits, ignorePatterns, ChurnOptions{Granularity: cfg.Granularity, Location: loc, Paths: paths})
+}
+
+// Type implements AnalysisResult
+func (r *ChurnResults) Type() string { return "churn" }
+
+// Summary implements AnalysisResult
+func (r *ChurnResults) Summary() string {
+	if len(r.Periods) == 0 {
+		return "no commits"
+	}
+	busiest := r.Periods[0]
+	for _, p := range r.Periods[1:] {
+		if p.Churn() > busiest.Churn() {
+			busiest = p
+		}
+	}
+	return fmt.Sprintf("%d %ss, +%d/-%d lines, busiest %s (+%d/-%d)",
+		len(r.Periods), r.Granularity, r.Total.Added, r.Total.Deleted, busiest.Label, busiest.Added, busiest.Deleted)
+}
+
+// churnAnalyzer computes the churn time series
+type churnAnalyzer struct {
+	ignorePatterns []string
+	paths          []string
+	cfg            config.ChurnConfig
+}
+
+func init() {
+	Register("churn", func(s Settings) Analyzer {
+		return churnAnalyzer{ignorePatterns: s.IgnorePatterns, paths: s.Paths, cfg: s.Config.Churn}
+	})
+}
+
+func (churnAnalyzer) Name() string              { return "churn" }
+func (churnAnalyzer) Version() string           { return "1" }
+func (churnAnalyzer) Needs() DataNeeds          { return DataNeeds{FileStats: true} }
+func (churnAnalyzer) Cacheable() bool           { return true }
+func (churnAnalyzer) NewResult() AnalysisResult { return &ChurnResults{} }
+
+func (a churnAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	results, err := ChurnAt(commits, a.ignorePatterns, a.paths, a.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
@@ -0,0 +1,182 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"histui/internal/git"
+)
+
+func TestPeriodStart(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*3600)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name        string
+		t           time.Time
+		granularity string
+		want        time.Time
+	}{
+		{"day on the commit's wall clock", time.Date(2024, 3, 5, 1, 0, 0, 0, tokyo), ChurnDay, date(2024, 3, 5)},
+		{"Sunday night ends the week", time.Date(2024, 3, 10, 23, 0, 0, 0, time.UTC), ChurnWeek, date(2024, 3, 4)},
+		{"Monday starts the week", time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), ChurnWeek, date(2024, 3, 11)},
+		{"week across the new year", time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), ChurnWeek, date(2024, 12, 30)},
+		{"week in the previous ISO year", time.Date(2021, 1, 3, 12, 0, 0, 0, time.UTC), ChurnWeek, date(2020, 12, 28)},
+		{"month", time.Date(2024, 2, 29, 18, 0, 0, 0, time.UTC), ChurnMonth, date(2024, 2, 1)},
+		{"first quarter", time.Date(2024, 3, 31, 23, 0, 0, 0, time.UTC), ChurnQuarter, date(2024, 1, 1)},
+		{"second quarter", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), ChurnQuarter, date(2024, 4, 1)},
+		{"last quarter", time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC), ChurnQuarter, date(2024, 10, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := periodStart(tt.t, tt.granularity); !got.Equal(tt.want) {
+				t.Errorf("periodStart(%s, %s) = %s, want %s", tt.t, tt.granularity, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextPeriodAndLabel(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		start       time.Time
+		granularity string
+		wantNext    time.Time
+		wantLabel   string
+	}{
+		{date(2024, 2, 28), ChurnDay, date(2024, 2, 29), "2024-02-28"},
+		{date(2024, 12, 30), ChurnWeek, date(2025, 1, 6), "2025-W01"},
+		{date(2020, 12, 28), ChurnWeek, date(2021, 1, 4), "2020-W53"},
+		{date(2024, 3, 4), ChurnWeek, date(2024, 3, 11), "2024-W10"},
+		{date(2024, 1, 1), ChurnMonth, date(2024, 2, 1), "2024-01"},
+		{date(2024, 12, 1), ChurnMonth, date(2025, 1, 1), "2024-12"},
+		{date(2024, 4, 1), ChurnQuarter, date(2024, 7, 1), "2024-Q2"},
+		{date(2024, 10, 1), ChurnQuarter, date(2025, 1, 1), "2024-Q4"},
+	}
+
+	for _, tt := range tests {
+		if got := nextPeriod(tt.start, tt.granularity); !got.Equal(tt.wantNext) {
+			t.Errorf("nextPeriod(%s, %s) = %s, want %s", tt.start, tt.granularity, got, tt.wantNext)
+		}
+		if got := periodLabel(tt.start, tt.granularity); got != tt.wantLabel {
+			t.Errorf("periodLabel(%s, %s) = %s, want %s", tt.start, tt.granularity, got, tt.wantLabel)
+		}
+	}
+}
+
+// churnSeries reduces periods to label, commits, authors, added, deleted and files
+func churnSeries(periods []ChurnPeriod) [][]interface{} {
+	var series [][]interface{}
+	for _, p := range periods {
+		series = append(series, []interface{}{p.Label, p.Commits, p.Authors, p.Added, p.Deleted, p.Files})
+	}
+	return series
+}
+
+func TestAnalyzeChurn(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*3600)
+	commit := func(author string, when time.Time, changes ...git.FileChange) git.Commit {
+		return git.Commit{Author: git.Author{Name: author}, Timestamp: when, FilesChanged: changes}
+	}
+
+	// Newest first; api/old.go was renamed to api/new.go on Monday morning in
+	// Tokyo, which is still Sunday in UTC
+	commits := []git.Commit{
+		commit("Bob", time.Date(2024, 3, 25, 10, 0, 0, 0, time.UTC),
+			git.FileChange{Path: "api/new.go", LinesAdded: 2, LinesDeleted: 2},
+			git.FileChange{Path: "README.md", LinesAdded: 7}),
+		commit("Alice", time.Date(2024, 3, 11, 1, 0, 0, 0, tokyo),
+			git.FileChange{Path: "api/new.go", OldPath: "api/old.go", ChangeType: git.ChangeTypeRenamed, LinesAdded: 1}),
+		commit("Alice", time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC),
+			git.FileChange{Path: "api/old.go", LinesAdded: 10},
+			git.FileChange{Path: "web/app.js", LinesAdded: 3}),
+	}
+	ignore := []string{"*.md"}
+
+	tests := []struct {
+		name      string
+		opts      ChurnOptions
+		want      [][]interface{}
+		wantTotal []interface{}
+	}{
+		{
+			"own timezones, empty weeks filled",
+			ChurnOptions{},
+			[][]interface{}{
+				{"2024-W10", 1, 1, 13, 0, 2},
+				{"2024-W11", 1, 1, 1, 0, 1},
+				{"2024-W12", 0, 0, 0, 0, 0},
+				{"2024-W13", 1, 1, 2, 2, 1},
+			},
+			[]interface{}{"", 3, 2, 16, 2, 2},
+		},
+		{
+			"UTC moves the rename into the week before",
+			ChurnOptions{Location: time.UTC},
+			[][]interface{}{
+				{"2024-W10", 2, 1, 14, 0, 2},
+				{"2024-W11", 0, 0, 0, 0, 0},
+				{"2024-W12", 0, 0, 0, 0, 0},
+				{"2024-W13", 1, 1, 2, 2, 1},
+			},
+			[]interface{}{"", 3, 2, 16, 2, 2},
+		},
+		{
+			"paths match renamed files by their current path",
+			ChurnOptions{Granularity: ChurnMonth, Paths: []string{"api/new.go"}},
+			[][]interface{}{{"2024-03", 3, 2, 13, 2, 1}},
+			[]interface{}{"", 3, 2, 13, 2, 1},
+		},
+		{
+			"paths skip commits touching nothing matching",
+			ChurnOptions{Granularity: ChurnDay, Paths: []string{"web/"}},
+			[][]interface{}{{"2024-03-05", 1, 1, 3, 0, 1}},
+			[]interface{}{"", 1, 1, 3, 0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := AnalyzeChurn(commits, ignore, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := churnSeries(results.Periods); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("periods = %v, want %v", got, tt.want)
+			}
+			if got := churnSeries([]ChurnPeriod{results.Total})[0]; !reflect.DeepEqual(got, tt.wantTotal) {
+				t.Errorf("total = %v, want %v", got, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestAnalyzeChurnQuarters(t *testing.T) {
+	commits := []git.Commit{
+		{Timestamp: time.Date(2024, 8, 20, 0, 0, 0, 0, time.UTC), FilesChanged: []git.FileChange{{Path: "a.go", LinesAdded: 1}}},
+		{Timestamp: time.Date(2023, 12, 31, 23, 0, 0, 0, time.UTC), FilesChanged: []git.FileChange{{Path: "a.go", LinesAdded: 1}}},
+	}
+	results, err := AnalyzeChurn(commits, nil, ChurnOptions{Granularity: ChurnQuarter})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var labels []string
+	for _, p := range results.Periods {
+		labels = append(labels, p.Label)
+	}
+	if want := []string{"2023-Q4", "2024-Q1", "2024-Q2", "2024-Q3"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("quarters = %v, want %v", labels, want)
+	}
+
+	if _, err := AnalyzeChurn(commits, nil, ChurnOptions{Granularity: "year"}); err == nil {
+		t.Error("AnalyzeChurn with an unknown granularity succeeded")
+	}
+	if results, err := AnalyzeChurn(nil, nil, ChurnOptions{}); err != nil || len(results.Periods) != 0 {
+		t.Errorf("AnalyzeChurn without commits = %d periods, %v, want none", len(results.Periods), err)
+	}
+}
@@ -0,0 +1,273 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// Commit categories
+const (
+	CategoryFeature  = "feature"
+	CategoryFix      = "fix"
+	CategoryRefactor = "refactor"
+	CategoryTest     = "test"
+	CategoryDocs     = "docs"
+	CategoryChore    = "chore"
+	CategoryOther    = "other"
+)
+
+// Categories lists the commit categories in display order
+var Categories = []string{CategoryFeature, CategoryFix, CategoryRefactor, CategoryTest, CategoryDocs, CategoryChore, CategoryOther}
+
+// conventionalCategories maps Conventional Commits types to categories
+var conventionalCategories = map[string]string{
+	"feat":     CategoryFeature,
+	"fix":      CategoryFix,
+	"refactor": CategoryRefactor,
+	"perf":     CategoryRefactor,
+	"style":    CategoryRefactor,
+	"test":     CategoryTest,
+	"docs":     CategoryDocs,
+	"build":    CategoryChore,
+	"ci":       CategoryChore,
+	"chore":    CategoryChore,
+	"revert":   CategoryChore,
+}
+
+// keywordOrder is the order in which subject keywords are tried, so that
+// "Add tests for ..." is a test rather than a feature
+var keywordOrder = []string{CategoryRefactor, CategoryDocs, CategoryTest, CategoryChore, CategoryFeature}
+
+// Classifier sorts commits into kinds of work
+type Classifier struct {
+	cfg   config.ClassifyConfig
+	szz   config.SZZConfig
+	tests []testConvention
+}
+
+// NewClassifier creates a classifier from the classification, bug fix and
+// test convention settings of cfg
+func NewClassifier(cfg config.Config) (*Classifier, error) {
+	tests, err := compileConventions(cfg.Tests.Conventions)
+	if err != nil {
+		return nil, err
+	}
+	return &Classifier{cfg: cfg.Classify, szz: cfg.SZZ, tests: tests}, nil
+}
+
+// Classify returns the category of a commit. The first rule that applies wins:
+//  1. a Conventional Commits type ("feat:", "fix(api):", ...)
+//  2. merges and reverts are chores
+//  3. the shape of the diff: only renames (refactor), only test files,
+//     only documentation or only build and CI files
+//  4. bug fixes, recognized as in IsBugFix
+//  5. subject keywords
+//  6. commits that only add files are features, anything else is other
+func (c *Classifier) Classify(commit git.Commit) string {
+	if m := conventionalPattern.FindStringSubmatch(commit.Subject); m != nil {
+		if category, ok := conventionalCategories[m[1]]; ok {
+			return category
+		}
+	}
+	if commit.IsMerge || strings.HasPrefix(commit.Subject, "Revert \"") {
+		return CategoryChore
+	}
+	if category := c.diffShape(commit.FilesChanged); category != "" {
+		return category
+	}
+	if IsBugFix(commit, c.szz) {
+		return CategoryFix
+	}
+	for _, category := range keywordOrder {
+		if hasKeyword(commit.Subject, c.cfg.Keywords[category]) {
+			return category
+		}
+	}
+
+	onlyAdded := len(commit.FilesChanged) > 0
+	for _, fc := range commit.FilesChanged {
+		onlyAdded = onlyAdded && fc.ChangeType == git.ChangeTypeAdded
+	}
+	if onlyAdded {
+		return CategoryFeature
+	}
+	return CategoryOther
+}
+
+// ClassifyAll sets the category of every commit
+func (c *Classifier) ClassifyAll(commits []git.Commit) {
+	for i := range commits {
+		commits[i].Category = c.Classify(commits[i])
+	}
+}
+
+// diffShape returns the category implied by the files a commit changed, or
+// "" if they are of mixed kinds
+func (c *Classifier) diffShape(files []git.FileChange) string {
+	if len(files) == 0 {
+		return ""
+	}
+	renames, tests, docs, chores := true, true, true, true
+	for _, fc := range files {
+		renames = renames && fc.ChangeType == git.ChangeTypeRenamed && fc.LinesAdded+fc.LinesDeleted == 0
+		tests = tests && c.isTest(fc.Path)
+		docs = docs && matchesAny(c.cfg.Docs, fc.Path)
+		chores = chores && matchesAny(c.cfg.Chores, fc.Path)
+	}
+	switch {
+	case renames:
+		return CategoryRefactor
+	case tests:
+		return CategoryTest
+	case docs:
+		return CategoryDocs
+	case chores:
+		return CategoryChore
+	}
+	return ""
+}
+
+// isTest reports whether path is a test file
+func (c *Classifier) isTest(path string) bool {
+	for _, convention := range c.tests {
+		if convention.testExpr.MatchString(path) {
+			return true
+		}
+	}
+	return matchesAny(c.cfg.Tests, path)
+}
+
+// matchesAny reports whether path matches any of the patterns (see MatchPath)
+func matchesAny(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if MatchPath(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkMix counts the commits of a period or module per category
+type WorkMix struct {
+	Name    string
+	Commits int
+	Counts  map[string]int
+}
+
+// Share returns the share of commits in a category
+func (m WorkMix) Share(category string) float64 {
+	return normalize(m.Counts[category], m.Commits)
+}
+
+// WorkMixResults holds the kinds of work done per period and per module
+type WorkMixResults struct {
+	Overall WorkMix
+	Periods []WorkMix // Monthly, oldest first
+	Modules []WorkMix // Most commits first
+	Depth   int
+}
+
+// AnalyzeWorkMix counts classified commits per month and per module (the
+// directories depth levels deep that a commit changed). Commits must have
+// been classified, see Classifier.ClassifyAll.
+func AnalyzeWorkMix(commits []git.Commit, ignorePatterns []string, depth int) WorkMixResults {
+	if depth <= 0 {
+		depth = 1
+	}
+	results := WorkMixResults{Overall: WorkMix{Name: "all", Counts: make(map[string]int)}, Depth: depth}
+
+	months := make(map[time.Time]*WorkMix)
+	modules := make(map[string]*WorkMix)
+	add := func(m *WorkMix, category string) {
+		m.Commits++
+		m.Counts[category]++
+	}
+
+	for _, commit := range commits {
+		category := commit.Category
+		if category == "" {
+			category = CategoryOther
+		}
+		add(&results.Overall, category)
+
+		local := commit.Timestamp
+		month := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if months[month] == nil {
+			months[month] = &WorkMix{Name: month.Format("2006-01"), Counts: make(map[string]int)}
+		}
+		add(months[month], category)
+
+		touched := make(map[string]bool)
+		for _, fc := range commit.FilesChanged {
+			if shouldIgnoreFile(fc.Path, ignorePatterns) {
+				continue
+			}
+			module := rollUp(fc.Path, depth)
+			if touched[module] {
+				continue
+			}
+			touched[module] = true
+			if modules[module] == nil {
+				modules[module] = &WorkMix{Name: module, Counts: make(map[string]int)}
+			}
+			add(modules[module], category)
+		}
+	}
+
+	for _, m := range months {
+		results.Periods = append(results.Periods, *m)
+	}
+	sort.Slice(results.Periods, func(i, j int) bool { return results.Periods[i].Name < results.Periods[j].Name })
+
+	for _, m := range modules {
+		results.Modules = append(results.Modules, *m)
+	}
+	sort.Slice(results.Modules, func(i, j int) bool {
+		a, b := results.Modules[i], results.Modules[j]
+		if a.Commits != b.Commits {
+			return a.Commits > b.Commits
+		}
+		return a.Name < b.Name
+	})
+
+	return results
+}
+
+// Type implements AnalysisResult
+func (r *WorkMixResults) Type() string { return "workmix" }
+
+// Summary implements AnalysisResult
+func (r *WorkMixResults) Summary() string {
+	return fmt.Sprintf("%.0f%% features, %.0f%% fixes, %.0f%% refactoring of %d commits",
+		r.Overall.Share(CategoryFeature)*100, r.Overall.Share(CategoryFix)*100,
+		r.Overall.Share(CategoryRefactor)*100, r.Overall.Commits)
+}
+
+// workMixAnalyzer reports the kinds of work done; the coordinator classifies
+// the commits before analyzers run
+type workMixAnalyzer struct {
+	ignorePatterns []string
+	depth          int
+}
+
+func init() {
+	Register("workmix", func(s Settings) Analyzer {
+		return workMixAnalyzer{ignorePatterns: s.IgnorePatterns, depth: s.depthOr(1)}
+	})
+}
+
+func (workMixAnalyzer) Name() string              { return "workmix" }
+func (workMixAnalyzer) Version() string           { return "1" }
+func (workMixAnalyzer) Needs() DataNeeds          { return DataNeeds{FileStats: true} }
+func (workMixAnalyzer) Cacheable() bool           { return true }
+func (workMixAnalyzer) NewResult() AnalysisResult { return &WorkMixResults{} }
+
+func (a workMixAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	results := AnalyzeWorkMix(commits, a.ignorePatterns, a.depth)
+	return &results, nil
+}
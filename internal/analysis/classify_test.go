@@ -0,0 +1,95 @@
+package analysis
+
+import (
+	"testing"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+func newTestClassifier(t *testing.T) *Classifier {
+	t.Helper()
+	c, err := NewClassifier(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestClassify(t *testing.T) {
+	code := []git.FileChange{{Path: "api/handler.go", ChangeType: git.ChangeTypeModified, LinesAdded: 3, LinesDeleted: 1}}
+	added := []git.FileChange{{Path: "api/new.go", ChangeType: git.ChangeTypeAdded, LinesAdded: 10}}
+
+	tests := []struct {
+		name   string
+		commit git.Commit
+		want   string
+	}{
+		{"conventional feat", git.Commit{Subject: "feat: add login", FilesChanged: code}, CategoryFeature},
+		{"conventional scope and breaking change", git.Commit{Subject: "fix(api)!: reject empty tokens", FilesChanged: code}, CategoryFix},
+		{"conventional perf", git.Commit{Subject: "perf: cache parsed templates", FilesChanged: code}, CategoryRefactor},
+		{"conventional type beats diff shape", git.Commit{Subject: "docs: fix typo", FilesChanged: code}, CategoryDocs},
+		{"conventional revert", git.Commit{Subject: "revert: feat: add login", FilesChanged: code}, CategoryChore},
+		{"unknown conventional type", git.Commit{Subject: "wip: Add parser", FilesChanged: added}, CategoryFeature},
+		{"merge", git.Commit{Subject: "Merge branch 'fix-crash'", IsMerge: true, FilesChanged: code}, CategoryChore},
+		{"revert", git.Commit{Subject: "Revert \"Add login\"", FilesChanged: code}, CategoryChore},
+		{"rename only", git.Commit{Subject: "Fix package layout", FilesChanged: []git.FileChange{
+			{Path: "pkg/b.go", OldPath: "a.go", ChangeType: git.ChangeTypeRenamed},
+		}}, CategoryRefactor},
+		{"tests only", git.Commit{Subject: "Fix flaky handler test", FilesChanged: []git.FileChange{{Path: "api/handler_test.go", LinesAdded: 2}}}, CategoryTest},
+		{"docs only", git.Commit{Subject: "Fix install steps", FilesChanged: []git.FileChange{{Path: "README.md", LinesAdded: 2}, {Path: "docs/setup.rst", LinesAdded: 1}}}, CategoryDocs},
+		{"build files only", git.Commit{Subject: "Add lint job", FilesChanged: []git.FileChange{{Path: ".github/workflows/ci.yml"}, {Path: "Makefile"}}}, CategoryChore},
+		{"bug fix", git.Commit{Subject: "Handle nil config crash", FilesChanged: code}, CategoryFix},
+		{"bug fix trailer", git.Commit{Subject: "Check token expiry", Body: "Fixes: #12", FilesChanged: code}, CategoryFix},
+		// Keywords are tried refactor, docs, test, chore, feature
+		{"refactor before feature", git.Commit{Subject: "Extract and add helpers", FilesChanged: code}, CategoryRefactor},
+		{"test before feature", git.Commit{Subject: "Add tests for the parser", FilesChanged: code}, CategoryTest},
+		{"docs before test", git.Commit{Subject: "Comment the test helpers", FilesChanged: code}, CategoryDocs},
+		{"chore before feature", git.Commit{Subject: "Bump deps to support Go 1.22", FilesChanged: code}, CategoryChore},
+		{"keywords are whole words", git.Commit{Subject: "Tweak address parsing", FilesChanged: code}, CategoryOther},
+		{"only added files", git.Commit{Subject: "Parser for TOML", FilesChanged: added}, CategoryFeature},
+		{"nothing applies", git.Commit{Subject: "Tweak timeouts", FilesChanged: code}, CategoryOther},
+		{"no files", git.Commit{Subject: "Tweak timeouts"}, CategoryOther},
+	}
+
+	c := newTestClassifier(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Classify(tt.commit); got != tt.want {
+				t.Errorf("Classify(%q) = %s, want %s", tt.commit.Subject, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffShape(t *testing.T) {
+	renamed := git.FileChange{Path: "b.go", OldPath: "a.go", ChangeType: git.ChangeTypeRenamed}
+	tests := []struct {
+		name  string
+		files []git.FileChange
+		want  string
+	}{
+		{"no files", nil, ""},
+		{"pure renames", []git.FileChange{renamed, {Path: "d/c.go", OldPath: "c.go", ChangeType: git.ChangeTypeRenamed}}, CategoryRefactor},
+		{"rename with edits", []git.FileChange{{Path: "b.go", OldPath: "a.go", ChangeType: git.ChangeTypeRenamed, LinesAdded: 1}}, ""},
+		{"rename and a new file", []git.FileChange{renamed, {Path: "c.go", ChangeType: git.ChangeTypeAdded}}, ""},
+		{"test conventions", []git.FileChange{{Path: "api/handler_test.go"}, {Path: "web/app.spec.ts"}}, CategoryTest},
+		{"test directory", []git.FileChange{{Path: "src/__tests__/app.js"}}, CategoryTest},
+		{"test and code", []git.FileChange{{Path: "api/handler_test.go"}, {Path: "api/handler.go"}}, ""},
+		{"docs", []git.FileChange{{Path: "README.md"}, {Path: "docs/guide/intro.html"}, {Path: "LICENSE"}}, CategoryDocs},
+		{"docs and code", []git.FileChange{{Path: "README.md"}, {Path: "main.go"}}, ""},
+		{"chores", []git.FileChange{{Path: "go.mod"}, {Path: "go.sum"}}, CategoryChore},
+		// A renamed test file is a refactoring before it is a test change
+		{"renamed tests", []git.FileChange{{Path: "b_test.go", OldPath: "a_test.go", ChangeType: git.ChangeTypeRenamed}}, CategoryRefactor},
+		{"code", []git.FileChange{{Path: "main.go"}}, ""},
+	}
+
+	c := newTestClassifier(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.diffShape(tt.files); got != tt.want {
+				t.Errorf("diffShape = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// Code age classes, youngest first
+const (
+	AgeVolatile = "volatile" // Changed very recently and mostly young lines
+	AgeActive   = "active"
+	AgeStable   = "stable"
+	AgeFrozen   = "frozen"
+)
+
+// AgeClasses lists the code age classes, youngest first
+var AgeClasses = []string{AgeVolatile, AgeActive, AgeStable, AgeFrozen}
+
+// AgeBands counts lines per age class, in AgeClasses order
+type AgeBands [4]int
+
+// Total returns the number of lines in all bands
+func (b AgeBands) Total() int {
+	return b[0] + b[1] + b[2] + b[3]
+}
+
+// Median returns the class of the median line
+func (b AgeBands) Median() string {
+	total, seen := b.Total(), 0
+	for i, n := range b {
+		seen += n
+		if 2*seen >= total {
+			return AgeClasses[i]
+		}
+	}
+	return AgeFrozen
+}
+
+// FileAge describes how old a file's code is
+type FileAge struct {
+	Path          string
+	Lines         int
+	LastModified  time.Time     // Zero if the file was not changed in the analyzed history
+	Age           time.Duration // Since the last modification
+	MedianLineAge time.Duration // Zero without blame
+	Bands         AgeBands      // Lines by their own age (blamed files only)
+	Class         string
+}
+
+// DirectoryAge sums up the code age of the files in a directory and below it
+type DirectoryAge struct {
+	Path         string
+	Depth        int
+	Files        int
+	Lines        int
+	LastModified time.Time
+	Bands        AgeBands
+	Class        string // Class of the median line, or of the last modification without blame
+}
+
+// CodeAgeResults holds the code age map of a revision
+type CodeAgeResults struct {
+	Files       []FileAge      // Sorted by path
+	Directories []DirectoryAge // In tree order
+	Overall     DirectoryAge
+	Blame       bool
+	Blamed      int // Files blamed; the others count by their last modification
+	Reference   time.Time
+	Bands       config.AgeConfig
+}
+
+// CodeAgeOptions configures the code age analysis
+type CodeAgeOptions struct {
+	Depth int       // Directory depth of the tree (default 2)
+	Now   time.Time // Reference time for ages (zero = time.Now())
+}
+
+// ageClass returns the class of code last changed age ago
+func ageClass(age time.Duration, cfg config.AgeConfig) string {
+	days := age.Hours() / 24
+	switch {
+	case days <= float64(cfg.VolatileDays):
+		return AgeVolatile
+	case days <= float64(cfg.ActiveDays):
+		return AgeActive
+	case days <= float64(cfg.StableDays):
+		return AgeStable
+	}
+	return AgeFrozen
+}
+
+// bandIndex returns the position of class in AgeClasses
+func bandIndex(class string) int {
+	for i, c := range AgeClasses {
+		if c == class {
+			return i
+		}
+	}
+	return len(AgeClasses) - 1
+}
+
+// AnalyzeCodeAge computes the age of the last modification of every file at
+// the analyzed revision (the keys of lineCounts) and, if blame is given (path
+// -> author time of every line), the median age of its lines. A file is
+// volatile if it changed within cfg.VolatileDays and its median line is not
+// older than cfg.ActiveDays; otherwise its class follows its last
+// modification. Directories are summed up depth levels deep; the lines of
+// files missing from blame count in the band of their file's class.
+func AnalyzeCodeAge(commits []git.Commit, lineCounts map[string]int, blame map[string][]time.Time, ignorePatterns []string, cfg config.AgeConfig, opts CodeAgeOptions) CodeAgeResults {
+	if opts.Depth <= 0 {
+		opts.Depth = 2
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	results := CodeAgeResults{
+		Overall:   DirectoryAge{Path: "./"},
+		Blame:     blame != nil,
+		Reference: opts.Now,
+		Bands:     cfg,
+	}
+
+	// Commits are newest first, so the first change seen is the last modification
+	lastModified := make(map[string]time.Time)
+	renames := make(renameTracker)
+	for _, commit := range commits {
+		for _, fc := range commit.FilesChanged {
+			path := renames.current(fc)
+			if _, seen := lastModified[path]; !seen {
+				lastModified[path] = commit.Timestamp
+			}
+		}
+	}
+
+	directories := make(map[string]*DirectoryAge)
+	for path, lines := range lineCounts {
+		if shouldIgnoreFile(path, ignorePatterns) {
+			continue
+		}
+		f := FileAge{Path: path, Lines: lines, LastModified: lastModified[path]}
+
+		times := blame[path]
+		if len(times) > 0 {
+			results.Blamed++
+			ages := make([]time.Duration, len(times))
+			newest := times[0]
+			for i, t := range times {
+				ages[i] = max(opts.Now.Sub(t), 0)
+				f.Bands[bandIndex(ageClass(ages[i], cfg))]++
+				if t.After(newest) {
+					newest = t
+				}
+			}
+			sort.Slice(ages, func(i, j int) bool { return ages[i] < ages[j] })
+			f.MedianLineAge = ages[len(ages)/2]
+			if f.LastModified.IsZero() {
+				f.LastModified = newest
+			}
+		}
+
+		if f.LastModified.IsZero() {
+			// Older than the analyzed history
+			f.Class = AgeFrozen
+		} else {
+			f.Age = max(opts.Now.Sub(f.LastModified), 0)
+			f.Class = ageClass(f.Age, cfg)
+			if f.Class == AgeVolatile && f.MedianLineAge.Hours()/24 > float64(cfg.ActiveDays) {
+				// A recent touch to old code
+				f.Class = AgeActive
+			}
+		}
+		results.Files = append(results.Files, f)
+
+		bands := f.Bands
+		if results.Blame && bands.Total() == 0 {
+			bands[bandIndex(f.Class)] = f.Lines
+		}
+
+		targets := []*DirectoryAge{&results.Overall}
+		for depth := 1; depth <= opts.Depth; depth++ {
+			dir := rollUp(path, depth)
+			if dir == "./" || (depth > 1 && dir == rollUp(path, depth-1)) {
+				break
+			}
+			if directories[dir] == nil {
+				directories[dir] = &DirectoryAge{Path: dir, Depth: depth}
+			}
+			targets = append(targets, directories[dir])
+		}
+		for _, d := range targets {
+			d.Files++
+			d.Lines += f.Lines
+			if f.LastModified.After(d.LastModified) {
+				d.LastModified = f.LastModified
+			}
+			for i, n := range bands {
+				d.Bands[i] += n
+			}
+		}
+	}
+	sort.Slice(results.Files, func(i, j int) bool { return results.Files[i].Path < results.Files[j].Path })
+
+	results.Overall.classify(opts.Now, cfg)
+	for _, d := range directories {
+		d.classify(opts.Now, cfg)
+		results.Directories = append(results.Directories, *d)
+	}
+
+	// Tree order: a directory is followed by its subdirectories
+	treeKey := func(path string) string { return strings.ReplaceAll(path, "/", "\x00") }
+	sort.Slice(results.Directories, func(i, j int) bool {
+		return treeKey(results.Directories[i].Path) < treeKey(results.Directories[j].Path)
+	})
+
+	return results
+}
+
+// classify sets the class of a directory from its median line, or from its
+// last modification without blame
+func (d *DirectoryAge) classify(now time.Time, cfg config.AgeConfig) {
+	switch {
+	case d.Bands.Total() > 0:
+		d.Class = d.Bands.Median()
+	case d.LastModified.IsZero():
+		d.Class = AgeFrozen
+	default:
+		d.Class = ageClass(now.Sub(d.LastModified), cfg)
+	}
+}
+
+// CodeAgeAt computes the code age map of the files at rev, blaming up to
+// cfg.MaxBlame of them (the largest first) unless noBlame is set
+func CodeAgeAt(repo git.Repository, rev string, commits []git.Commit, ignorePatterns []string, cfg config.AgeConfig, depth int, noBlame bool) (CodeAgeResults, error) {
+	lineCounts, err := repo.GetLineCounts(rev)
+	if err != nil {
+		return CodeAgeResults{}, err
+	}
+
+	var blame map[string][]time.Time
+	if !noBlame {
+		var files []string
+		for path := range lineCounts {
+			if !shouldIgnoreFile(path, ignorePatterns) {
+				files = append(files, path)
+			}
+		}
+		sort.Slice(files, func(i, j int) bool {
+			if lineCounts[files[i]] != lineCounts[files[j]] {
+				return lineCounts[files[i]] > lineCounts[files[j]]
+			}
+			return files[i] < files[j]
+		})
+		if cfg.MaxBlame > 0 && len(files) > cfg.MaxBlame {
+			files = files[:cfg.MaxBlame]
+		}
+
+		blame = make(map[string][]time.Time, len(files))
+		for _, path := range files {
+			lines, err := repo.BlameFile(rev, path)
+			if err != nil {
+				return CodeAgeResults{}, err
+			}
+			times := make([]time.Time, len(lines))
+			for i, line := range lines {
+				times[i] = line.Time
+			}
+			blame[path] = times
+		}
+	}
+
+	return AnalyzeCodeAge(commits, lineCounts, blame, ignorePatterns, cfg, CodeAgeOptions{Depth: depth}), nil
+}
+
+// Type implements AnalysisResult
+func (r *CodeAgeResults) Type() string { return "codeage" }
+
+// Summary implements AnalysisResult
+func (r *CodeAgeResults) Summary() string {
+	total := r.Overall.Bands.Total()
+	if total == 0 {
+		return fmt.Sprintf("%d files, mostly %s", len(r.Files), r.Overall.Class)
+	}
+	parts := make([]string, len(AgeClasses))
+	for i, class := range AgeClasses {
+		parts[i] = fmt.Sprintf("%.0f%% %s", normalize(r.Overall.Bands[i], total)*100, class)
+	}
+	return fmt.Sprintf("%d lines in %d files: %s", total, len(r.Files), strings.Join(parts, ", "))
+}
+
+// codeAgeAnalyzer maps the age of the code at the analyzed revision
+type codeAgeAnalyzer struct {
+	repo           git.Repository
+	revision       string
+	ignorePatterns []string
+	cfg            config.AgeConfig
+	depth          int
+	noBlame        bool
+}
+
+func init() {
+	Register("codeage", func(s Settings) Analyzer {
+		return codeAgeAnalyzer{
+			repo:           s.Repo,
+			revision:       s.Revision,
+			ignorePatterns: s.IgnorePatterns,
+			cfg:            s.Config.Age,
+			depth:          s.depthOr(2),
+			noBlame:        s.NoBlame,
+		}
+	})
+}
+
+func (codeAgeAnalyzer) Name() string              { return "codeage" }
+func (codeAgeAnalyzer) Version() string           { return "1" }
+func (codeAgeAnalyzer) Needs() DataNeeds          { return DataNeeds{FileStats: true} }
+func (codeAgeAnalyzer) Cacheable() bool           { return false } // Ages depend on the current time
+func (codeAgeAnalyzer) NewResult() AnalysisResult { return &CodeAgeResults{} }
+
+func (a codeAgeAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	if a.repo == nil {
+		return nil, fmt.Errorf("codeage analysis needs repository access")
+	}
+	results, err := CodeAgeAt(a.repo, revisionOrHead(a.revision), commits, a.ignorePatterns, a.cfg, a.depth, a.noBlame)
+	if err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
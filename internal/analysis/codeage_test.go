@@ -0,0 +1,184 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+var testAgeConfig = config.AgeConfig{VolatileDays: 30, ActiveDays: 180, StableDays: 730}
+
+func TestAgeClass(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{0, AgeVolatile},
+		{30 * day, AgeVolatile},
+		{30*day + time.Hour, AgeActive},
+		{180 * day, AgeActive},
+		{181 * day, AgeStable},
+		{730 * day, AgeStable},
+		{731 * day, AgeFrozen},
+	}
+
+	for _, tt := range tests {
+		if got := ageClass(tt.age, testAgeConfig); got != tt.want {
+			t.Errorf("ageClass(%s) = %s, want %s", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestAgeBandsMedian(t *testing.T) {
+	tests := []struct {
+		bands AgeBands
+		want  string
+	}{
+		{AgeBands{1, 0, 0, 0}, AgeVolatile},
+		{AgeBands{0, 0, 0, 5}, AgeFrozen},
+		// Half the lines reach the median
+		{AgeBands{2, 0, 0, 2}, AgeVolatile},
+		{AgeBands{1, 1, 1, 2}, AgeStable},
+		{AgeBands{0, 3, 4, 0}, AgeStable},
+		{AgeBands{}, AgeVolatile},
+	}
+
+	for _, tt := range tests {
+		if got := tt.bands.Median(); got != tt.want {
+			t.Errorf("%v.Median() = %s, want %s", tt.bands, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeCodeAge(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	touch := func(when time.Time, changes ...git.FileChange) git.Commit {
+		return git.Commit{Timestamp: when, FilesChanged: changes}
+	}
+
+	// Newest first; api/new.go was renamed from api/old.go
+	commits := []git.Commit{
+		touch(daysAgo(5), git.FileChange{Path: "api/new.go"}, git.FileChange{Path: "web/app.js"}),
+		touch(daysAgo(100), git.FileChange{Path: "api/new.go", OldPath: "api/old.go", ChangeType: git.ChangeTypeRenamed}),
+		touch(daysAgo(400), git.FileChange{Path: "api/old.go"}, git.FileChange{Path: "api/v1/h.go"}),
+	}
+	lineCounts := map[string]int{"api/new.go": 4, "api/v1/h.go": 2, "web/app.js": 6, "README.md": 1}
+	blame := map[string][]time.Time{
+		// A recent touch to mostly old lines
+		"api/new.go":  {daysAgo(5), daysAgo(400), daysAgo(400), daysAgo(800)},
+		"api/v1/h.go": {daysAgo(400), daysAgo(400)},
+		// web/app.js was not blamed
+	}
+
+	results := AnalyzeCodeAge(commits, lineCounts, blame, nil, testAgeConfig, CodeAgeOptions{Depth: 2, Now: now})
+
+	files := make(map[string]FileAge)
+	for _, f := range results.Files {
+		files[f.Path] = f
+	}
+	fileTests := []struct {
+		path  string
+		class string
+		bands AgeBands
+	}{
+		{"api/new.go", AgeActive, AgeBands{1, 0, 2, 1}},
+		{"api/v1/h.go", AgeStable, AgeBands{0, 0, 2, 0}},
+		{"web/app.js", AgeVolatile, AgeBands{}},
+		// Older than the analyzed history
+		{"README.md", AgeFrozen, AgeBands{}},
+	}
+	for _, tt := range fileTests {
+		f := files[tt.path]
+		if f.Class != tt.class || f.Bands != tt.bands {
+			t.Errorf("%s: class %s, bands %v, want %s, %v", tt.path, f.Class, f.Bands, tt.class, tt.bands)
+		}
+	}
+	if got := files["api/new.go"].MedianLineAge; got != 400*24*time.Hour {
+		t.Errorf("api/new.go median line age = %s, want 400 days", got)
+	}
+	if results.Blamed != 2 {
+		t.Errorf("%d files blamed, want 2", results.Blamed)
+	}
+
+	// Files without blame count in the band of their class
+	dirTests := []struct {
+		path  string
+		files int
+		bands AgeBands
+		class string
+	}{
+		{"./", 4, AgeBands{7, 0, 4, 2}, AgeVolatile},
+		{"api/", 2, AgeBands{1, 0, 4, 1}, AgeStable},
+		{"api/v1/", 1, AgeBands{0, 0, 2, 0}, AgeStable},
+		{"web/", 1, AgeBands{6, 0, 0, 0}, AgeVolatile},
+	}
+	dirs := map[string]DirectoryAge{"./": results.Overall}
+	var order []string
+	for _, d := range results.Directories {
+		dirs[d.Path] = d
+		order = append(order, d.Path)
+	}
+	for _, tt := range dirTests {
+		d, ok := dirs[tt.path]
+		if !ok {
+			t.Errorf("directory %s missing", tt.path)
+			continue
+		}
+		if d.Files != tt.files || d.Bands != tt.bands || d.Class != tt.class {
+			t.Errorf("%s: %d files, bands %v, class %s, want %d, %v, %s", tt.path, d.Files, d.Bands, d.Class, tt.files, tt.bands, tt.class)
+		}
+	}
+	if want := []string{"api/", "api/v1/", "web/"}; len(order) != len(want) || order[0] != want[0] || order[1] != want[1] || order[2] != want[2] {
+		t.Errorf("directories in order %v, want %v", order, want)
+	}
+	if !dirs["api/"].LastModified.Equal(daysAgo(5)) {
+		t.Errorf("api/ last modified %s, want %s", dirs["api/"].LastModified, daysAgo(5))
+	}
+}
+
+func TestAnalyzeCodeAgeWithoutBlame(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	commits := []git.Commit{{Timestamp: now.AddDate(0, 0, -200), FilesChanged: []git.FileChange{{Path: "a/x.go"}}}}
+
+	results := AnalyzeCodeAge(commits, map[string]int{"a/x.go": 10}, nil, nil, testAgeConfig, CodeAgeOptions{Now: now})
+	if results.Blame || results.Overall.Bands.Total() != 0 {
+		t.Errorf("blame = %v with bands %v, want no blame and no bands", results.Blame, results.Overall.Bands)
+	}
+	// Directories fall back to the class of their last modification
+	if results.Overall.Class != AgeStable || results.Directories[0].Class != AgeStable {
+		t.Errorf("classes %s, %s, want stable", results.Overall.Class, results.Directories[0].Class)
+	}
+}
+
+func TestCodeAgeAtBlamesLargestFiles(t *testing.T) {
+	r := newTestRepo(t)
+	r.commit("Alice", "Add files", map[string]string{"big.go": lines("b", 5), "mid.go": lines("m", 3), "small.go": lines("s", 1)})
+
+	repo := r.open()
+	commits, _, _, _, err := repo.LoadCommits(git.LoadOptions{IncludeFileStats: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := testAgeConfig
+	cfg.MaxBlame = 2
+
+	results, err := CodeAgeAt(repo, "HEAD", commits, nil, cfg, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.Blamed != 2 {
+		t.Fatalf("%d files blamed, want 2", results.Blamed)
+	}
+	for _, f := range results.Files {
+		if blamed := f.Bands.Total() > 0; blamed != (f.Path != "small.go") {
+			t.Errorf("%s blamed = %v", f.Path, blamed)
+		}
+	}
+	if got := results.Overall.Bands.Total(); got != 9 {
+		t.Errorf("overall counts %d lines, want 9", got)
+	}
+}
@@ -0,0 +1,198 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CodeownersLocations are the paths GitHub and GitLab look for CODEOWNERS at, in order
+var CodeownersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// CodeownersRule is one pattern line of a CODEOWNERS file
+type CodeownersRule struct {
+	Pattern string
+	Owners  []string // Empty for explicitly unowned paths
+	Line    int
+	Section string // GitLab section, empty outside sections
+
+	expr *regexp.Regexp
+}
+
+// Codeowners is a parsed CODEOWNERS file
+type Codeowners struct {
+	Path  string
+	Rules []CodeownersRule
+}
+
+// sectionPattern matches GitLab section headers: "[Name]", "^[Optional]",
+// "[Name][2]" (required approvals), optionally followed by default owners
+var sectionPattern = regexp.MustCompile(`^\^?\[([^\]]+)\](?:\[\d+\])?\s*(.*)$`)
+
+// ParseCodeowners parses a CODEOWNERS file in GitHub or GitLab syntax. In
+// GitLab sections, rules without owners inherit the section's default owners.
+func ParseCodeowners(path string, content []byte) (Codeowners, error) {
+	co := Codeowners{Path: path}
+	section := ""
+	var sectionOwners []string
+
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(stripComment(line))
+		if line == "" {
+			continue
+		}
+
+		if m := sectionPattern.FindStringSubmatch(line); m != nil {
+			section = m[1]
+			sectionOwners = strings.Fields(m[2])
+			continue
+		}
+
+		fields := splitUnescaped(line)
+		rule := CodeownersRule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			Line:    i + 1,
+			Section: section,
+		}
+		if len(rule.Owners) == 0 && section != "" {
+			rule.Owners = sectionOwners
+		}
+
+		expr, err := compileCodeownersPattern(rule.Pattern)
+		if err != nil {
+			return co, fmt.Errorf("%s:%d: invalid pattern %q: %w", path, rule.Line, rule.Pattern, err)
+		}
+		rule.expr = expr
+		co.Rules = append(co.Rules, rule)
+	}
+	return co, nil
+}
+
+// Match returns the rules that apply to a file: the last matching rule, or in
+// GitLab syntax the last matching rule of every section
+func (co Codeowners) Match(path string) []CodeownersRule {
+	last := make(map[string]int)
+	var order []string
+	for i, rule := range co.Rules {
+		if !rule.expr.MatchString(path) {
+			continue
+		}
+		if _, seen := last[rule.Section]; !seen {
+			order = append(order, rule.Section)
+		}
+		last[rule.Section] = i
+	}
+
+	rules := make([]CodeownersRule, 0, len(order))
+	for _, section := range order {
+		rules = append(rules, co.Rules[last[section]])
+	}
+	return rules
+}
+
+// Owners returns the owners of a file across all applicable rules
+func (co Codeowners) Owners(path string) []string {
+	var owners []string
+	for _, rule := range co.Match(path) {
+		for _, owner := range rule.Owners {
+			if !contains(owners, owner) {
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
+
+// compileCodeownersPattern converts a gitignore-style CODEOWNERS pattern into
+// an anchored regular expression over slash-separated paths:
+//   - a leading or inner '/' anchors the pattern at the repository root,
+//     otherwise it matches at any depth
+//   - a trailing '/' matches only directories (everything below them)
+//   - a pattern also matches everything below a directory it names, except
+//     for "dir/*", which only matches files directly in dir
+//   - '*' and '?' match within a segment, '**' across segments
+func compileCodeownersPattern(pattern string) (*regexp.Regexp, error) {
+	p := pattern
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" || p == "**" {
+		return regexp.Compile(`^.*$`)
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case p[i] == '*':
+			expr.WriteString("[^/]*")
+		case p[i] == '?':
+			expr.WriteString("[^/]")
+		case p[i] == '\\' && i+1 < len(p):
+			i++
+			expr.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		default:
+			expr.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+
+	switch {
+	case dirOnly:
+		expr.WriteString("/.*")
+	case strings.HasSuffix(p, "/*"):
+		// Files directly in the directory only
+	default:
+		expr.WriteString("(?:/.*)?")
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
+// stripComment removes a trailing comment; "\#" escapes a literal '#'
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if line[i] == '#' {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// splitUnescaped splits a rule line at whitespace not escaped with '\'
+func splitUnescaped(line string) []string {
+	var fields []string
+	var current strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == ' ':
+			current.WriteString(`\ `)
+			i++
+		case line[i] == ' ' || line[i] == '\t':
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteByte(line[i])
+		}
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
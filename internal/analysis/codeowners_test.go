@@ -0,0 +1,121 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCodeownersPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*", "main.go", true},
+		{"*", "cmd/histui/main.go", true},
+		{"*.go", "cmd/histui/main.go", true},
+		{"*.go", "README.md", false},
+		{"docs/", "docs/index.md", true},
+		{"docs/", "src/docs/index.md", true},
+		{"docs/", "docs", false},
+		{"/docs/", "src/docs/index.md", false},
+		{"/build/logs/", "build/logs/a/b.log", true},
+		{"apps/", "web/apps/main.go", true},
+		{"src/api", "src/api/handler.go", true},
+		{"src/api", "lib/src/api/handler.go", false},
+		{"docs/*", "docs/index.md", true},
+		{"docs/*", "docs/guide/index.md", false},
+		{"**/logs", "build/logs/a.log", true},
+		{"**/logs", "logs/a.log", true},
+		{"src/**/test.go", "src/test.go", true},
+		{"src/**/test.go", "src/a/b/test.go", true},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file10.txt", false},
+		{`my\ file.txt`, "my file.txt", true},
+		{"/", "anything/at/all.go", true},
+	}
+
+	for _, tt := range tests {
+		expr, err := compileCodeownersPattern(tt.pattern)
+		if err != nil {
+			t.Errorf("compileCodeownersPattern(%q): %v", tt.pattern, err)
+			continue
+		}
+		if got := expr.MatchString(tt.path); got != tt.want {
+			t.Errorf("pattern %q matching %q = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseCodeowners(t *testing.T) {
+	github := `# Default owners
+*           @org/core
+
+*.go        @alice @bob   # Go code
+/docs/      @carol
+/docs/internal/
+my\ file.txt @dave
+`
+
+	gitlab := `* @org/core
+
+[Docs] @carol
+docs/
+/README.md @erin
+
+^[Frontend][2] @frank
+*.js
+*.go @grace
+`
+
+	tests := []struct {
+		name    string
+		content string
+		path    string
+		want    []string
+	}{
+		{"github fallback", github, "Makefile", []string{"@org/core"}},
+		{"github last match wins", github, "cmd/main.go", []string{"@alice", "@bob"}},
+		{"github directory", github, "docs/guide.md", []string{"@carol"}},
+		{"github explicitly unowned", github, "docs/internal/notes.md", nil},
+		{"github escaped space", github, "my file.txt", []string{"@dave"}},
+		{"gitlab section default owners", gitlab, "docs/guide.md", []string{"@org/core", "@carol"}},
+		{"gitlab rule owners", gitlab, "README.md", []string{"@org/core", "@erin"}},
+		{"gitlab one rule per section", gitlab, "docs/app.js", []string{"@org/core", "@carol", "@frank"}},
+		{"gitlab optional section", gitlab, "main.go", []string{"@org/core", "@grace"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			co, err := ParseCodeowners("CODEOWNERS", []byte(tt.content))
+			if err != nil {
+				t.Fatalf("ParseCodeowners: %v", err)
+			}
+			if got := co.Owners(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCodeownersSections(t *testing.T) {
+	co, err := ParseCodeowners("CODEOWNERS", []byte("a @x\n\n[One] @y\nb\n^[Two][2]\n# comment\nc @z\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []CodeownersRule{
+		{Pattern: "a", Owners: []string{"@x"}, Line: 1},
+		{Pattern: "b", Owners: []string{"@y"}, Line: 4, Section: "One"},
+		{Pattern: "c", Owners: []string{"@z"}, Line: 7, Section: "Two"},
+	}
+	if len(co.Rules) != len(want) {
+		t.Fatalf("%d rules, want %d", len(co.Rules), len(want))
+	}
+	for i, rule := range co.Rules {
+		rule.expr = nil
+		if !reflect.DeepEqual(rule, want[i]) {
+			t.Errorf("rule %d = %+v, want %+v", i, rule, want[i])
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package analysis
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"histui/internal/git"
+)
+
+// Complexity is an estimate of how hard a file is to understand
+type Complexity struct {
+	Lines       int  // Non-blank lines
+	TotalIndent int  // Sum of the indentation levels of all non-blank lines
+	MaxIndent   int  // Deepest indentation level
+	Decisions   int  // Branches: conditionals, loops, cases and boolean operators
+	Functions   int  // Functions and closures (Go only)
+	Cyclomatic  int  // Decisions plus one path per function
+	Precise     bool // Computed from the syntax tree rather than estimated from text
+}
+
+// MeanIndent returns the average indentation level of non-blank lines
+func (c Complexity) MeanIndent() float64 {
+	if c.Lines == 0 {
+		return 0
+	}
+	return float64(c.TotalIndent) / float64(c.Lines)
+}
+
+// decisionPattern matches branching keywords and boolean operators common to
+// C-like languages, Python, Ruby and shell scripts
+var decisionPattern = regexp.MustCompile(`\b(if|elif|for|foreach|while|until|unless|case|when|catch|except)\b|&&|\|\|`)
+
+// commentPrefixes start lines that are skipped when counting decisions
+var commentPrefixes = []string{"//", "#", "/*", "*", "--", ";"}
+
+// EstimateComplexity estimates the complexity of a file's contents. Go files
+// are measured precisely via go/ast; other files (and Go files that do not
+// parse) fall back to indentation and keyword heuristics.
+func EstimateComplexity(filePath string, content []byte) Complexity {
+	c := textComplexity(content)
+	if path.Ext(filePath) == ".go" {
+		if decisions, functions, ok := goComplexity(content); ok {
+			c.Decisions = decisions
+			c.Functions = functions
+			c.Cyclomatic = decisions + max(functions, 1)
+			c.Precise = true
+		}
+	}
+	return c
+}
+
+// textComplexity measures indentation and counts decision keywords line by line
+func textComplexity(content []byte) Complexity {
+	var c Complexity
+	lines := strings.Split(string(content), "\n")
+	unit := indentUnit(lines)
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		c.Lines++
+
+		level := indentLevel(line, unit)
+		c.TotalIndent += level
+		c.MaxIndent = max(c.MaxIndent, level)
+
+		if !isComment(trimmed) {
+			c.Decisions += len(decisionPattern.FindAllStringIndex(trimmed, -1))
+		}
+	}
+	c.Cyclomatic = c.Decisions + 1
+	return c
+}
+
+// indentUnit guesses how many spaces make one indentation level: the smallest
+// non-zero space indentation in the file, 4 if no line is indented with spaces
+func indentUnit(lines []string) int {
+	unit := 0
+	for _, line := range lines {
+		spaces := len(line) - len(strings.TrimLeft(line, " "))
+		if spaces > 0 && spaces < len(line) && (unit == 0 || spaces < unit) {
+			unit = spaces
+		}
+	}
+	if unit == 0 {
+		return 4
+	}
+	return unit
+}
+
+// indentLevel returns the indentation level of line (a tab is one level)
+func indentLevel(line string, unit int) int {
+	tabs, spaces := 0, 0
+	for _, r := range line {
+		switch r {
+		case '\t':
+			tabs++
+		case ' ':
+			spaces++
+		default:
+			return tabs + spaces/unit
+		}
+	}
+	return tabs + spaces/unit
+}
+
+// isComment reports whether a trimmed line is a comment
+func isComment(line string) bool {
+	for _, prefix := range commentPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// goComplexity counts decision points and functions in Go source; ok is false
+// if the source does not parse
+func goComplexity(content []byte) (decisions, functions int, ok bool) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", content, parser.SkipObjectResolution)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			functions++
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			decisions++
+		case *ast.CaseClause:
+			if n.List != nil { // default
+				decisions++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil { // default
+				decisions++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				decisions++
+			}
+		}
+		return true
+	})
+	return decisions, functions, true
+}
+
+// ComplexityAt estimates the complexity of files at a revision. Files that do
+// not exist there, or are binary, are left out.
+func ComplexityAt(repo git.Repository, rev string, paths []string) (map[string]Complexity, error) {
+	refs := make([]git.FileRef, len(paths))
+	for i, p := range paths {
+		refs[i] = git.FileRef{Rev: rev, Path: p}
+	}
+	contents, err := repo.ReadFiles(refs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]Complexity, len(paths))
+	for i, content := range contents {
+		if content == nil || isBinary(content) {
+			continue
+		}
+		result[paths[i]] = EstimateComplexity(paths[i], content)
+	}
+	return result, nil
+}
+
+// isBinary reports whether content looks binary (contains a NUL byte early on)
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content[:min(len(content), 8000)], 0) >= 0
+}
+
+// ComplexityPoint is the complexity of a file after one commit
+type ComplexityPoint struct {
+	SHA        string
+	Timestamp  time.Time
+	Author     string
+	Path       string // Path at that commit (differs before renames)
+	Complexity Complexity
+}
+
+// ComplexityTrend measures the complexity of a file after every commit that
+// changed it, following renames, oldest first. Commits are newest first.
+func ComplexityTrend(repo git.Repository, commits []git.Commit, filePath string) ([]ComplexityPoint, error) {
+	var points []ComplexityPoint
+	var refs []git.FileRef
+
+	current := filePath
+	for _, commit := range commits {
+		for _, fc := range commit.FilesChanged {
+			if fc.Path != current {
+				continue
+			}
+			points = append(points, ComplexityPoint{
+				SHA:       commit.SHA,
+				Timestamp: commit.Timestamp,
+				Author:    commit.Author.Name,
+				Path:      current,
+			})
+			refs = append(refs, git.FileRef{Rev: commit.SHA, Path: current})
+			if fc.ChangeType == git.ChangeTypeRenamed && fc.OldPath != "" {
+				current = fc.OldPath
+			}
+			break
+		}
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("no commits changed %s", filePath)
+	}
+
+	contents, err := repo.ReadFiles(refs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop commits that deleted the file and reverse to oldest first
+	trend := make([]ComplexityPoint, 0, len(points))
+	for i := len(points) - 1; i >= 0; i-- {
+		if contents[i] == nil {
+			continue
+		}
+		points[i].Complexity = EstimateComplexity(points[i].Path, contents[i])
+		trend = append(trend, points[i])
+	}
+	if len(trend) == 0 {
+		return nil, fmt.Errorf("%s is not a text file in any commit that changed it", filePath)
+	}
+	return trend, nil
+}
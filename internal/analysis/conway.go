@@ -0,0 +1,270 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// TeamShare is one team's part of the changes to a file or module
+type TeamShare struct {
+	Team    string
+	Changes int
+	Share   float64 // Changes relative to all changes by authors in a team
+}
+
+// CrossTeamPair is a strongly coupled file pair whose files are mostly
+// changed by different teams
+type CrossTeamPair struct {
+	FileA, FileB string
+	Score        float64
+	CoChanges    int
+	TeamA, TeamB TeamShare // The team changing each file most
+}
+
+// CrossTeamModules sums the strongly coupled file pairs between two modules
+// that belong to different teams
+type CrossTeamModules struct {
+	ModuleA, ModuleB string
+	TeamA, TeamB     string
+	Pairs            int
+	CoChanges        int
+}
+
+// ModuleTeams lists the teams working on a module
+type ModuleTeams struct {
+	Path       string
+	Changes    int         // File changes by authors in a team
+	Unassigned int         // File changes by authors in no team
+	Teams      []TeamShare // Teams with at least the minimum share, largest first
+}
+
+// ConwayResults holds how well coupling follows team boundaries
+type ConwayResults struct {
+	Pairs       int                // Strongly coupled pairs whose files both have a team
+	Aligned     int                // Of these, pairs whose files belong to the same team
+	CrossTeam   []CrossTeamPair    // Strongest first
+	ModulePairs []CrossTeamModules // Most co-changes first
+	Collisions  []ModuleTeams      // Modules several teams work on, most teams first
+	Unassigned  []string           // Authors in no team, most commits first
+	Depth       int
+}
+
+// Alignment returns the share of strongly coupled pairs that stay within one team
+func (r *ConwayResults) Alignment() float64 {
+	return normalize(r.Aligned, r.Pairs)
+}
+
+// AnalyzeConway adds the author dimension to file coupling: every file and
+// module belongs to the team whose members change it most, and strongly
+// coupled pairs (cfg.MinScore) across teams are reported, per file and per
+// module (directories depth levels deep). Modules where cfg.CollisionTeams or
+// more teams each make at least cfg.MinShare of the changes are collisions.
+// Authors are assigned to teams with ids.TeamOf.
+func AnalyzeConway(commits []git.Commit, coupling CouplingResults, ids *Identities, teamNames []string, ignorePatterns []string, cfg config.ConwayConfig, depth int) ConwayResults {
+	if depth <= 0 {
+		depth = 1
+	}
+	results := ConwayResults{Depth: depth}
+
+	teamOf := make(map[string]string)
+	unassigned := make(map[string]int)
+	fileTeams := make(map[string]map[string]int)
+	moduleTeams := make(map[string]map[string]int)
+	moduleUnassigned := make(map[string]int)
+
+	for _, commit := range commits {
+		name := commit.Author.Name
+		if _, resolved := teamOf[name]; !resolved {
+			teamOf[name], _ = ids.TeamOf(commit.Author, teamNames)
+		}
+		team := teamOf[name]
+		if team == "" {
+			unassigned[name]++
+		}
+		for _, fc := range commit.FilesChanged {
+			if shouldIgnoreFile(fc.Path, ignorePatterns) {
+				continue
+			}
+			module := rollUp(fc.Path, depth)
+			if team == "" {
+				moduleUnassigned[module]++
+				continue
+			}
+			if fileTeams[fc.Path] == nil {
+				fileTeams[fc.Path] = make(map[string]int)
+			}
+			fileTeams[fc.Path][team]++
+			if moduleTeams[module] == nil {
+				moduleTeams[module] = make(map[string]int)
+			}
+			moduleTeams[module][team]++
+		}
+	}
+
+	moduleOwner := make(map[string]string, len(moduleTeams))
+	for module, counts := range moduleTeams {
+		shares := teamShares(counts)
+		moduleOwner[module] = shares[0].Team
+
+		m := ModuleTeams{Path: module, Unassigned: moduleUnassigned[module]}
+		for _, s := range shares {
+			m.Changes += s.Changes
+			if s.Share >= cfg.MinShare {
+				m.Teams = append(m.Teams, s)
+			}
+		}
+		if len(m.Teams) >= max(cfg.CollisionTeams, 2) {
+			results.Collisions = append(results.Collisions, m)
+		}
+	}
+	sort.Slice(results.Collisions, func(i, j int) bool {
+		a, b := results.Collisions[i], results.Collisions[j]
+		if len(a.Teams) != len(b.Teams) {
+			return len(a.Teams) > len(b.Teams)
+		}
+		if a.Changes != b.Changes {
+			return a.Changes > b.Changes
+		}
+		return a.Path < b.Path
+	})
+
+	modulePairs := make(map[[2]string]*CrossTeamModules)
+	for _, pair := range coupling.Pairs {
+		if pair.ScoreValue < cfg.MinScore || fileTeams[pair.FileA] == nil || fileTeams[pair.FileB] == nil {
+			continue
+		}
+		results.Pairs++
+		teamA, teamB := teamShares(fileTeams[pair.FileA])[0], teamShares(fileTeams[pair.FileB])[0]
+		if teamA.Team == teamB.Team {
+			results.Aligned++
+		} else {
+			results.CrossTeam = append(results.CrossTeam, CrossTeamPair{
+				FileA:     pair.FileA,
+				FileB:     pair.FileB,
+				Score:     pair.ScoreValue,
+				CoChanges: pair.CoChanges,
+				TeamA:     teamA,
+				TeamB:     teamB,
+			})
+		}
+
+		moduleA, moduleB := rollUp(pair.FileA, depth), rollUp(pair.FileB, depth)
+		if moduleA > moduleB {
+			moduleA, moduleB = moduleB, moduleA
+		}
+		if moduleA == moduleB || moduleOwner[moduleA] == moduleOwner[moduleB] {
+			continue
+		}
+		key := [2]string{moduleA, moduleB}
+		if modulePairs[key] == nil {
+			modulePairs[key] = &CrossTeamModules{
+				ModuleA: moduleA,
+				ModuleB: moduleB,
+				TeamA:   moduleOwner[moduleA],
+				TeamB:   moduleOwner[moduleB],
+			}
+		}
+		modulePairs[key].Pairs++
+		modulePairs[key].CoChanges += pair.CoChanges
+	}
+	sort.SliceStable(results.CrossTeam, func(i, j int) bool { return results.CrossTeam[i].Score > results.CrossTeam[j].Score })
+
+	for _, m := range modulePairs {
+		results.ModulePairs = append(results.ModulePairs, *m)
+	}
+	sort.Slice(results.ModulePairs, func(i, j int) bool {
+		a, b := results.ModulePairs[i], results.ModulePairs[j]
+		if a.CoChanges != b.CoChanges {
+			return a.CoChanges > b.CoChanges
+		}
+		if a.ModuleA != b.ModuleA {
+			return a.ModuleA < b.ModuleA
+		}
+		return a.ModuleB < b.ModuleB
+	})
+
+	for name := range unassigned {
+		results.Unassigned = append(results.Unassigned, name)
+	}
+	sort.Slice(results.Unassigned, func(i, j int) bool {
+		a, b := results.Unassigned[i], results.Unassigned[j]
+		if unassigned[a] != unassigned[b] {
+			return unassigned[a] > unassigned[b]
+		}
+		return a < b
+	})
+
+	return results
+}
+
+// teamShares turns change counts per team into shares, largest first
+func teamShares(counts map[string]int) []TeamShare {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	shares := make([]TeamShare, 0, len(counts))
+	for team, n := range counts {
+		shares = append(shares, TeamShare{Team: team, Changes: n, Share: normalize(n, total)})
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Changes != shares[j].Changes {
+			return shares[i].Changes > shares[j].Changes
+		}
+		return shares[i].Team < shares[j].Team
+	})
+	return shares
+}
+
+// Type implements AnalysisResult
+func (r *ConwayResults) Type() string { return "conway" }
+
+// Summary implements AnalysisResult
+func (r *ConwayResults) Summary() string {
+	return fmt.Sprintf("%.0f%% of %d strongly coupled pairs within one team, %d module pairs across teams, %d collisions",
+		r.Alignment()*100, r.Pairs, len(r.ModulePairs), len(r.Collisions))
+}
+
+// conwayAnalyzer reports coupling across team boundaries
+type conwayAnalyzer struct {
+	ignorePatterns []string
+	coupling       CouplingOptions
+	teams          map[string][]string
+	aliases        map[string][]string
+	cfg            config.ConwayConfig
+	depth          int
+}
+
+func init() {
+	Register("conway", func(s Settings) Analyzer {
+		return conwayAnalyzer{
+			ignorePatterns: s.IgnorePatterns,
+			coupling:       s.Coupling,
+			teams:          s.Config.Teams,
+			aliases:        s.Config.Ownership.Aliases,
+			cfg:            s.Config.Conway,
+			depth:          s.depthOr(1),
+		}
+	})
+}
+
+func (conwayAnalyzer) Name() string              { return "conway" }
+func (conwayAnalyzer) Version() string           { return "1" }
+func (conwayAnalyzer) Needs() DataNeeds          { return DataNeeds{FileStats: true} }
+func (conwayAnalyzer) Cacheable() bool           { return true }
+func (conwayAnalyzer) NewResult() AnalysisResult { return &ConwayResults{} }
+
+func (a conwayAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	if len(a.teams) == 0 {
+		return nil, fmt.Errorf("conway analysis needs teams in %s", config.FileName)
+	}
+	coupling, err := AnalyzeFileCouplingWithOptions(commits, a.ignorePatterns, a.coupling)
+	if err != nil {
+		return nil, err
+	}
+	results := AnalyzeConway(commits, coupling, NewIdentities(a.aliases, a.teams), TeamNames(a.teams), a.ignorePatterns, a.cfg, a.depth)
+	return &results, nil
+}
@@ -0,0 +1,99 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+func TestTeamShares(t *testing.T) {
+	got := teamShares(map[string]int{"web": 2, "api": 2, "data": 4})
+	want := []TeamShare{
+		{Team: "data", Changes: 4, Share: 0.5},
+		// Ties go by name
+		{Team: "api", Changes: 2, Share: 0.25},
+		{Team: "web", Changes: 2, Share: 0.25},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("teamShares = %+v, want %+v", got, want)
+	}
+}
+
+func TestAnalyzeConway(t *testing.T) {
+	commit := func(author string, paths ...string) git.Commit {
+		c := git.Commit{Author: git.Author{Name: author, Email: "x@example.com"}}
+		for _, path := range paths {
+			c.FilesChanged = append(c.FilesChanged, git.FileChange{Path: path})
+		}
+		return c
+	}
+	var commits []git.Commit
+	for i := 0; i < 3; i++ {
+		commits = append(commits,
+			commit("Alice", "api/a.go", "api/b.go"),
+			commit("Carol", "web/w.js"))
+	}
+	commits = append(commits,
+		commit("Bob", "db/d.go", "api/gen.pb.go"),
+		commit("Carol", "api/c.go"),
+		commit("Carol", "api/c.go"),
+		// In no team
+		commit("Dave", "api/a.go", "web/w.js"))
+
+	teams := map[string][]string{"backend": {"Alice", "Bob"}, "frontend": {"Carol"}}
+	coupling := CouplingResults{Pairs: []FilePair{
+		{FileA: "api/a.go", FileB: "api/b.go", CoChanges: 3, ScoreValue: 0.9},
+		{FileA: "api/a.go", FileB: "web/w.js", CoChanges: 3, ScoreValue: 0.8},
+		{FileA: "api/b.go", FileB: "db/d.go", CoChanges: 2, ScoreValue: 0.7},
+		// Both files belong to frontend, but their modules do not
+		{FileA: "web/w.js", FileB: "api/c.go", CoChanges: 1, ScoreValue: 0.6},
+		// Not strongly coupled, or a file no team changed
+		{FileA: "api/a.go", FileB: "db/d.go", CoChanges: 1, ScoreValue: 0.3},
+		{FileA: "api/a.go", FileB: "misc/x.go", CoChanges: 1, ScoreValue: 0.9},
+	}}
+	cfg := config.ConwayConfig{MinScore: 0.5, MinShare: 0.2, CollisionTeams: 2}
+
+	results := AnalyzeConway(commits, coupling, NewIdentities(nil, teams), TeamNames(teams), []string{"*.pb.go"}, cfg, 1)
+
+	if results.Pairs != 4 || results.Aligned != 3 {
+		t.Errorf("%d of %d pairs aligned, want 3 of 4", results.Aligned, results.Pairs)
+	}
+	wantCross := []CrossTeamPair{{
+		FileA: "api/a.go", FileB: "web/w.js", Score: 0.8, CoChanges: 3,
+		TeamA: TeamShare{Team: "backend", Changes: 3, Share: 1},
+		TeamB: TeamShare{Team: "frontend", Changes: 3, Share: 1},
+	}}
+	if !reflect.DeepEqual(results.CrossTeam, wantCross) {
+		t.Errorf("cross-team pairs = %+v, want %+v", results.CrossTeam, wantCross)
+	}
+
+	wantModules := []CrossTeamModules{{ModuleA: "api/", ModuleB: "web/", TeamA: "backend", TeamB: "frontend", Pairs: 2, CoChanges: 4}}
+	if !reflect.DeepEqual(results.ModulePairs, wantModules) {
+		t.Errorf("module pairs = %+v, want %+v", results.ModulePairs, wantModules)
+	}
+
+	// Ignored files do not count; Dave's change counts as unassigned
+	wantCollisions := []ModuleTeams{{
+		Path:       "api/",
+		Changes:    8,
+		Unassigned: 1,
+		Teams:      []TeamShare{{Team: "backend", Changes: 6, Share: 0.75}, {Team: "frontend", Changes: 2, Share: 0.25}},
+	}}
+	if !reflect.DeepEqual(results.Collisions, wantCollisions) {
+		t.Errorf("collisions = %+v, want %+v", results.Collisions, wantCollisions)
+	}
+	if !reflect.DeepEqual(results.Unassigned, []string{"Dave"}) {
+		t.Errorf("unassigned = %v, want Dave", results.Unassigned)
+	}
+	if got := results.Alignment(); got != 0.75 {
+		t.Errorf("alignment = %v, want 0.75", got)
+	}
+
+	// With a higher bar for collisions, frontend's quarter is not enough
+	cfg.MinShare = 0.3
+	if results := AnalyzeConway(commits, coupling, NewIdentities(nil, teams), TeamNames(teams), []string{"*.pb.go"}, cfg, 1); len(results.Collisions) != 0 {
+		t.Errorf("collisions = %+v, want none", results.Collisions)
+	}
+}
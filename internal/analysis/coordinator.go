@@ -0,0 +1,434 @@
+package analysis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"histui/internal/cache"
+	"histui/internal/git"
+)
+
+// Phase is a stage of the analysis lifecycle
+type Phase int
+
+const (
+	PhaseInit Phase = iota
+	PhaseLoadingCommits
+	PhaseRunningAnalysis
+	PhaseComplete
+	PhaseError
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseInit:
+		return "Init"
+	case PhaseLoadingCommits:
+		return "Loading commits"
+	case PhaseRunningAnalysis:
+		return "Running analysis"
+	case PhaseComplete:
+		return "Complete"
+	case PhaseError:
+		return "Error"
+	default:
+		return "Unknown"
+	}
+}
+
+// LoadMode describes where the commits of a run came from
+type LoadMode int
+
+const (
+	LoadFull        LoadMode = iota // Whole history loaded from git
+	LoadCached                      // Cached commits reused as is
+	LoadIncremental                 // Cached commits extended with new commits
+	LoadRewritten                   // Cached history was rewritten; whole history reloaded
+)
+
+func (m LoadMode) String() string {
+	switch m {
+	case LoadFull:
+		return "Full"
+	case LoadCached:
+		return "Cached"
+	case LoadIncremental:
+		return "Incremental"
+	case LoadRewritten:
+		return "Rewritten"
+	default:
+		return "Unknown"
+	}
+}
+
+// AnalysisStatus reports the coordinator's progress
+type AnalysisStatus struct {
+	Phase       Phase
+	CurrentStep string
+	Progress    float64 // 0.0 to 1.0
+	FromCache   bool    // Whether the data of the current step came from the cache
+	Updated     bool    // Whether the current step's result was updated incrementally
+	Error       error
+}
+
+// AnalysisCoordinator loads commits once and runs a set of analyzers over them
+type AnalysisCoordinator struct {
+	repo       git.Repository
+	analyzers  []Analyzer
+	settings   string // Fingerprint of the analyzer settings
+	classifier *Classifier
+
+	// Cache, if not nil, stores commits and cacheable results between runs
+	Cache cache.CacheManager
+
+	// OnProgress, if set, is called on every status change (serialized)
+	OnProgress func(AnalysisStatus)
+
+	// State
+	mu         sync.Mutex
+	commits    []git.Commit
+	loadMode   LoadMode
+	newCommits int
+	results    map[string]AnalysisResult
+	fromCache  map[string]bool
+	status     AnalysisStatus
+}
+
+// NewCoordinator creates a coordinator running the named analyzers from the
+// registry. store, if not nil, caches commits and results between runs.
+func NewCoordinator(repo git.Repository, registry *Registry, settings Settings, names []string, store cache.CacheManager) (*AnalysisCoordinator, error) {
+	classifier, err := NewClassifier(settings.Config)
+	if err != nil {
+		return nil, err
+	}
+	// Without a cache there is no later run to update results in
+	settings.Incremental = store != nil
+
+	c := &AnalysisCoordinator{
+		repo:       repo,
+		Cache:      store,
+		settings:   settingsFingerprint(settings),
+		classifier: classifier,
+		results:    make(map[string]AnalysisResult),
+		fromCache:  make(map[string]bool),
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		a, err := registry.Create(name, settings)
+		if err != nil {
+			return nil, err
+		}
+		c.analyzers = append(c.analyzers, a)
+	}
+	return c, nil
+}
+
+// Run loads commits matching opts, widened to what the analyzers need, and
+// runs all analyzers in parallel over the shared commits
+func (c *AnalysisCoordinator) Run(opts git.LoadOptions) error {
+	c.setStatus(AnalysisStatus{Phase: PhaseLoadingCommits, CurrentStep: "Loading commits"})
+
+	// git log always reads file stats, so asking for them must not split the
+	// cache into entries holding the same commits
+	loadOpts := opts
+	loadOpts.IncludeFileStats = true
+	for _, a := range c.analyzers {
+		loadOpts.IncludeMerges = loadOpts.IncludeMerges || a.Needs().Merges
+	}
+
+	// Reuse cached commits if they were loaded from the same history and options
+	useCache := false
+	var key cache.Key
+	if c.Cache != nil {
+		var err error
+		key, err = CacheKey(c.repo, loadOpts)
+		useCache = err == nil
+	}
+
+	var commits, added []git.Commit
+	var previous map[string]AnalysisResult
+	if useCache && c.Cache.IsValid(key) {
+		if cached, err := c.Cache.LoadCommits(key); err == nil {
+			commits = cached
+			c.loadMode = LoadCached
+		}
+	} else if useCache && loadOpts.MaxCommits == 0 {
+		// The history grew since the cache was built: load only the new commits
+		if base, ok := c.Cache.Base(key); ok {
+			// A cached head that is no longer an ancestor (or no longer exists)
+			// means the history was rewritten
+			if isAncestor, err := c.repo.IsAncestor(base, key.LatestCommitSHA); err != nil || !isAncestor {
+				c.loadMode = LoadRewritten
+			} else {
+				commits, added, previous = c.loadIncremental(loadOpts, key, base)
+			}
+		}
+	}
+
+	if c.loadMode == LoadFull || c.loadMode == LoadRewritten {
+		loaded, _, _, _, err := c.repo.LoadCommits(loadOpts)
+		if err != nil {
+			err = fmt.Errorf("failed to load commits: %w", err)
+			c.setStatus(AnalysisStatus{Phase: PhaseError, Error: err})
+			return err
+		}
+		commits = loaded
+	}
+
+	// Every analyzer sees the kind of work of each commit
+	c.classifier.ClassifyAll(commits)
+	c.classifier.ClassifyAll(added)
+
+	// A cache that cannot be written only costs speed on the next run
+	if useCache && c.loadMode != LoadCached && c.Cache.SaveCommits(key, commits) != nil {
+		useCache = false
+	}
+	c.commits = commits
+
+	// Analyzers that did not ask for merges only see them if the user did
+	filterMerges := func(commits []git.Commit) []git.Commit {
+		if !loadOpts.IncludeMerges || opts.IncludeMerges {
+			return commits
+		}
+		filtered := make([]git.Commit, 0, len(commits))
+		for _, commit := range commits {
+			if !commit.IsMerge {
+				filtered = append(filtered, commit)
+			}
+		}
+		return filtered
+	}
+	withoutMerges, addedWithoutMerges := filterMerges(commits), filterMerges(added)
+
+	return c.runAnalyzers(useCache, key, previous, func(a Analyzer, onlyNew bool) []git.Commit {
+		switch {
+		case onlyNew && a.Needs().Merges:
+			return added
+		case onlyNew:
+			return addedWithoutMerges
+		case a.Needs().Merges:
+			return commits
+		default:
+			return withoutMerges
+		}
+	})
+}
+
+// loadIncremental loads the commits added since base and prepends them to the
+// cached commits. It also returns the cached results of incremental analyzers,
+// which saving the new commits discards. If anything fails, the load mode is
+// left at LoadFull so that the caller reloads the whole history.
+func (c *AnalysisCoordinator) loadIncremental(opts git.LoadOptions, key cache.Key, base string) ([]git.Commit, []git.Commit, map[string]AnalysisResult) {
+	cached, err := c.Cache.LoadCommits(key)
+	if err != nil {
+		return nil, nil, nil
+	}
+
+	newOpts := opts
+	newOpts.Branch = key.LatestCommitSHA
+	newOpts.Exclude = append(append([]string(nil), opts.Exclude...), base)
+	added, _, _, _, err := c.repo.LoadCommits(newOpts)
+	if err != nil {
+		return nil, nil, nil
+	}
+
+	previous := make(map[string]AnalysisResult)
+	for _, a := range c.analyzers {
+		if _, ok := a.(IncrementalAnalyzer); !ok || !a.Cacheable() {
+			continue
+		}
+		result := a.NewResult()
+		if ok, _ := c.Cache.LoadResult(key, a.Name(), a.Version(), c.settings, result); ok {
+			previous[a.Name()] = result
+		}
+	}
+
+	c.loadMode = LoadIncremental
+	c.newCommits = len(added)
+	// Commits are kept newest first
+	return append(append(make([]git.Commit, 0, len(added)+len(cached)), added...), cached...), added, previous
+}
+
+// runAnalyzers runs every analyzer in its own goroutine and collects results,
+// taking cacheable results from the cache when possible and updating previous
+// results with only the new commits when the analyzer supports it
+func (c *AnalysisCoordinator) runAnalyzers(useCache bool, key cache.Key, previous map[string]AnalysisResult, commitsFor func(a Analyzer, onlyNew bool) []git.Commit) error {
+	total := len(c.analyzers)
+	c.setStatus(AnalysisStatus{
+		Phase:       PhaseRunningAnalysis,
+		CurrentStep: "Running analyzers",
+		FromCache:   c.loadMode == LoadCached,
+	})
+
+	var wg sync.WaitGroup
+	errs := make([]error, total)
+	done := 0
+	for i, a := range c.analyzers {
+		wg.Add(1)
+		go func(i int, a Analyzer) {
+			defer wg.Done()
+
+			cacheable := useCache && a.Cacheable()
+			result, cached := AnalysisResult(nil), false
+			if cacheable {
+				result = a.NewResult()
+				cached, _ = c.Cache.LoadResult(key, a.Name(), a.Version(), c.settings, result)
+			}
+
+			var err error
+			updated := false
+			if prev, ok := previous[a.Name()]; ok && !cached {
+				result, err = a.(IncrementalAnalyzer).Update(prev, commitsFor(a, true))
+				updated = err == nil
+				if errors.Is(err, ErrFullRebuild) {
+					err = nil
+				}
+			}
+			if !cached && !updated && err == nil {
+				result, err = a.Analyze(commitsFor(a, false))
+			}
+			if !cached && err == nil && cacheable {
+				// Failing to cache a result does not fail the analysis
+				_ = c.Cache.SaveResult(key, a.Name(), a.Version(), c.settings, result)
+			}
+
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			done++
+			if err != nil {
+				errs[i] = fmt.Errorf("%s analyzer failed: %w", a.Name(), err)
+			} else {
+				c.results[a.Name()] = result
+				c.fromCache[a.Name()] = cached
+			}
+			c.notify(AnalysisStatus{
+				Phase:       PhaseRunningAnalysis,
+				CurrentStep: a.Name(),
+				Progress:    float64(done) / float64(total),
+				FromCache:   cached,
+				Updated:     updated,
+			})
+		}(i, a)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		c.setStatus(AnalysisStatus{Phase: PhaseError, Error: err})
+		return err
+	}
+
+	c.setStatus(AnalysisStatus{Phase: PhaseComplete, Progress: 1})
+	return nil
+}
+
+// Commits returns the commits loaded by the last run
+func (c *AnalysisCoordinator) Commits() []git.Commit {
+	return c.commits
+}
+
+// CommitsFromCache reports whether the last run took its commits from the cache
+func (c *AnalysisCoordinator) CommitsFromCache() bool {
+	return c.loadMode == LoadCached
+}
+
+// LoadMode reports how the last run obtained its commits
+func (c *AnalysisCoordinator) LoadMode() LoadMode {
+	return c.loadMode
+}
+
+// NewCommits returns how many commits an incremental run added to the cache
+func (c *AnalysisCoordinator) NewCommits() int {
+	return c.newCommits
+}
+
+// FromCache reports whether the named analyzer's result came from the cache
+func (c *AnalysisCoordinator) FromCache(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fromCache[name]
+}
+
+// Result returns the result of the named analyzer
+func (c *AnalysisCoordinator) Result(name string) (AnalysisResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.results[name]
+	return r, ok
+}
+
+// Analyzers returns the analyzers in the order they were requested
+func (c *AnalysisCoordinator) Analyzers() []Analyzer {
+	return c.analyzers
+}
+
+// Status returns the current analysis status
+func (c *AnalysisCoordinator) Status() AnalysisStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+// setStatus records and reports a status change
+func (c *AnalysisCoordinator) setStatus(status AnalysisStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify(status)
+}
+
+// notify records and reports a status change; c.mu must be held
+func (c *AnalysisCoordinator) notify(status AnalysisStatus) {
+	c.status = status
+	if c.OnProgress != nil {
+		c.OnProgress(status)
+	}
+}
+
+// CacheKey identifies the history and options commits are loaded with in
+// repo. Excluded revisions are resolved too, as moving one (fetching the
+// branch of "check --since origin/main") changes the history without moving
+// the head.
+func CacheKey(repo git.Repository, opts git.LoadOptions) (cache.Key, error) {
+	sha, err := repo.ResolveRevision(revisionOrHead(opts.Branch))
+	if err != nil {
+		return cache.Key{}, err
+	}
+	resolved := opts
+	if len(opts.Exclude) > 0 {
+		resolved.Exclude = make([]string, len(opts.Exclude))
+		for i, rev := range opts.Exclude {
+			if resolved.Exclude[i], err = repo.ResolveRevision(rev); err != nil {
+				return cache.Key{}, err
+			}
+		}
+	}
+	return cache.Key{LatestCommitSHA: sha, LoadOptions: fingerprint(resolved), Options: opts}, nil
+}
+
+// settingsFingerprint identifies the settings that influence analyzer results
+func settingsFingerprint(settings Settings) string {
+	// Parallelism and spilling change how results are computed, not what they are
+	settings.Coupling.Workers = 0
+	settings.Coupling.SpillThreshold = 0
+	settings.Coupling.SpillDir = ""
+	return fingerprint(settings)
+}
+
+// fingerprint returns a short hash of the JSON encoding of v
+func fingerprint(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,220 @@
+package analysis
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"histui/internal/cache"
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// testRepo is a throwaway git repository whose commits are a day apart
+type testRepo struct {
+	t    *testing.T
+	dir  string
+	date time.Time
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found on PATH")
+	}
+	r := &testRepo{t: t, dir: t.TempDir(), date: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	r.git("init", "-q", "-b", "main")
+	return r
+}
+
+// git runs a git command in the repository and returns its trimmed output
+func (r *testRepo) git(args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL=/dev/null",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_COMMITTER_NAME=Committer",
+		"GIT_COMMITTER_EMAIL=committer@example.com",
+		"GIT_COMMITTER_DATE="+r.date.Format(time.RFC3339),
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commit writes files, deleting those with empty content, and commits them
+// as author a day after the previous commit. It returns the commit's SHA.
+func (r *testRepo) commit(author, message string, files map[string]string) string {
+	r.t.Helper()
+	r.date = r.date.Add(24 * time.Hour)
+	for path, content := range files {
+		full := filepath.Join(r.dir, path)
+		if content == "" {
+			r.git("rm", "-q", path)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			r.t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			r.t.Fatal(err)
+		}
+		r.git("add", path)
+	}
+	email := strings.ToLower(strings.ReplaceAll(author, " ", ".")) + "@example.com"
+	r.git("commit", "-q", "--allow-empty", "-m", message,
+		"--author", fmt.Sprintf("%s <%s>", author, email),
+		"--date", r.date.Format(time.RFC3339))
+	return r.git("rev-parse", "HEAD")
+}
+
+// open opens the repository with the CLI implementation
+func (r *testRepo) open() git.Repository {
+	r.t.Helper()
+	repo, err := git.NewCLIRepository(r.dir)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	return repo
+}
+
+// lines returns n numbered lines of content, tagged so that files differ
+func lines(tag string, n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "%s %d\n", tag, i)
+	}
+	return b.String()
+}
+
+// runCoordinator runs analyzers over the repository and returns the coordinator
+func runCoordinator(t *testing.T, repo git.Repository, store cache.CacheManager, opts git.LoadOptions, names ...string) *AnalysisCoordinator {
+	t.Helper()
+	settings := Settings{Config: config.Default(), Repo: repo}
+	settings.Coupling.MinCoChanges = 1
+	c, err := NewCoordinator(repo, DefaultRegistry, settings, names, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Run(opts); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// sameResults fails the test if the stats and coupling results of two runs differ
+func sameResults(t *testing.T, got, want *AnalysisCoordinator) {
+	t.Helper()
+	gotStats, _ := got.Result("stats")
+	wantStats, _ := want.Result("stats")
+	gs, ws := *gotStats.(*RepositoryStats), *wantStats.(*RepositoryStats)
+	if !gs.FirstCommit.Equal(ws.FirstCommit) || !gs.LastCommit.Equal(ws.LastCommit) {
+		t.Errorf("stats span %s..%s, want %s..%s", gs.FirstCommit, gs.LastCommit, ws.FirstCommit, ws.LastCommit)
+	}
+	gs.FirstCommit, gs.LastCommit, ws.FirstCommit, ws.LastCommit = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+	if !reflect.DeepEqual(gs, ws) {
+		t.Errorf("stats = %+v, want %+v", gs, ws)
+	}
+
+	gotCoupling, _ := got.Result("coupling")
+	wantCoupling, _ := want.Result("coupling")
+	gc, wc := gotCoupling.(*CouplingResults), wantCoupling.(*CouplingResults)
+	if !reflect.DeepEqual(pairsByName(gc.Pairs), pairsByName(wc.Pairs)) {
+		t.Errorf("coupling pairs = %+v, want %+v", gc.Pairs, wc.Pairs)
+	}
+	if !reflect.DeepEqual(gc.FileTotalChanges, wc.FileTotalChanges) {
+		t.Errorf("file changes = %v, want %v", gc.FileTotalChanges, wc.FileTotalChanges)
+	}
+}
+
+func TestCoordinatorIncrementalMatchesFull(t *testing.T) {
+	r := newTestRepo(t)
+	r.commit("Alice", "Add api and db", map[string]string{"api/a.go": lines("a", 3), "db/b.go": lines("b", 3)})
+	r.commit("Bob", "Change both", map[string]string{"api/a.go": lines("a", 4), "db/b.go": lines("b", 5)})
+	r.commit("Alice", "Add docs", map[string]string{"README.md": lines("r", 2)})
+
+	repo := r.open()
+	store := cache.NewFileCache(t.TempDir(), 0)
+	names := []string{"stats", "coupling"}
+
+	first := runCoordinator(t, repo, store, git.LoadOptions{}, names...)
+	if first.LoadMode() != LoadFull {
+		t.Fatalf("first run load mode = %s, want Full", first.LoadMode())
+	}
+	if again := runCoordinator(t, repo, store, git.LoadOptions{}, names...); again.LoadMode() != LoadCached {
+		t.Fatalf("unchanged history load mode = %s, want Cached", again.LoadMode())
+	}
+
+	// New commits touch cached pairs and add new files and authors
+	r.commit("Carol", "Change api, db and docs", map[string]string{"api/a.go": lines("a", 6), "db/b.go": lines("b", 2), "README.md": lines("r", 3)})
+	r.commit("Bob", "Remove docs", map[string]string{"README.md": "", "api/c.go": lines("c", 1)})
+
+	incremental := runCoordinator(t, repo, store, git.LoadOptions{}, names...)
+	if incremental.LoadMode() != LoadIncremental || incremental.NewCommits() != 2 {
+		t.Fatalf("load mode = %s with %d new commits, want Incremental with 2", incremental.LoadMode(), incremental.NewCommits())
+	}
+	sameResults(t, incremental, runCoordinator(t, repo, nil, git.LoadOptions{}, names...))
+
+	// Amending the cached head rewrites history: the cache must not be extended
+	r.git("commit", "-q", "--amend", "-m", "Remove docs, reworded")
+	r.commit("Alice", "Change api", map[string]string{"api/a.go": lines("a", 7), "api/c.go": lines("c", 2)})
+
+	rewritten := runCoordinator(t, repo, store, git.LoadOptions{}, names...)
+	if rewritten.LoadMode() != LoadRewritten {
+		t.Fatalf("load mode after amend = %s, want Rewritten", rewritten.LoadMode())
+	}
+	sameResults(t, rewritten, runCoordinator(t, repo, nil, git.LoadOptions{}, names...))
+}
+
+func TestCoordinatorCacheKeyFollowsExcludedRefs(t *testing.T) {
+	r := newTestRepo(t)
+	r.commit("Alice", "Base", map[string]string{"a.go": lines("a", 1)})
+	r.git("branch", "upstream")
+	r.commit("Alice", "First", map[string]string{"a.go": lines("a", 2), "b.go": lines("b", 1)})
+	r.commit("Bob", "Second", map[string]string{"a.go": lines("a", 3), "c.go": lines("c", 1)})
+
+	repo := r.open()
+	store := cache.NewFileCache(t.TempDir(), 0)
+	opts := git.LoadOptions{Exclude: []string{"upstream"}}
+
+	if c := runCoordinator(t, repo, store, opts, "stats"); len(c.Commits()) != 2 {
+		t.Fatalf("%d commits since upstream, want 2", len(c.Commits()))
+	}
+
+	// Fetching moves the excluded ref while the head stays put
+	r.git("branch", "-f", "upstream", "HEAD~1")
+	c := runCoordinator(t, repo, store, opts, "stats")
+	if c.LoadMode() == LoadCached {
+		t.Error("cached commits reused after the excluded ref moved")
+	}
+	if len(c.Commits()) != 1 {
+		t.Errorf("%d commits since the moved upstream, want 1", len(c.Commits()))
+	}
+}
+
+func TestCoordinatorKeepsEntriesPerLoadOptions(t *testing.T) {
+	r := newTestRepo(t)
+	r.commit("Alice", "Add a", map[string]string{"a.go": lines("a", 2)})
+	r.commit("Bob", "Change a", map[string]string{"a.go": lines("a", 3)})
+
+	repo := r.open()
+	store := cache.NewFileCache(t.TempDir(), 0)
+
+	// ohshit loads merges too, which the stats-only run does not
+	runCoordinator(t, repo, store, git.LoadOptions{}, "stats")
+	runCoordinator(t, repo, store, git.LoadOptions{}, "stats", "ohshit")
+
+	c := runCoordinator(t, repo, store, git.LoadOptions{}, "stats")
+	if c.LoadMode() != LoadCached || !c.FromCache("stats") {
+		t.Errorf("load mode = %s, stats from cache = %v, want both cached", c.LoadMode(), c.FromCache("stats"))
+	}
+}
@@ -1,160 +1,383 @@
-package analysis
-
-import (
-	"histui/internal/git"
-	"path/filepath"
-	"sort"
-)
-
-// FilePair represents two files that change together
-type FilePair struct {
-	FileA      string
-	FileB      string
-	CoChanges  int
-	ScoreValue float64
-}
-
-// CouplingResults holds the complete coupling analysis
-type CouplingResults struct {
-	Pairs            []FilePair
-	FileTotalChanges map[string]int
-}
-
-// AnalyzeFileCoupling analyzes which files change together across commits
-func AnalyzeFileCoupling(commits []git.Commit, ignorePatterns []string) CouplingResults {
-	// Track total changes per file
-	fileTotalChanges := make(map[string]int)
-
-	// Track co-changes between file pairs
-	// Key format: "fileA|fileB" (alphabetically sorted)
-	pairCoChanges := make(map[string]int)
-	pairFiles := make(map[string][2]string)
-
-	// Process each commit
-	for _, commit := range commits {
-		files := commit.FilesChanged
-
-		// Skip single-file commits (no coupling possible)
-		if len(files) < 2 {
-			if len(files) == 1 {
-				fileTotalChanges[files[0].Path]++
-			}
-			continue
-		}
-
-		// Count individual file changes (skip ignored files)
-		validFiles := []string{}
-		for _, file := range files {
-			if !shouldIgnoreFile(file.Path, ignorePatterns) {
-				fileTotalChanges[file.Path]++
-				validFiles = append(validFiles, file.Path)
-			}
-		}
-
-		// Update files to only include non-ignored files
-		files = make([]git.FileChange, len(validFiles))
-		for i, path := range validFiles {
-			files[i] = git.FileChange{Path: path}
-		}
-
-		// Count co-changes for all file pairs in this commit
-		for i := 0; i < len(files); i++ {
-			for j := i + 1; j < len(files); j++ {
-				fileA := files[i].Path
-				fileB := files[j].Path
-
-				// Create sorted pair key
-				pairKey := makePairKey(fileA, fileB)
-				pairCoChanges[pairKey]++
-				pairFiles[pairKey] = [2]string{fileA, fileB}
-			}
-		}
-	}
-
-	// Calculate coupling scores
-	// Calculate coupling scores
-	var pairs []FilePair
-	for pairKey, coChanges := range pairCoChanges {
-		files := pairFiles[pairKey]
-		fileA := files[0]
-		fileB := files[1]
-
-		changesA := fileTotalChanges[fileA]
-		changesB := fileTotalChanges[fileB]
-
-		// Skip pairs with insufficient data (less than 3 co-changes)
-		// This prevents single coincidental changes from showing as "critical coupling"
-		if coChanges < 3 {
-			continue
-		}
-
-		// Coupling score = co-changes / min(changesA, changesB)
-		minChanges := min(changesA, changesB)
-		score := 0.0
-		if minChanges > 0 {
-			score = float64(coChanges) / float64(minChanges)
-		}
-
-		pairs = append(pairs, FilePair{
-			FileA:      fileA,
-			FileB:      fileB,
-			CoChanges:  coChanges,
-			ScoreValue: score,
-		})
-	}
-
-	// Sort by coupling score (descending)
-	sort.Slice(pairs, func(i, j int) bool {
-		return pairs[i].ScoreValue > pairs[j].ScoreValue
-	})
-
-	return CouplingResults{
-		Pairs:            pairs,
-		FileTotalChanges: fileTotalChanges,
-	}
-}
-
-// makePairKey creates a consistent key for file pairs (alphabetically sorted)
-func makePairKey(fileA, fileB string) string {
-	if fileA < fileB {
-		return fileA + "|" + fileB
-	}
-	return fileB + "|" + fileA
-}
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
-// GetCouplingStrength returns a human-readable coupling strength category
-func GetCouplingStrength(score float64) string {
-	switch {
-	case score >= 0.8:
-		return "Critical"
-	case score >= 0.5:
-		return "Strong"
-	case score >= 0.2:
-		return "Moderate"
-	default:
-		return "Weak"
-	}
-}
-
-// shouldIgnoreFile checks if a file matches any ignore pattern
-func shouldIgnoreFile(filePath string, patterns []string) bool {
-	for _, pattern := range patterns {
-		matched, err := filepath.Match(pattern, filepath.Base(filePath))
-		if err == nil && matched {
-			return true
-		}
-		// Also try matching full path
-		matched, err = filepath.Match(pattern, filePath)
-		if err == nil && matched {
-			return true
-		}
-	}
-	return false
-}
+package analysis
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"histui/internal/git"
+)
+
+// FilePair represents two files that change together
+type FilePair struct {
+	FileA      string
+	FileB      string
+	CoChanges  int
+	ScoreValue float64
+}
+
+// CouplingResults holds the complete coupling analysis
+type CouplingResults struct {
+	Pairs            []FilePair
+	FileTotalChanges map[string]int
+	Counts           *CouplingCounts `json:",omitempty"` // Raw counters, if kept
+}
+
+// CouplingCounts holds the raw co-change counters behind a coupling result so
+// that new commits can be merged in without re-reading the whole history
+type CouplingCounts struct {
+	Files []string          // Interned file paths; the index is the file ID
+	Pairs map[uint64]uint32 // Co-changes of every pair, keyed by pairKey
+}
+
+// couplingCountsJSON is the encoded form of CouplingCounts. A JSON object
+// would spend a quoted 20-digit key on every pair, so the counters are
+// packed into a binary blob instead: pairs in key order, each as the
+// difference to the previous key followed by its count, both as uvarints.
+type couplingCountsJSON struct {
+	Files []string
+	Pairs []byte
+}
+
+// MarshalJSON implements json.Marshaler
+func (c CouplingCounts) MarshalJSON() ([]byte, error) {
+	keys := make([]uint64, 0, len(c.Pairs))
+	for key := range c.Pairs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	packed := make([]byte, 0, len(keys)*4)
+	previous := uint64(0)
+	for _, key := range keys {
+		packed = binary.AppendUvarint(packed, key-previous)
+		packed = binary.AppendUvarint(packed, uint64(c.Pairs[key]))
+		previous = key
+	}
+	return json.Marshal(couplingCountsJSON{Files: c.Files, Pairs: packed})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (c *CouplingCounts) UnmarshalJSON(data []byte) error {
+	var encoded couplingCountsJSON
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+
+	pairs := make(map[uint64]uint32)
+	key, packed := uint64(0), encoded.Pairs
+	for len(packed) > 0 {
+		delta, n := binary.Uvarint(packed)
+		if n <= 0 {
+			return errors.New("corrupt coupling counters")
+		}
+		count, m := binary.Uvarint(packed[n:])
+		if m <= 0 || count > math.MaxUint32 {
+			return errors.New("corrupt coupling counters")
+		}
+		key += delta
+		pairs[key] = uint32(count)
+		packed = packed[n+m:]
+	}
+	*c = CouplingCounts{Files: encoded.Files, Pairs: pairs}
+	return nil
+}
+
+// CouplingOptions tunes how co-changes are counted
+type CouplingOptions struct {
+	MinCoChanges   int    // Pairs with fewer co-changes are dropped (default 3)
+	Workers        int    // Parallel counting shards (0 or 1 = serial)
+	SpillThreshold int    // Distinct pairs held in memory per shard before spilling to disk (0 = never spill)
+	SpillDir       string // Directory for spill files (default: system temp dir)
+	KeepCounts     bool   // Keep raw counters in the results for incremental updates
+}
+
+// AnalyzeFileCoupling analyzes which files change together across commits
+func AnalyzeFileCoupling(commits []git.Commit, ignorePatterns []string) CouplingResults {
+	// In-memory counting cannot fail
+	results, _ := AnalyzeFileCouplingWithOptions(commits, ignorePatterns, CouplingOptions{})
+	return results
+}
+
+// AnalyzeFileCouplingWithOptions analyzes which files change together across
+// commits. Files are interned to integer IDs and pairs are counted under
+// packed integer keys, optionally in parallel shards and spilling to disk.
+func AnalyzeFileCouplingWithOptions(commits []git.Commit, ignorePatterns []string, opts CouplingOptions) (CouplingResults, error) {
+	return UpdateFileCoupling(CouplingResults{}, commits, ignorePatterns, opts)
+}
+
+// UpdateFileCoupling merges the co-changes of new commits into a previous
+// result. The previous result must have been computed with KeepCounts (or be
+// empty); otherwise ErrFullRebuild is returned.
+func UpdateFileCoupling(previous CouplingResults, commits []git.Commit, ignorePatterns []string, opts CouplingOptions) (CouplingResults, error) {
+	if previous.Counts == nil && len(previous.FileTotalChanges) > 0 {
+		return CouplingResults{}, ErrFullRebuild
+	}
+	if opts.MinCoChanges <= 0 {
+		// Skip pairs with insufficient data (less than 3 co-changes)
+		// This prevents single coincidental changes from showing as "critical coupling"
+		opts.MinCoChanges = 3
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+
+	// Track total changes per file, continuing from the previous counters
+	fileTotalChanges := make(map[string]int, len(previous.FileTotalChanges))
+	for path, n := range previous.FileTotalChanges {
+		fileTotalChanges[path] = n
+	}
+	files := newFileInterner()
+	if previous.Counts != nil {
+		for _, path := range previous.Counts.Files {
+			files.id(path)
+		}
+	}
+
+	// Reduce each commit to the sorted IDs of its non-ignored files
+	commitFiles := make([][]uint32, 0, len(commits))
+	ignored := make(map[string]bool)
+	for _, commit := range commits {
+		changes := commit.FilesChanged
+
+		// Skip single-file commits (no coupling possible)
+		if len(changes) < 2 {
+			if len(changes) == 1 {
+				fileTotalChanges[changes[0].Path]++
+			}
+			continue
+		}
+
+		ids := make([]uint32, 0, len(changes))
+		for _, file := range changes {
+			skip, seen := ignored[file.Path]
+			if !seen {
+				skip = shouldIgnoreFile(file.Path, ignorePatterns)
+				ignored[file.Path] = skip
+			}
+			if !skip {
+				fileTotalChanges[file.Path]++
+				ids = append(ids, files.id(file.Path))
+			}
+		}
+		if len(ids) < 2 {
+			continue
+		}
+
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		commitFiles = append(commitFiles, dedupeSorted(ids))
+	}
+
+	// Count co-changes for all file pairs. Shard s owns the pairs whose lower
+	// file ID is congruent to s, so shards never share a key.
+	counters := make([]*pairCounter, opts.Workers)
+	errs := make([]error, opts.Workers)
+	var wg sync.WaitGroup
+	for s := 0; s < opts.Workers; s++ {
+		counters[s] = newPairCounter(opts.SpillThreshold, opts.SpillDir)
+	}
+	if previous.Counts != nil {
+		for key, n := range previous.Counts.Pairs {
+			idA, _ := unpackPairKey(key)
+			counters[idA%uint32(opts.Workers)].counts[key] += n
+		}
+	}
+	for s := 0; s < opts.Workers; s++ {
+		wg.Add(1)
+		go func(shard int) {
+			defer wg.Done()
+			errs[shard] = countPairs(commitFiles, counters[shard], shard, opts.Workers)
+		}(s)
+	}
+	wg.Wait()
+	for _, counter := range counters {
+		defer counter.cleanup()
+	}
+	if err := errors.Join(errs...); err != nil {
+		return CouplingResults{}, err
+	}
+
+	var counts *CouplingCounts
+	if opts.KeepCounts {
+		counts = &CouplingCounts{Files: files.paths, Pairs: make(map[uint64]uint32)}
+	}
+
+	// Calculate coupling scores
+	var pairs []FilePair
+	for _, counter := range counters {
+		err := counter.each(func(key uint64, count uint32) {
+			if counts != nil {
+				counts.Pairs[key] = count
+			}
+
+			coChanges := int(count)
+			if coChanges < opts.MinCoChanges {
+				return
+			}
+
+			idA, idB := unpackPairKey(key)
+			fileA, fileB := files.path(idA), files.path(idB)
+			if fileB < fileA {
+				fileA, fileB = fileB, fileA
+			}
+
+			// Coupling score = co-changes / min(changesA, changesB)
+			minChanges := min(fileTotalChanges[fileA], fileTotalChanges[fileB])
+			score := 0.0
+			if minChanges > 0 {
+				score = float64(coChanges) / float64(minChanges)
+			}
+
+			pairs = append(pairs, FilePair{
+				FileA:      fileA,
+				FileB:      fileB,
+				CoChanges:  coChanges,
+				ScoreValue: score,
+			})
+		})
+		if err != nil {
+			return CouplingResults{}, err
+		}
+	}
+
+	// Sort by coupling score (descending), then co-changes, then paths for stable output
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].ScoreValue != pairs[j].ScoreValue {
+			return pairs[i].ScoreValue > pairs[j].ScoreValue
+		}
+		if pairs[i].CoChanges != pairs[j].CoChanges {
+			return pairs[i].CoChanges > pairs[j].CoChanges
+		}
+		if pairs[i].FileA != pairs[j].FileA {
+			return pairs[i].FileA < pairs[j].FileA
+		}
+		return pairs[i].FileB < pairs[j].FileB
+	})
+
+	return CouplingResults{
+		Pairs:            pairs,
+		FileTotalChanges: fileTotalChanges,
+		Counts:           counts,
+	}, nil
+}
+
+// countPairs counts the pairs owned by one shard across all commits
+func countPairs(commitFiles [][]uint32, counter *pairCounter, shard, shards int) error {
+	for _, ids := range commitFiles {
+		for i := 0; i < len(ids); i++ {
+			if int(ids[i]%uint32(shards)) != shard {
+				continue
+			}
+			for j := i + 1; j < len(ids); j++ {
+				if err := counter.add(pairKey(ids[i], ids[j])); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// dedupeSorted removes adjacent duplicates from a sorted ID slice
+func dedupeSorted(ids []uint32) []uint32 {
+	out := ids[:1]
+	for _, id := range ids[1:] {
+		if id != out[len(out)-1] {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// GetCouplingStrength returns a human-readable coupling strength category
+func GetCouplingStrength(score float64) string {
+	switch {
+	case score >= 0.8:
+		return "Critical"
+	case score >= 0.5:
+		return "Strong"
+	case score >= 0.2:
+		return "Moderate"
+	default:
+		return "Weak"
+	}
+}
+
+// shouldIgnoreFile checks if a file matches any ignore pattern
+func shouldIgnoreFile(filePath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		matched, err := filepath.Match(pattern, filepath.Base(filePath))
+		if err == nil && matched {
+			return true
+		}
+		// Also try matching full path
+		matched, err = filepath.Match(pattern, filePath)
+		if err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Type implements AnalysisResult
+func (r *CouplingResults) Type() string { return "coupling" }
+
+// Summary implements AnalysisResult
+func (r *CouplingResults) Summary() string {
+	if len(r.Pairs) == 0 {
+		return "No coupled file pairs"
+	}
+	top := r.Pairs[0]
+	return fmt.Sprintf("%d coupled file pairs, strongest: %s <-> %s (%.2f)",
+		len(r.Pairs), top.FileA, top.FileB, top.ScoreValue)
+}
+
+// couplingAnalyzer detects files that change together
+type couplingAnalyzer struct {
+	ignorePatterns []string
+	opts           CouplingOptions
+}
+
+func init() {
+	Register("coupling", func(s Settings) Analyzer {
+		opts := s.Coupling
+		// Counters are only worth their memory and cache space if the result
+		// will be updated; spilled counters do not fit in memory at all
+		opts.KeepCounts = s.Incremental && opts.SpillThreshold == 0
+		return couplingAnalyzer{ignorePatterns: s.IgnorePatterns, opts: opts}
+	})
+}
+
+func (couplingAnalyzer) Name() string              { return "coupling" }
+func (couplingAnalyzer) Version() string           { return "2" }
+func (couplingAnalyzer) Needs() DataNeeds          { return DataNeeds{FileStats: true} }
+func (couplingAnalyzer) Cacheable() bool           { return true }
+func (couplingAnalyzer) NewResult() AnalysisResult { return &CouplingResults{} }
+
+func (a couplingAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	results, err := AnalyzeFileCouplingWithOptions(commits, a.ignorePatterns, a.opts)
+	if err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
+
+func (a couplingAnalyzer) Update(previous AnalysisResult, commits []git.Commit) (AnalysisResult, error) {
+	results, err := UpdateFileCoupling(*previous.(*CouplingResults), commits, a.ignorePatterns, a.opts)
+	if err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
@@ -0,0 +1,85 @@
+package analysis
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"histui/internal/git"
+)
+
+// commitsTouching returns one commit per list of changed paths
+func commitsTouching(changes ...[]string) []git.Commit {
+	commits := make([]git.Commit, len(changes))
+	for i, paths := range changes {
+		for _, path := range paths {
+			commits[i].FilesChanged = append(commits[i].FilesChanged, git.FileChange{Path: path, LinesAdded: 1})
+		}
+	}
+	return commits
+}
+
+func TestCouplingCountsJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts CouplingCounts
+	}{
+		{"empty", CouplingCounts{Pairs: map[uint64]uint32{}}},
+		{"one pair", CouplingCounts{Files: []string{"a.go", "b.go"}, Pairs: map[uint64]uint32{pairKey(0, 1): 3}}},
+		{
+			"many pairs",
+			CouplingCounts{
+				Files: []string{"a.go", "b.go", "c.go"},
+				Pairs: map[uint64]uint32{
+					pairKey(0, 1):             1,
+					pairKey(0, 2):             1 << 20,
+					pairKey(1, 2):             4294967295,
+					pairKey(7, 1<<31):         2,
+					pairKey(1<<32-2, 1<<32-1): 9,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.counts)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var decoded CouplingCounts
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", data, err)
+			}
+			if !reflect.DeepEqual(decoded, tt.counts) {
+				t.Errorf("round trip = %+v, want %+v", decoded, tt.counts)
+			}
+		})
+	}
+}
+
+func TestCouplingCountsJSONCorrupt(t *testing.T) {
+	// A key delta without its count
+	data, _ := json.Marshal(couplingCountsJSON{Pairs: []byte{0x05}})
+	var decoded CouplingCounts
+	if err := json.Unmarshal(data, &decoded); err == nil {
+		t.Errorf("Unmarshal of truncated counters succeeded: %+v", decoded)
+	}
+}
+
+func TestCouplingKeepsCountsOnlyWhenIncremental(t *testing.T) {
+	commits := commitsTouching([]string{"a.go", "b.go"}, []string{"a.go", "b.go"}, []string{"a.go", "b.go", "c.go"})
+	for _, incremental := range []bool{false, true} {
+		a, err := DefaultRegistry.Create("coupling", Settings{Incremental: incremental})
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := a.Analyze(commits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if kept := result.(*CouplingResults).Counts != nil; kept != incremental {
+			t.Errorf("Incremental %v: counts kept = %v", incremental, kept)
+		}
+	}
+}
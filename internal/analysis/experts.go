@@ -0,0 +1,259 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// Weights of the expertise measures in an expert's score
+const (
+	expertWeightCommits = 0.4
+	expertWeightLines   = 0.2
+	expertWeightBlame   = 0.4
+)
+
+// Expert is one author's knowledge of a file or directory
+type Expert struct {
+	Author     string
+	Commits    int
+	Recency    float64   // Commits weighted by age: a commit one half-life old counts half
+	Lines      int       // Lines added and deleted
+	Blamed     int       // Lines the author last changed at the analyzed revision
+	LastChange time.Time // Most recent change to the file or directory
+	LastActive time.Time // Most recent commit anywhere (zero if the author only appears in blame)
+	Inactive   bool      // No commit anywhere within the inactivity period; false if LastActive is unknown
+	Score      float64   // Weighted share of recency-weighted commits, lines and blamed lines
+}
+
+// ExpertResults ranks the experts of a file or directory
+type ExpertResults struct {
+	Path      string
+	Files     int      // Files under Path at the analyzed revision
+	Blamed    int      // Files blamed (0 without blame)
+	Experts   []Expert // Highest score first
+	Reference time.Time
+}
+
+// ExpertOptions configures the expertise lookup
+type ExpertOptions struct {
+	HalfLifeDays   int       // See config.ExpertsConfig
+	InactiveMonths int       // Months without commits after which an expert is inactive (0 = never)
+	Now            time.Time // Reference time for recency and inactivity (zero = time.Now())
+}
+
+// UnderPath reports whether path is target or lies in the directory target
+// ("" or "." is the whole repository)
+func UnderPath(path, target string) bool {
+	target = strings.TrimSuffix(strings.TrimPrefix(target, "./"), "/")
+	if target == "" || target == "." {
+		return true
+	}
+	return path == target || strings.HasPrefix(path, target+"/")
+}
+
+// inTarget reports whether path counts towards target: ignored files only
+// count when they are the target itself
+func inTarget(path, target string, ignorePatterns []string) bool {
+	if !UnderPath(path, target) {
+		return false
+	}
+	return path == strings.TrimPrefix(target, "./") || !shouldIgnoreFile(path, ignorePatterns)
+}
+
+// AnalyzeExperts ranks the authors of the files under target by their
+// recency-weighted commits, the lines they changed and, if blame is given,
+// the lines they last changed at the analyzed revision (author -> lines).
+// Renames are followed, so history under old paths counts.
+func AnalyzeExperts(commits []git.Commit, target string, blame map[string]int, ignorePatterns []string, opts ExpertOptions) ExpertResults {
+	return rankExperts(commits, []string{target}, map[string]map[string]int{target: blame}, ignorePatterns, opts)[target]
+}
+
+// rankExperts ranks the experts of every target as AnalyzeExperts does, in a
+// single pass over commits. blame holds the blamed lines per author of the
+// targets that were blamed.
+func rankExperts(commits []git.Commit, targets []string, blame map[string]map[string]int, ignorePatterns []string, opts ExpertOptions) map[string]ExpertResults {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	halfLife := time.Duration(opts.HalfLifeDays) * 24 * time.Hour
+	inactiveAfter := time.Duration(opts.InactiveMonths) * 30 * 24 * time.Hour
+
+	// Targets by the path they stand for, so that a changed file only looks
+	// up itself and its parent directories
+	byPath := make(map[string][]string)
+	experts := make(map[string]map[string]*Expert, len(targets))
+	for _, target := range targets {
+		if experts[target] != nil {
+			continue
+		}
+		key := strings.TrimSuffix(strings.TrimPrefix(target, "./"), "/")
+		if key == "." {
+			key = ""
+		}
+		byPath[key] = append(byPath[key], target)
+		experts[target] = make(map[string]*Expert)
+	}
+	expert := func(target, name string) *Expert {
+		if experts[target][name] == nil {
+			experts[target][name] = &Expert{Author: name}
+		}
+		return experts[target][name]
+	}
+	lastActive := make(map[string]time.Time)
+
+	renames := make(renameTracker)
+	lines := make(map[string]int)
+	for _, commit := range commits {
+		name := commit.Author.Name
+		if commit.Timestamp.After(lastActive[name]) {
+			lastActive[name] = commit.Timestamp
+		}
+
+		clear(lines)
+		for _, fc := range commit.FilesChanged {
+			path := renames.current(fc)
+			for dir := path; ; dir = parentDir(dir) {
+				for _, target := range byPath[dir] {
+					if inTarget(path, target, ignorePatterns) {
+						lines[target] += fc.LinesAdded + fc.LinesDeleted
+					}
+				}
+				if dir == "" {
+					break
+				}
+			}
+		}
+
+		for target, n := range lines {
+			e := expert(target, name)
+			e.Commits++
+			e.Lines += n
+			if commit.Timestamp.After(e.LastChange) {
+				e.LastChange = commit.Timestamp
+			}
+			if halfLife > 0 {
+				age := max(opts.Now.Sub(commit.Timestamp), 0)
+				e.Recency += math.Pow(0.5, float64(age)/float64(halfLife))
+			} else {
+				e.Recency++
+			}
+		}
+	}
+
+	results := make(map[string]ExpertResults, len(experts))
+	for target, targetExperts := range experts {
+		for name, n := range blame[target] {
+			expert(target, name).Blamed += n
+		}
+		results[target] = scoreExperts(target, targetExperts, lastActive, inactiveAfter, opts.Now)
+	}
+	return results
+}
+
+// parentDir returns the directory containing path, or "" at the top level
+func parentDir(path string) string {
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// scoreExperts turns the expertise collected for target into shares and ranks it
+func scoreExperts(target string, experts map[string]*Expert, lastActive map[string]time.Time, inactiveAfter time.Duration, now time.Time) ExpertResults {
+	results := ExpertResults{Path: target, Reference: now}
+
+	var totalRecency float64
+	var totalLines, totalBlamed int
+	for name, e := range experts {
+		e.LastActive = lastActive[name]
+		// Authors known only from blame may have committed after the
+		// analyzed history was cut off, so their activity is unknown
+		e.Inactive = inactiveAfter > 0 && !e.LastActive.IsZero() && now.Sub(e.LastActive) > inactiveAfter
+		totalRecency += e.Recency
+		totalLines += e.Lines
+		totalBlamed += e.Blamed
+	}
+
+	for _, e := range experts {
+		var score, weights float64
+		if totalRecency > 0 {
+			score += expertWeightCommits * e.Recency / totalRecency
+			weights += expertWeightCommits
+		}
+		if totalLines > 0 {
+			score += expertWeightLines * normalize(e.Lines, totalLines)
+			weights += expertWeightLines
+		}
+		if totalBlamed > 0 {
+			score += expertWeightBlame * normalize(e.Blamed, totalBlamed)
+			weights += expertWeightBlame
+		}
+		if weights > 0 {
+			e.Score = score / weights
+		}
+		results.Experts = append(results.Experts, *e)
+	}
+	sort.Slice(results.Experts, func(i, j int) bool {
+		a, b := results.Experts[i], results.Experts[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Author < b.Author
+	})
+
+	return results
+}
+
+// ExpertsAt ranks the experts of the files under target at rev, blaming up
+// to cfg.Experts.MaxBlame of them (the largest first) unless noBlame is set
+func ExpertsAt(repo git.Repository, rev, target string, commits []git.Commit, ignorePatterns []string, cfg config.Config, noBlame bool) (ExpertResults, error) {
+	lineCounts, err := repo.GetLineCounts(rev)
+	if err != nil {
+		return ExpertResults{}, err
+	}
+
+	var files []string
+	for path := range lineCounts {
+		if inTarget(path, target, ignorePatterns) {
+			files = append(files, path)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if lineCounts[files[i]] != lineCounts[files[j]] {
+			return lineCounts[files[i]] > lineCounts[files[j]]
+		}
+		return files[i] < files[j]
+	})
+
+	var blame map[string]int
+	blamed := 0
+	if !noBlame {
+		blame = make(map[string]int)
+		for _, path := range files {
+			if cfg.Experts.MaxBlame > 0 && blamed >= cfg.Experts.MaxBlame {
+				break
+			}
+			authors, err := repo.BlameAuthors(rev, path)
+			if err != nil {
+				return ExpertResults{}, err
+			}
+			for name, lines := range authors {
+				blame[name] += lines
+			}
+			blamed++
+		}
+	}
+
+	results := AnalyzeExperts(commits, target, blame, ignorePatterns, ExpertOptions{
+		HalfLifeDays:   cfg.Experts.HalfLifeDays,
+		InactiveMonths: cfg.Knowledge.OrphanMonths,
+	})
+	results.Files = len(files)
+	results.Blamed = blamed
+	return results, nil
+}
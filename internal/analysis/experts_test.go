@@ -0,0 +1,135 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"histui/internal/git"
+)
+
+func TestUnderPath(t *testing.T) {
+	tests := []struct {
+		path, target string
+		want         bool
+	}{
+		{"api/handler.go", "", true},
+		{"api/handler.go", ".", true},
+		{"api/handler.go", "api", true},
+		{"api/handler.go", "api/", true},
+		{"api/handler.go", "./api", true},
+		{"api/handler.go", "api/handler.go", true},
+		{"apiv2/handler.go", "api", false},
+		{"api", "api/handler.go", false},
+		{"internal/api/handler.go", "api", false},
+	}
+
+	for _, tt := range tests {
+		if got := UnderPath(tt.path, tt.target); got != tt.want {
+			t.Errorf("UnderPath(%q, %q) = %v, want %v", tt.path, tt.target, got, tt.want)
+		}
+	}
+}
+
+// expertCommit is a commit by author changing files, each by one added line
+func expertCommit(author string, when time.Time, changes ...git.FileChange) git.Commit {
+	for i := range changes {
+		if changes[i].LinesAdded == 0 {
+			changes[i].LinesAdded = 1
+		}
+	}
+	return git.Commit{Author: git.Author{Name: author}, Timestamp: when, FilesChanged: changes}
+}
+
+func TestAnalyzeExperts(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	// Newest first; api/old.go was renamed to api/new.go
+	commits := []git.Commit{
+		expertCommit("Alice", daysAgo(1), git.FileChange{Path: "api/new.go", LinesAdded: 4}),
+		expertCommit("Carol", daysAgo(10), git.FileChange{Path: "web/app.js"}),
+		expertCommit("Alice", daysAgo(30), git.FileChange{Path: "api/new.go", OldPath: "api/old.go", ChangeType: git.ChangeTypeRenamed}),
+		expertCommit("Bob", daysAgo(60), git.FileChange{Path: "api/old.go", LinesAdded: 10, LinesDeleted: 6}),
+		expertCommit("Bob", daysAgo(400), git.FileChange{Path: "api/old.go"}, git.FileChange{Path: "api/lib.gen.go", LinesAdded: 50}),
+		expertCommit("Dave", daysAgo(500), git.FileChange{Path: "api/other.go"}),
+	}
+	blame := map[string]int{"Alice": 5, "Bob": 20, "Erin": 10}
+
+	results := AnalyzeExperts(commits, "api/", blame, []string{"*.gen.go"}, ExpertOptions{HalfLifeDays: 30, InactiveMonths: 12, Now: now})
+
+	byName := make(map[string]Expert)
+	for _, e := range results.Experts {
+		byName[e.Author] = e
+	}
+	if _, ok := byName["Carol"]; ok {
+		t.Error("Carol never touched api/ but is an expert")
+	}
+
+	tests := []struct {
+		author   string
+		commits  int
+		lines    int
+		blamed   int
+		recency  float64
+		inactive bool
+	}{
+		// The commits before the rename count for api/new.go
+		{"Alice", 2, 5, 5, math.Pow(0.5, 1.0/30) + 0.5, false},
+		// Lines of ignored files do not count
+		{"Bob", 2, 17, 20, 0.25 + math.Pow(0.5, 400.0/30), false},
+		{"Dave", 1, 1, 0, math.Pow(0.5, 500.0/30), true},
+		// Known from blame only: when Erin last committed is unknown
+		{"Erin", 0, 0, 10, 0, false},
+	}
+	for _, tt := range tests {
+		e, ok := byName[tt.author]
+		if !ok {
+			t.Errorf("%s is not an expert", tt.author)
+			continue
+		}
+		if e.Commits != tt.commits || e.Lines != tt.lines || e.Blamed != tt.blamed {
+			t.Errorf("%s: %d commits, %d lines, %d blamed, want %d, %d, %d",
+				tt.author, e.Commits, e.Lines, e.Blamed, tt.commits, tt.lines, tt.blamed)
+		}
+		if math.Abs(e.Recency-tt.recency) > 1e-9 {
+			t.Errorf("%s: recency %v, want %v", tt.author, e.Recency, tt.recency)
+		}
+		if e.Inactive != tt.inactive {
+			t.Errorf("%s: inactive = %v, want %v", tt.author, e.Inactive, tt.inactive)
+		}
+	}
+	if !byName["Erin"].LastActive.IsZero() {
+		t.Errorf("Erin last active %s, want unknown", byName["Erin"].LastActive)
+	}
+
+	// Scores are weighted shares and ranked highest first
+	total := 0.0
+	for i, e := range results.Experts {
+		total += e.Score
+		if i > 0 && e.Score > results.Experts[i-1].Score {
+			t.Errorf("%s ranked below %s with a higher score", e.Author, results.Experts[i-1].Author)
+		}
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("scores add up to %v, want 1", total)
+	}
+	// Recent commits outweigh Bob's older, larger changes
+	if results.Experts[0].Author != "Alice" {
+		t.Errorf("top expert %s, want Alice", results.Experts[0].Author)
+	}
+}
+
+func TestAnalyzeExpertsIgnoredTarget(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	commits := []git.Commit{expertCommit("Alice", now, git.FileChange{Path: "gen/lib.gen.go"})}
+
+	// An ignored file still has experts when it is asked for by name
+	results := AnalyzeExperts(commits, "gen/lib.gen.go", nil, []string{"*.gen.go"}, ExpertOptions{Now: now})
+	if len(results.Experts) != 1 || results.Experts[0].Score != 1 {
+		t.Errorf("experts = %+v, want Alice alone", results.Experts)
+	}
+	if results := AnalyzeExperts(commits, "gen/", nil, []string{"*.gen.go"}, ExpertOptions{Now: now}); len(results.Experts) != 0 {
+		t.Errorf("experts of an ignored directory = %+v, want none", results.Experts)
+	}
+}
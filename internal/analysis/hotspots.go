@@ -0,0 +1,284 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// HotspotOptions configures the hotspot analysis
+type HotspotOptions struct {
+	Window       time.Duration // Only count changes this far back from the newest commit (0 = all history)
+	Depth        int           // Roll files up into directories this many levels deep (0 = files)
+	ByComplexity bool          // Measure size by estimated complexity instead of line count
+	ByDefects    bool          // Weight scores by defect density (see AnalyzeSZZ)
+}
+
+// Hotspot is a file (or directory) that changes often and is large
+type Hotspot struct {
+	Path       string
+	Changes    int     // Commits that changed the file (or a file in the directory) within the window
+	Churn      int     // Lines added plus deleted within the window
+	Lines      int     // Line count at the analyzed revision
+	Complexity int     // Cyclomatic complexity at the analyzed revision, if measured
+	Defects    int     // Bug-introducing commits, if measured
+	Density    float64 // Bug-introducing commits per 1000 lines, if measured
+	Score      float64 // 0.0 to 1.0, relative to the other hotspots
+}
+
+// HotspotResults holds the ranked hotspots, highest score first
+type HotspotResults struct {
+	Hotspots     []Hotspot
+	Since        time.Time // Start of the window (zero = all history)
+	Depth        int
+	ByComplexity bool
+	ByDefects    bool
+}
+
+// AnalyzeHotspots ranks files by combining how often and how much they changed
+// with their current size. Files missing from lineCounts (deleted or binary)
+// are skipped; changes made before a rename are credited to the current path.
+// complexity, if not nil, holds the cyclomatic complexity of the files and
+// replaces the line count as size. defects, if not nil, holds the
+// bug-introducing commits per file and weights the score by defect density.
+//
+// Score = activity * size, where activity averages the change count and the
+// churn and every factor is normalized by its maximum over all candidates.
+// With defects, the score is further multiplied by (1 + density) / 2, so that
+// defect-free files keep half their score.
+func AnalyzeHotspots(commits []git.Commit, lineCounts, complexity, defects map[string]int, ignorePatterns []string, opts HotspotOptions) HotspotResults {
+	results := HotspotResults{Depth: opts.Depth, ByComplexity: complexity != nil, ByDefects: defects != nil}
+	if len(commits) == 0 {
+		return results
+	}
+	if opts.Window > 0 {
+		results.Since = commits[0].Timestamp.Add(-opts.Window)
+	}
+
+	renames := make(renameTracker)
+	byPath := make(map[string]*Hotspot)
+	counted := make(map[string]bool) // Hotspots the current commit already counts for
+	for _, commit := range commits {
+		inWindow := results.Since.IsZero() || !commit.Timestamp.Before(results.Since)
+		clear(counted)
+		for _, fc := range commit.FilesChanged {
+			path := renames.current(fc)
+			if !inWindow {
+				continue
+			}
+
+			lines, exists := lineCounts[path]
+			if !exists || shouldIgnoreFile(path, ignorePatterns) {
+				continue
+			}
+
+			key := rollUp(path, opts.Depth)
+			h, ok := byPath[key]
+			if !ok {
+				h = &Hotspot{Path: key}
+				byPath[key] = h
+			}
+			// A commit changing several files of a directory changes it once
+			if !counted[key] {
+				counted[key] = true
+				h.Changes++
+			}
+			h.Churn += fc.LinesAdded + fc.LinesDeleted
+			if opts.Depth == 0 {
+				h.Lines = lines
+				h.Complexity = complexity[path]
+				h.Defects = defects[path]
+			}
+		}
+	}
+
+	// Directories are as large as all their files, changed or not
+	if opts.Depth > 0 {
+		for path, lines := range lineCounts {
+			if h, ok := byPath[rollUp(path, opts.Depth)]; ok && !shouldIgnoreFile(path, ignorePatterns) {
+				h.Lines += lines
+				h.Complexity += complexity[path]
+				h.Defects += defects[path]
+			}
+		}
+	}
+
+	size := func(h *Hotspot) int {
+		if complexity != nil {
+			return h.Complexity
+		}
+		return h.Lines
+	}
+
+	maxChanges, maxChurn, maxSize, maxDensity := 0, 0, 0, 0.0
+	for _, h := range byPath {
+		maxChanges = max(maxChanges, h.Changes)
+		maxChurn = max(maxChurn, h.Churn)
+		maxSize = max(maxSize, size(h))
+		if h.Lines > 0 {
+			h.Density = float64(h.Defects) * 1000 / float64(h.Lines)
+		}
+		maxDensity = max(maxDensity, h.Density)
+	}
+
+	for _, h := range byPath {
+		activity := normalize(h.Changes, maxChanges)
+		if maxChurn > 0 {
+			activity = (activity + normalize(h.Churn, maxChurn)) / 2
+		}
+		h.Score = activity * normalize(size(h), maxSize)
+		if defects != nil && maxDensity > 0 {
+			h.Score *= (1 + h.Density/maxDensity) / 2
+		}
+		results.Hotspots = append(results.Hotspots, *h)
+	}
+
+	sort.Slice(results.Hotspots, func(i, j int) bool {
+		a, b := results.Hotspots[i], results.Hotspots[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Changes != b.Changes {
+			return a.Changes > b.Changes
+		}
+		return a.Path < b.Path
+	})
+
+	return results
+}
+
+// renameTracker maps paths of older commits to the path the file has today.
+// Commits must be fed newest first, so that a rename is seen before the older
+// changes it affects.
+type renameTracker map[string]string
+
+// current returns the path a changed file has today, recording renames
+func (rt renameTracker) current(fc git.FileChange) string {
+	path := fc.Path
+	if newPath, ok := rt[path]; ok {
+		path = newPath
+	}
+	if fc.ChangeType == git.ChangeTypeRenamed && fc.OldPath != "" {
+		rt[fc.OldPath] = path
+	}
+	return path
+}
+
+// rollUp returns the directory of path at most depth levels deep, or path
+// itself when depth is 0. Files above that depth roll up into their own directory.
+func rollUp(path string, depth int) string {
+	if depth <= 0 {
+		return path
+	}
+	parts := strings.Split(path, "/")
+	dirs := parts[:len(parts)-1]
+	if len(dirs) == 0 {
+		return "./"
+	}
+	if len(dirs) > depth {
+		dirs = dirs[:depth]
+	}
+	return strings.Join(dirs, "/") + "/"
+}
+
+// normalize returns value / maximum, or 0 when maximum is 0
+func normalize(value, maximum int) float64 {
+	if maximum == 0 {
+		return 0
+	}
+	return float64(value) / float64(maximum)
+}
+
+// Type implements AnalysisResult
+func (r *HotspotResults) Type() string { return "hotspots" }
+
+// Summary implements AnalysisResult
+func (r *HotspotResults) Summary() string {
+	if len(r.Hotspots) == 0 {
+		return "no hotspots"
+	}
+	top := r.Hotspots[0]
+	return fmt.Sprintf("%d candidates, top %s (score %.2f, %d changes, %d lines)",
+		len(r.Hotspots), top.Path, top.Score, top.Changes, top.Lines)
+}
+
+// hotspotAnalyzer ranks files by change activity and size at the analyzed revision
+type hotspotAnalyzer struct {
+	repo           git.Repository
+	revision       string
+	ignorePatterns []string
+	opts           HotspotOptions
+	szz            config.SZZConfig
+}
+
+func init() {
+	Register("hotspots", func(s Settings) Analyzer {
+		return hotspotAnalyzer{repo: s.Repo, revision: s.Revision, ignorePatterns: s.IgnorePatterns, opts: s.Hotspots, szz: s.Config.SZZ}
+	})
+}
+
+func (hotspotAnalyzer) Name() string              { return "hotspots" }
+func (hotspotAnalyzer) Version() string           { return "4" }
+func (hotspotAnalyzer) Needs() DataNeeds          { return DataNeeds{FileStats: true} }
+func (hotspotAnalyzer) Cacheable() bool           { return true }
+func (hotspotAnalyzer) NewResult() AnalysisResult { return &HotspotResults{} }
+
+func (a hotspotAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	if a.repo == nil {
+		return nil, fmt.Errorf("hotspot analysis needs repository access")
+	}
+	rev := revisionOrHead(a.revision)
+	lineCounts, err := a.repo.GetLineCounts(rev)
+	if err != nil {
+		return nil, err
+	}
+
+	var complexity map[string]int
+	if a.opts.ByComplexity {
+		if complexity, err = CyclomaticAt(a.repo, rev, lineCounts); err != nil {
+			return nil, err
+		}
+	}
+
+	var defects map[string]int
+	if a.opts.ByDefects {
+		szz, err := SZZAt(a.repo, rev, commits, a.ignorePatterns, a.szz)
+		if err != nil {
+			return nil, err
+		}
+		defects = szz.Defects()
+	}
+
+	results := AnalyzeHotspots(commits, lineCounts, complexity, defects, a.ignorePatterns, a.opts)
+	return &results, nil
+}
+
+// CyclomaticAt estimates the cyclomatic complexity of the files in lineCounts at rev
+func CyclomaticAt(repo git.Repository, rev string, lineCounts map[string]int) (map[string]int, error) {
+	paths := make([]string, 0, len(lineCounts))
+	for path := range lineCounts {
+		paths = append(paths, path)
+	}
+	measured, err := ComplexityAt(repo, rev, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	complexity := make(map[string]int, len(measured))
+	for path, c := range measured {
+		complexity[path] = c.Cyclomatic
+	}
+	return complexity, nil
+}
+
+// revisionOrHead returns rev, or "HEAD" if rev is empty
+func revisionOrHead(rev string) string {
+	if rev == "" {
+		return "HEAD"
+	}
+	return rev
+}
@@ -0,0 +1,142 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"histui/internal/git"
+)
+
+func TestRollUp(t *testing.T) {
+	tests := []struct {
+		path  string
+		depth int
+		want  string
+	}{
+		{"a/b/c.go", 0, "a/b/c.go"},
+		{"a/b/c.go", 1, "a/"},
+		{"a/b/c.go", 2, "a/b/"},
+		{"a/b/c.go", 5, "a/b/"},
+		{"main.go", 1, "./"},
+		{"main.go", 0, "main.go"},
+	}
+
+	for _, tt := range tests {
+		if got := rollUp(tt.path, tt.depth); got != tt.want {
+			t.Errorf("rollUp(%q, %d) = %q, want %q", tt.path, tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestRenameTracker(t *testing.T) {
+	renamed := func(from, to string) git.FileChange {
+		return git.FileChange{Path: to, OldPath: from, ChangeType: git.ChangeTypeRenamed}
+	}
+	// Newest first: a.go became b.go, then c.go
+	changes := []struct {
+		fc   git.FileChange
+		want string
+	}{
+		{renamed("b.go", "c.go"), "c.go"},
+		{git.FileChange{Path: "b.go"}, "c.go"},
+		{renamed("a.go", "b.go"), "c.go"},
+		{git.FileChange{Path: "a.go"}, "c.go"},
+		{git.FileChange{Path: "other.go"}, "other.go"},
+		// A rename without its old path records nothing
+		{git.FileChange{Path: "z.go", ChangeType: git.ChangeTypeRenamed}, "z.go"},
+	}
+
+	renames := make(renameTracker)
+	for _, c := range changes {
+		if got := renames.current(c.fc); got != c.want {
+			t.Errorf("current(%s) = %s, want %s", c.fc.Path, got, c.want)
+		}
+	}
+}
+
+func TestAnalyzeHotspots(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	commit := func(day int, changes ...git.FileChange) git.Commit {
+		return git.Commit{Timestamp: start.AddDate(0, 0, day), FilesChanged: changes}
+	}
+	change := func(path string, churn int) git.FileChange {
+		return git.FileChange{Path: path, LinesAdded: churn}
+	}
+
+	// Newest first; api/old.go is api/new.go today, gone.go was deleted
+	commits := []git.Commit{
+		commit(30, change("api/new.go", 10), change("api/util.go", 10)),
+		commit(20, change("api/new.go", 10), change("gen/x.pb.go", 100)),
+		commit(10, git.FileChange{Path: "api/new.go", OldPath: "api/old.go", ChangeType: git.ChangeTypeRenamed}),
+		commit(0, change("api/old.go", 20), change("web/app.js", 40), change("gone.go", 5)),
+	}
+	lineCounts := map[string]int{"api/new.go": 100, "api/util.go": 50, "web/app.js": 200, "gen/x.pb.go": 1000, "api/idle.go": 30}
+	ignore := []string{"*.pb.go"}
+
+	t.Run("files", func(t *testing.T) {
+		results := AnalyzeHotspots(commits, lineCounts, nil, nil, ignore, HotspotOptions{})
+		byPath := make(map[string]Hotspot)
+		for _, h := range results.Hotspots {
+			byPath[h.Path] = h
+		}
+		if len(byPath) != 3 {
+			t.Errorf("hotspots = %+v, want api/new.go, api/util.go and web/app.js", results.Hotspots)
+		}
+		// Changes before the rename count for the current path
+		if h := byPath["api/new.go"]; h.Changes != 4 || h.Churn != 40 || h.Lines != 100 {
+			t.Errorf("api/new.go = %+v, want 4 changes, 40 lines churn, 100 lines", h)
+		}
+		// activity (4/4 + 40/40) / 2 = 1, size 100/200
+		if h := byPath["api/new.go"]; math.Abs(h.Score-0.5) > 1e-9 {
+			t.Errorf("api/new.go score = %v, want 0.5", h.Score)
+		}
+		// activity (1/4 + 40/40) / 2 = 0.625, size 200/200
+		if h := byPath["web/app.js"]; math.Abs(h.Score-0.625) > 1e-9 {
+			t.Errorf("web/app.js score = %v, want 0.625", h.Score)
+		}
+		if results.Hotspots[0].Path != "web/app.js" {
+			t.Errorf("top hotspot %s, want web/app.js", results.Hotspots[0].Path)
+		}
+	})
+
+	t.Run("window", func(t *testing.T) {
+		results := AnalyzeHotspots(commits, lineCounts, nil, nil, ignore, HotspotOptions{Window: 15 * 24 * time.Hour})
+		if !results.Since.Equal(start.AddDate(0, 0, 15)) {
+			t.Errorf("since = %s, want %s", results.Since, start.AddDate(0, 0, 15))
+		}
+		for _, h := range results.Hotspots {
+			if h.Path == "web/app.js" || (h.Path == "api/new.go" && h.Changes != 2) {
+				t.Errorf("hotspot %+v counts changes before the window", h)
+			}
+		}
+	})
+
+	t.Run("directories", func(t *testing.T) {
+		results := AnalyzeHotspots(commits, lineCounts, nil, nil, ignore, HotspotOptions{Depth: 1})
+		byPath := make(map[string]Hotspot)
+		for _, h := range results.Hotspots {
+			byPath[h.Path] = h
+		}
+		// The commit changing api/new.go and api/util.go changes api/ once,
+		// and the unchanged api/idle.go adds to its size
+		if h := byPath["api/"]; h.Changes != 4 || h.Churn != 50 || h.Lines != 180 {
+			t.Errorf("api/ = %+v, want 4 changes, 50 lines churn, 180 lines", h)
+		}
+		if _, ok := byPath["gen/"]; ok {
+			t.Error("a directory of ignored files is a hotspot")
+		}
+	})
+
+	t.Run("defects", func(t *testing.T) {
+		defects := map[string]int{"api/new.go": 2}
+		results := AnalyzeHotspots(commits, lineCounts, nil, defects, ignore, HotspotOptions{})
+		for _, h := range results.Hotspots {
+			// Defect-free files keep half their score
+			want := map[string]float64{"api/new.go": 0.5, "web/app.js": 0.625 / 2}[h.Path]
+			if want > 0 && math.Abs(h.Score-want) > 1e-9 {
+				t.Errorf("%s score = %v, want %v", h.Path, h.Score, want)
+			}
+		}
+	})
+}
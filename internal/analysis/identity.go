@@ -0,0 +1,168 @@
+package analysis
+
+import (
+	"regexp"
+	"strings"
+
+	"histui/internal/git"
+)
+
+// noreplyPattern matches GitHub noreply addresses ("123+login@users.noreply.github.com")
+var noreplyPattern = regexp.MustCompile(`^(?:\d+\+)?([^@]+)@users\.noreply\.github\.com$`)
+
+// Trailers naming people: "Co-authored-by: Name <email>", "Reviewed-by: Name <email>"
+var (
+	coAuthorPattern = personTrailerPattern("co-authored-by")
+	reviewerPattern = personTrailerPattern("reviewed-by")
+)
+
+// personTrailerPattern matches a trailer whose value is "Name <email>"
+func personTrailerPattern(key string) *regexp.Regexp {
+	return regexp.MustCompile(`(?im)^` + regexp.QuoteMeta(key) + `:\s*(.*?)\s*(?:<([^>]*)>)?\s*$`)
+}
+
+// Identities resolves the handles used in CODEOWNERS and team definitions
+// (@login, @org/team, emails, author names) to commit authors
+type Identities struct {
+	aliases map[string][]string
+	teams   map[string][]string
+}
+
+// NewIdentities creates a resolver from owner aliases and team definitions
+func NewIdentities(aliases, teams map[string][]string) *Identities {
+	return &Identities{aliases: aliases, teams: teams}
+}
+
+// Resolve expands an owner into the identities it stands for: the members of
+// a team (recursively), the aliases of a handle, or the owner itself. ok is
+// false for teams without a definition, whose members cannot be known.
+func (ids *Identities) Resolve(owner string) (identities []string, ok bool) {
+	return ids.resolve(owner, make(map[string]bool))
+}
+
+func (ids *Identities) resolve(owner string, seen map[string]bool) ([]string, bool) {
+	if seen[owner] {
+		return nil, true
+	}
+	seen[owner] = true
+
+	if members, isTeam := ids.teams[owner]; isTeam {
+		var identities []string
+		for _, member := range members {
+			resolved, ok := ids.resolve(member, seen)
+			if !ok {
+				return nil, false
+			}
+			identities = append(identities, resolved...)
+		}
+		return identities, true
+	}
+	if isTeamHandle(owner) {
+		return nil, false
+	}
+
+	identities := []string{owner}
+	identities = append(identities, ids.aliases[owner]...)
+	return identities, true
+}
+
+// Matches reports whether an author is one of the identities an owner resolves to
+func (ids *Identities) Matches(owner string, author git.Author) bool {
+	identities, _ := ids.Resolve(owner)
+	for _, identity := range identities {
+		if identityMatches(identity, author) {
+			return true
+		}
+	}
+	return false
+}
+
+// TeamOf returns the first team (in name order) that author belongs to
+func (ids *Identities) TeamOf(author git.Author, teamNames []string) (string, bool) {
+	for _, team := range teamNames {
+		if ids.Matches(team, author) {
+			return team, true
+		}
+	}
+	return "", false
+}
+
+// Handle returns the owner handle of an author: an alias that resolves to
+// them, their GitHub login for noreply addresses, or their email
+func (ids *Identities) Handle(author git.Author) string {
+	var candidates []string
+	for alias, identities := range ids.aliases {
+		for _, identity := range identities {
+			if identityMatches(identity, author) {
+				candidates = append(candidates, alias)
+				break
+			}
+		}
+	}
+	if len(candidates) > 0 {
+		// Deterministic choice when several aliases match
+		best := candidates[0]
+		for _, c := range candidates[1:] {
+			if c < best {
+				best = c
+			}
+		}
+		return best
+	}
+	if m := noreplyPattern.FindStringSubmatch(strings.ToLower(author.Email)); m != nil {
+		return "@" + m[1]
+	}
+	if author.Email != "" {
+		return author.Email
+	}
+	return author.Name
+}
+
+// identityMatches reports whether a single identity (name, email or @login)
+// denotes author. A login matches the local part of the author's email and
+// GitHub noreply addresses.
+func identityMatches(identity string, author git.Author) bool {
+	if strings.EqualFold(identity, author.Name) || strings.EqualFold(identity, author.Email) {
+		return true
+	}
+	login, isHandle := strings.CutPrefix(identity, "@")
+	if !isHandle || login == "" {
+		return false
+	}
+
+	email := strings.ToLower(author.Email)
+	if m := noreplyPattern.FindStringSubmatch(email); m != nil {
+		return strings.EqualFold(m[1], login)
+	}
+	local, _, _ := strings.Cut(email, "@")
+	return strings.EqualFold(local, login)
+}
+
+// CoAuthors returns the authors named in the Co-authored-by trailers of a
+// commit message body
+func CoAuthors(body string) []git.Author {
+	return trailerPeople(coAuthorPattern, body)
+}
+
+// Reviewers returns the people named in the Reviewed-by trailers of a commit
+// message body
+func Reviewers(body string) []git.Author {
+	return trailerPeople(reviewerPattern, body)
+}
+
+// trailerPeople returns the people named in the trailers pattern matches
+func trailerPeople(pattern *regexp.Regexp, body string) []git.Author {
+	var authors []git.Author
+	for _, m := range pattern.FindAllStringSubmatch(body, -1) {
+		if m[1] == "" && m[2] == "" {
+			continue
+		}
+		authors = append(authors, git.Author{Name: m[1], Email: m[2]})
+	}
+	return authors
+}
+
+// isTeamHandle reports whether owner names a team ("@org/team")
+func isTeamHandle(owner string) bool {
+	return strings.HasPrefix(owner, "@") && strings.Contains(owner, "/")
+}
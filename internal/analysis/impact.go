@@ -0,0 +1,70 @@
+package analysis
+
+import "sort"
+
+// ImpactSuggestion represents a file that historically changes together with
+// the files in a change set but is not part of it
+type ImpactSuggestion struct {
+	File        string
+	CoupledWith []string // Changed files that pull this file in
+	Confidence  float64  // Highest coupling score with any changed file
+	CoChanges   int      // Co-changes behind the highest score
+}
+
+// SuggestMissingFiles reports files that are coupled to the changed files but
+// missing from the change set, ranked by confidence (descending)
+func SuggestMissingFiles(results CouplingResults, changed []string, minScore float64) []ImpactSuggestion {
+	changedSet := make(map[string]bool, len(changed))
+	for _, path := range changed {
+		changedSet[path] = true
+	}
+
+	suggestions := make(map[string]*ImpactSuggestion)
+	for _, pair := range results.Pairs {
+		if pair.ScoreValue < minScore {
+			continue
+		}
+
+		// Only pairs with exactly one side in the change set are interesting
+		var present, missing string
+		switch {
+		case changedSet[pair.FileA] && !changedSet[pair.FileB]:
+			present, missing = pair.FileA, pair.FileB
+		case changedSet[pair.FileB] && !changedSet[pair.FileA]:
+			present, missing = pair.FileB, pair.FileA
+		default:
+			continue
+		}
+
+		s, ok := suggestions[missing]
+		if !ok {
+			s = &ImpactSuggestion{File: missing}
+			suggestions[missing] = s
+		}
+		s.CoupledWith = append(s.CoupledWith, present)
+		if pair.ScoreValue > s.Confidence ||
+			(pair.ScoreValue == s.Confidence && pair.CoChanges > s.CoChanges) {
+			s.Confidence = pair.ScoreValue
+			s.CoChanges = pair.CoChanges
+		}
+	}
+
+	result := make([]ImpactSuggestion, 0, len(suggestions))
+	for _, s := range suggestions {
+		sort.Strings(s.CoupledWith)
+		result = append(result, *s)
+	}
+
+	// Sort by confidence, then co-changes (descending), then path for stable output
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Confidence != result[j].Confidence {
+			return result[i].Confidence > result[j].Confidence
+		}
+		if result[i].CoChanges != result[j].CoChanges {
+			return result[i].CoChanges > result[j].CoChanges
+		}
+		return result[i].File < result[j].File
+	})
+
+	return result
+}
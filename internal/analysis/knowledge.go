@@ -0,0 +1,291 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// AuthorShare is one author's part of the knowledge about a file or directory
+type AuthorShare struct {
+	Author string
+	Weight int     // Lines added (history) or lines owned (blame)
+	Share  float64 // Weight relative to all authors
+}
+
+// FileKnowledge describes who knows a file
+type FileKnowledge struct {
+	Path       string
+	Authors    []AuthorShare // Largest share first
+	Owners     []string      // Authors whose share is close to the top share
+	LastActive time.Time     // Most recent commit (anywhere) of any owner
+	Orphaned   bool          // No owner has committed within the orphan period
+}
+
+// DirectoryKnowledge summarizes the knowledge about the files of a directory
+type DirectoryKnowledge struct {
+	Path         string
+	Files        int
+	Contributors int
+	Top          AuthorShare
+	BusFactor    int
+	KeyPeople    []string // The BusFactor authors whose loss orphans most files, most critical first
+}
+
+// KnowledgeResults holds the knowledge distribution of a repository
+type KnowledgeResults struct {
+	Files       []FileKnowledge      // Sorted by path
+	Directories []DirectoryKnowledge // Modules (directories at the configured depth), sorted by path
+	BusFactor   int                  // Authors to lose before most files have no owner left
+	KeyPeople   []string
+	Orphaned    []FileKnowledge      // Orphaned files, most recently abandoned last
+	LastCommit  map[string]time.Time // Most recent commit per author
+	Blame       bool                 // Authorship measured with git blame
+	Reference   time.Time            // Time inactivity is measured against
+	OrphanAfter time.Duration
+}
+
+// KnowledgeOptions configures the knowledge analysis
+type KnowledgeOptions struct {
+	OwnerThreshold float64   // See config.KnowledgeConfig
+	OrphanMonths   int       // See config.KnowledgeConfig
+	Depth          int       // Directory depth of the module breakdown (default 1)
+	Now            time.Time // Reference time for inactivity (zero = time.Now())
+}
+
+// AnalyzeKnowledge computes who knows which files that exist at the analyzed
+// revision (the keys of lineCounts). Authorship comes from lines added per
+// commit, following renames, unless blame is given: then it holds the lines
+// each author owns per file.
+func AnalyzeKnowledge(commits []git.Commit, lineCounts map[string]int, blame map[string]map[string]int, ignorePatterns []string, opts KnowledgeOptions) KnowledgeResults {
+	if opts.Depth <= 0 {
+		opts.Depth = 1
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	results := KnowledgeResults{
+		LastCommit:  make(map[string]time.Time),
+		Blame:       blame != nil,
+		Reference:   opts.Now,
+		OrphanAfter: time.Duration(opts.OrphanMonths) * 30 * 24 * time.Hour,
+	}
+
+	weights := blame
+	if weights == nil {
+		weights = make(map[string]map[string]int)
+	}
+
+	renames := make(renameTracker)
+	for _, commit := range commits {
+		name := commit.Author.Name
+		if commit.Timestamp.After(results.LastCommit[name]) {
+			results.LastCommit[name] = commit.Timestamp
+		}
+		if blame != nil {
+			continue
+		}
+
+		for _, fc := range commit.FilesChanged {
+			path := renames.current(fc)
+			if _, exists := lineCounts[path]; !exists || fc.LinesAdded+fc.LinesDeleted == 0 {
+				continue
+			}
+			if weights[path] == nil {
+				weights[path] = make(map[string]int)
+			}
+			// Every edit counts, even if it only deleted lines; pure renames do not
+			weights[path][name] += max(fc.LinesAdded, 1)
+		}
+	}
+
+	for path, byAuthor := range weights {
+		if len(byAuthor) == 0 || shouldIgnoreFile(path, ignorePatterns) {
+			continue
+		}
+		fk := FileKnowledge{Path: path, Authors: shares(byAuthor)}
+		top := fk.Authors[0].Share
+		for _, a := range fk.Authors {
+			if a.Share >= top*opts.OwnerThreshold {
+				fk.Owners = append(fk.Owners, a.Author)
+				if last := results.LastCommit[a.Author]; last.After(fk.LastActive) {
+					fk.LastActive = last
+				}
+			}
+		}
+		fk.Orphaned = results.OrphanAfter > 0 && opts.Now.Sub(fk.LastActive) > results.OrphanAfter
+		results.Files = append(results.Files, fk)
+		if fk.Orphaned {
+			results.Orphaned = append(results.Orphaned, fk)
+		}
+	}
+	sort.Slice(results.Files, func(i, j int) bool { return results.Files[i].Path < results.Files[j].Path })
+	sort.Slice(results.Orphaned, func(i, j int) bool {
+		a, b := results.Orphaned[i], results.Orphaned[j]
+		if !a.LastActive.Equal(b.LastActive) {
+			return a.LastActive.Before(b.LastActive)
+		}
+		return a.Path < b.Path
+	})
+
+	results.BusFactor, results.KeyPeople = busFactor(results.Files)
+	results.Directories = directoryKnowledge(results.Files, weights, opts.Depth)
+	return results
+}
+
+// directoryKnowledge rolls file knowledge up into directories depth levels deep
+func directoryKnowledge(files []FileKnowledge, weights map[string]map[string]int, depth int) []DirectoryKnowledge {
+	filesOf := make(map[string][]FileKnowledge)
+	for _, fk := range files {
+		dir := rollUp(fk.Path, depth)
+		filesOf[dir] = append(filesOf[dir], fk)
+	}
+
+	dirs := make([]DirectoryKnowledge, 0, len(filesOf))
+	for dir, dirFiles := range filesOf {
+		byAuthor := make(map[string]int)
+		for _, fk := range dirFiles {
+			for author, weight := range weights[fk.Path] {
+				byAuthor[author] += weight
+			}
+		}
+		dk := DirectoryKnowledge{
+			Path:         dir,
+			Files:        len(dirFiles),
+			Contributors: len(byAuthor),
+			Top:          shares(byAuthor)[0],
+		}
+		dk.BusFactor, dk.KeyPeople = busFactor(dirFiles)
+		dirs = append(dirs, dk)
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].Path < dirs[j].Path })
+	return dirs
+}
+
+// shares converts per-author weights into shares, largest first
+func shares(byAuthor map[string]int) []AuthorShare {
+	total := 0
+	for _, w := range byAuthor {
+		total += w
+	}
+	list := make([]AuthorShare, 0, len(byAuthor))
+	for author, w := range byAuthor {
+		list = append(list, AuthorShare{Author: author, Weight: w, Share: normalize(w, total)})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Weight != list[j].Weight {
+			return list[i].Weight > list[j].Weight
+		}
+		return list[i].Author < list[j].Author
+	})
+	return list
+}
+
+// busFactor computes the truck factor of a set of files: the number of owners
+// that must leave before more than half of the files have no owner left.
+// Owners are removed greedily, the one owning most remaining files first.
+func busFactor(files []FileKnowledge) (int, []string) {
+	removed := make(map[string]bool)
+	var keyPeople []string
+
+	for {
+		covered := 0
+		owned := make(map[string]int)
+		for _, fk := range files {
+			alive := false
+			for _, owner := range fk.Owners {
+				if !removed[owner] {
+					alive = true
+					owned[owner]++
+				}
+			}
+			if alive {
+				covered++
+			}
+		}
+		if covered*2 < len(files) || len(owned) == 0 {
+			return len(keyPeople), keyPeople
+		}
+
+		next := ""
+		for owner, n := range owned {
+			if next == "" || n > owned[next] || n == owned[next] && owner < next {
+				next = owner
+			}
+		}
+		removed[next] = true
+		keyPeople = append(keyPeople, next)
+	}
+}
+
+// Type implements AnalysisResult
+func (r *KnowledgeResults) Type() string { return "knowledge" }
+
+// Summary implements AnalysisResult
+func (r *KnowledgeResults) Summary() string {
+	return fmt.Sprintf("bus factor %d across %d files, %d orphaned", r.BusFactor, len(r.Files), len(r.Orphaned))
+}
+
+// knowledgeAnalyzer computes the knowledge distribution at the analyzed revision
+type knowledgeAnalyzer struct {
+	repo           git.Repository
+	revision       string
+	ignorePatterns []string
+	cfg            config.KnowledgeConfig
+	depth          int
+}
+
+func init() {
+	Register("knowledge", func(s Settings) Analyzer {
+		return knowledgeAnalyzer{repo: s.Repo, revision: s.Revision, ignorePatterns: s.IgnorePatterns, cfg: s.Config.Knowledge, depth: s.depthOr(1)}
+	})
+}
+
+func (knowledgeAnalyzer) Name() string              { return "knowledge" }
+func (knowledgeAnalyzer) Version() string           { return "1" }
+func (knowledgeAnalyzer) Needs() DataNeeds          { return DataNeeds{FileStats: true} }
+func (knowledgeAnalyzer) Cacheable() bool           { return false } // Inactivity depends on the current time
+func (knowledgeAnalyzer) NewResult() AnalysisResult { return &KnowledgeResults{} }
+
+func (a knowledgeAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	if a.repo == nil {
+		return nil, fmt.Errorf("knowledge analysis needs repository access")
+	}
+	results, err := KnowledgeAt(a.repo, revisionOrHead(a.revision), commits, a.ignorePatterns, a.cfg, a.depth)
+	if err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
+
+// KnowledgeAt runs the knowledge analysis for the files at rev, blaming every
+// file first if cfg asks for it
+func KnowledgeAt(repo git.Repository, rev string, commits []git.Commit, ignorePatterns []string, cfg config.KnowledgeConfig, depth int) (KnowledgeResults, error) {
+	lineCounts, err := repo.GetLineCounts(rev)
+	if err != nil {
+		return KnowledgeResults{}, err
+	}
+
+	var blame map[string]map[string]int
+	if cfg.Blame {
+		blame = make(map[string]map[string]int, len(lineCounts))
+		for path := range lineCounts {
+			if shouldIgnoreFile(path, ignorePatterns) {
+				continue
+			}
+			if blame[path], err = repo.BlameAuthors(rev, path); err != nil {
+				return KnowledgeResults{}, err
+			}
+		}
+	}
+
+	return AnalyzeKnowledge(commits, lineCounts, blame, ignorePatterns, KnowledgeOptions{
+		OwnerThreshold: cfg.OwnerThreshold,
+		OrphanMonths:   cfg.OrphanMonths,
+		Depth:          depth,
+	}), nil
+}
@@ -0,0 +1,159 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"histui/internal/git"
+)
+
+func TestBusFactor(t *testing.T) {
+	owned := func(owners ...string) FileKnowledge { return FileKnowledge{Owners: owners} }
+
+	tests := []struct {
+		name      string
+		files     []FileKnowledge
+		wantCount int
+		wantKey   []string
+	}{
+		{"no files", nil, 0, nil},
+		{"single owner", []FileKnowledge{owned("a"), owned("a"), owned("a")}, 1, []string{"a"}},
+		{
+			"owner of most files goes first",
+			[]FileKnowledge{owned("a"), owned("a"), owned("a", "b"), owned("b", "c")},
+			2, []string{"a", "b"},
+		},
+		{
+			// Losing one of two owners leaves exactly half the files owned
+			"half the files owned is enough",
+			[]FileKnowledge{owned("b"), owned("a")},
+			2, []string{"a", "b"},
+		},
+		{
+			"shared files survive their first owner",
+			[]FileKnowledge{owned("a", "b"), owned("a", "b"), owned("a", "c")},
+			2, []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, key := busFactor(tt.files)
+			if count != tt.wantCount || !reflect.DeepEqual(key, tt.wantKey) {
+				t.Errorf("busFactor = %d, %v, want %d, %v", count, key, tt.wantCount, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestAnalyzeKnowledge(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	commit := func(author string, when time.Time, changes ...git.FileChange) git.Commit {
+		return git.Commit{Author: git.Author{Name: author}, Timestamp: when, FilesChanged: changes}
+	}
+	added := func(path string, lines int) git.FileChange { return git.FileChange{Path: path, LinesAdded: lines} }
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	// Newest first; api/old.go is api/new.go today
+	commits := []git.Commit{
+		commit("Carol", date(2024, 5, 20), added("web/app.js", 10)),
+		commit("Bob", date(2024, 1, 10), git.FileChange{Path: "api/util.go", LinesDeleted: 5}),
+		commit("Alice", date(2023, 6, 1), git.FileChange{Path: "api/new.go", OldPath: "api/old.go", ChangeType: git.ChangeTypeRenamed}),
+		commit("Alice", date(2023, 5, 1), added("api/old.go", 30), added("vendor/v.go", 100), added("gone.go", 50)),
+		commit("Bob", date(2023, 4, 1), added("api/old.go", 20), added("api/util.go", 10)),
+		commit("Dave", date(2023, 1, 1), added("lib/old.go", 40)),
+		commit("Erin", date(2022, 6, 1), added("lib/older.go", 40)),
+	}
+	lineCounts := map[string]int{"api/new.go": 50, "api/util.go": 5, "web/app.js": 10, "vendor/v.go": 100, "lib/old.go": 40, "lib/older.go": 40}
+	ignore := []string{"vendor/*"}
+	opts := KnowledgeOptions{OwnerThreshold: 0.5, OrphanMonths: 6, Now: now}
+
+	t.Run("history", func(t *testing.T) {
+		results := AnalyzeKnowledge(commits, lineCounts, nil, ignore, opts)
+
+		files := make(map[string]FileKnowledge)
+		var paths []string
+		for _, fk := range results.Files {
+			files[fk.Path] = fk
+			paths = append(paths, fk.Path)
+		}
+		if want := []string{"api/new.go", "api/util.go", "lib/old.go", "lib/older.go", "web/app.js"}; !reflect.DeepEqual(paths, want) {
+			t.Fatalf("files = %v, want %v", paths, want)
+		}
+		// Lines added before the rename count; the rename itself does not
+		wantAuthors := []AuthorShare{{Author: "Alice", Weight: 30, Share: 0.6}, {Author: "Bob", Weight: 20, Share: 0.4}}
+		if fk := files["api/new.go"]; !reflect.DeepEqual(fk.Authors, wantAuthors) || !reflect.DeepEqual(fk.Owners, []string{"Alice", "Bob"}) {
+			t.Errorf("api/new.go = %+v, want Alice and Bob owning 30 and 20 lines", fk)
+		}
+		// An owner active anywhere keeps the file alive
+		if fk := files["api/new.go"]; !fk.LastActive.Equal(date(2024, 1, 10)) || fk.Orphaned {
+			t.Errorf("api/new.go = %+v, want active through Bob", fk)
+		}
+		// A change deleting lines only still counts
+		if fk := files["api/util.go"]; fk.Authors[0].Weight != 11 {
+			t.Errorf("api/util.go authors = %+v, want Bob with 11", fk.Authors)
+		}
+
+		var orphaned []string
+		for _, fk := range results.Orphaned {
+			orphaned = append(orphaned, fk.Path)
+		}
+		if want := []string{"lib/older.go", "lib/old.go"}; !reflect.DeepEqual(orphaned, want) {
+			t.Errorf("orphaned = %v, want %v", orphaned, want)
+		}
+
+		if results.BusFactor != 3 || !reflect.DeepEqual(results.KeyPeople, []string{"Bob", "Alice", "Carol"}) {
+			t.Errorf("bus factor %d with %v, want 3 with Bob, Alice and Carol", results.BusFactor, results.KeyPeople)
+		}
+
+		wantDirs := []DirectoryKnowledge{
+			{Path: "api/", Files: 2, Contributors: 2, Top: AuthorShare{Author: "Bob", Weight: 31, Share: 31.0 / 61}, BusFactor: 2, KeyPeople: []string{"Bob", "Alice"}},
+			{Path: "lib/", Files: 2, Contributors: 2, Top: AuthorShare{Author: "Dave", Weight: 40, Share: 0.5}, BusFactor: 2, KeyPeople: []string{"Dave", "Erin"}},
+			{Path: "web/", Files: 1, Contributors: 1, Top: AuthorShare{Author: "Carol", Weight: 10, Share: 1}, BusFactor: 1, KeyPeople: []string{"Carol"}},
+		}
+		if !reflect.DeepEqual(results.Directories, wantDirs) {
+			t.Errorf("directories = %+v, want %+v", results.Directories, wantDirs)
+		}
+	})
+
+	t.Run("owner threshold", func(t *testing.T) {
+		strict := opts
+		strict.OwnerThreshold = 0.9
+		results := AnalyzeKnowledge(commits, lineCounts, nil, ignore, strict)
+		// Without Bob as an owner, api/new.go depends on Alice, who left
+		for _, fk := range results.Files {
+			if fk.Path == "api/new.go" && (!fk.Orphaned || !reflect.DeepEqual(fk.Owners, []string{"Alice"})) {
+				t.Errorf("api/new.go = %+v, want orphaned with Alice as the only owner", fk)
+			}
+		}
+	})
+
+	t.Run("no orphan period", func(t *testing.T) {
+		never := opts
+		never.OrphanMonths = 0
+		if results := AnalyzeKnowledge(commits, lineCounts, nil, ignore, never); len(results.Orphaned) != 0 {
+			t.Errorf("orphaned = %+v, want none", results.Orphaned)
+		}
+	})
+
+	t.Run("blame", func(t *testing.T) {
+		blame := map[string]map[string]int{
+			"api/new.go": {"Alice": 5, "Bob": 95},
+			"lib/old.go": {"Dave": 40},
+		}
+		results := AnalyzeKnowledge(commits, lineCounts, blame, ignore, opts)
+		if !results.Blame || len(results.Files) != 2 {
+			t.Fatalf("files = %+v, want the 2 blamed files", results.Files)
+		}
+		if fk := results.Files[0]; !reflect.DeepEqual(fk.Owners, []string{"Bob"}) {
+			t.Errorf("api/new.go owners = %v, want Bob", fk.Owners)
+		}
+		// Commits still tell who is active
+		if !results.LastCommit["Dave"].Equal(date(2023, 1, 1)) || !results.Files[1].Orphaned {
+			t.Errorf("lib/old.go = %+v, want orphaned", results.Files[1])
+		}
+	})
+}
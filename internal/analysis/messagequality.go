@@ -0,0 +1,355 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// Message checks, as reported in MessageProblem.Check
+const (
+	CheckEmpty        = "empty"
+	CheckMeaningless  = "meaningless"
+	CheckTooShort     = "too-short"
+	CheckTooLong      = "too-long"
+	CheckMood         = "mood"
+	CheckMissingBody  = "missing-body"
+	CheckConventional = "conventional"
+	CheckIssueRef     = "issue-ref"
+)
+
+// messagePenalties are the points a problem costs out of a score of 100
+var messagePenalties = map[string]int{
+	CheckEmpty:        100,
+	CheckMeaningless:  50,
+	CheckTooShort:     20,
+	CheckTooLong:      10,
+	CheckMood:         15,
+	CheckMissingBody:  20,
+	CheckConventional: 20,
+	CheckIssueRef:     20,
+}
+
+// issueRefPattern matches "#123" and tracker keys like "PROJ-123"
+var issueRefPattern = regexp.MustCompile(`#\d+|\b[A-Z][A-Z0-9]+-\d+\b`)
+
+// conventionalPattern matches Conventional Commits subjects: "type(scope)!: description"
+var conventionalPattern = regexp.MustCompile(`^([a-z]+)(?:\([^)]*\))?!?: \S`)
+
+// conventionalTypes are the commit types of the Conventional Commits spec and
+// the Angular convention it derives from
+var conventionalTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// commonVerbs are verbs that start commit subjects; "Adds" or "Fixes" are
+// recognized as third person forms of them
+var commonVerbs = map[string]bool{
+	"add": true, "adjust": true, "allow": true, "avoid": true, "bump": true,
+	"change": true, "clean": true, "convert": true, "correct": true, "create": true,
+	"delete": true, "disable": true, "document": true, "drop": true, "enable": true,
+	"ensure": true, "extract": true, "fix": true, "handle": true, "implement": true,
+	"improve": true, "introduce": true, "make": true, "merge": true, "move": true,
+	"optimize": true, "prevent": true, "remove": true, "rename": true, "replace": true,
+	"return": true, "revert": true, "rewrite": true, "set": true, "simplify": true,
+	"support": true, "update": true, "upgrade": true, "use": true,
+}
+
+// imperativeExceptions are imperative verbs that look like past tense or gerunds
+var imperativeExceptions = map[string]bool{
+	"bleed": true, "breed": true, "embed": true, "exceed": true, "feed": true,
+	"need": true, "proceed": true, "seed": true, "shed": true, "speed": true,
+	"succeed": true, "bring": true, "ping": true, "ring": true, "sing": true,
+	"spring": true, "string": true, "swing": true,
+}
+
+// MessageProblem is one quality problem of a commit message
+type MessageProblem struct {
+	Check   string
+	Message string
+}
+
+// IsExemptMessage reports whether a subject was generated by git itself
+// (merges, reverts, fixup! and squash! commits) and is not checked
+func IsExemptMessage(subject string) bool {
+	return strings.HasPrefix(subject, "Merge ") ||
+		strings.HasPrefix(subject, "Revert \"") ||
+		strings.HasPrefix(subject, "fixup! ") ||
+		strings.HasPrefix(subject, "squash! ")
+}
+
+// IsConventional reports whether a subject follows Conventional Commits
+func IsConventional(subject string) bool {
+	m := conventionalPattern.FindStringSubmatch(subject)
+	return m != nil && contains(conventionalTypes, m[1])
+}
+
+// HasIssueRef reports whether a message references an issue ("#123", "PROJ-123")
+func HasIssueRef(subject, body string) bool {
+	return issueRefPattern.MatchString(subject + "\n" + body)
+}
+
+// CheckMessage returns the quality problems of a commit message. linesChanged
+// is the size of the commit's diff; the missing body check is skipped when it
+// is 0. Conventional Commits and issue references are only problems when the
+// rules require them.
+func CheckMessage(subject, body string, linesChanged int, rules config.MessageRules) []MessageProblem {
+	if IsExemptMessage(subject) {
+		return nil
+	}
+
+	subject = strings.TrimSpace(subject)
+	body = strings.TrimSpace(body)
+	if subject == "" {
+		return []MessageProblem{{CheckEmpty, "commit message is empty"}}
+	}
+
+	var problems []MessageProblem
+	add := func(check, format string, args ...interface{}) {
+		problems = append(problems, MessageProblem{check, fmt.Sprintf(format, args...)})
+	}
+
+	if rules.MinSubjectLength > 0 && len(subject) < rules.MinSubjectLength {
+		add(CheckTooShort, "subject is shorter than %d characters", rules.MinSubjectLength)
+	}
+	if rules.MaxSubjectLength > 0 && len(subject) > rules.MaxSubjectLength {
+		add(CheckTooLong, "subject is longer than %d characters", rules.MaxSubjectLength)
+	}
+	normalized := strings.ToLower(subject)
+	for _, forbidden := range rules.ForbiddenSubjects {
+		if normalized == strings.ToLower(forbidden) {
+			add(CheckMeaningless, "subject %q is not meaningful", subject)
+			break
+		}
+	}
+	if word := firstWord(subject); word != "" && !isImperative(word) {
+		add(CheckMood, "subject should use the imperative mood (%q)", word)
+	}
+	if rules.LargeDiffLines > 0 && linesChanged >= rules.LargeDiffLines && body == "" {
+		add(CheckMissingBody, "%d changed lines but no message body explaining them", linesChanged)
+	}
+	if rules.RequireConventional && !IsConventional(subject) {
+		add(CheckConventional, "subject does not follow Conventional Commits (\"type(scope): description\")")
+	}
+	if rules.RequireIssueRef && !HasIssueRef(subject, body) {
+		add(CheckIssueRef, "message does not reference an issue")
+	}
+	return problems
+}
+
+// ScoreMessage scores a commit message from 0 to 100, deducting a penalty
+// for every problem
+func ScoreMessage(problems []MessageProblem) int {
+	score := 100
+	for _, p := range problems {
+		score -= messagePenalties[p.Check]
+	}
+	return max(score, 0)
+}
+
+// firstWord returns the lowercased first word of a subject after any
+// Conventional Commits prefix, or "" if it does not start with a word
+func firstWord(subject string) string {
+	if i := strings.Index(subject, ": "); i >= 0 && conventionalPattern.MatchString(subject) {
+		subject = subject[i+2:]
+	}
+	fields := strings.Fields(subject)
+	if len(fields) == 0 {
+		return ""
+	}
+	word := strings.ToLower(strings.TrimRight(fields[0], ".,:;!"))
+	for _, r := range word {
+		if r < 'a' || r > 'z' {
+			return ""
+		}
+	}
+	return word
+}
+
+// isImperative guesses whether a subject's first word is an imperative verb:
+// past tense ("Added"), gerunds ("Adding") and third person forms of common
+// verbs ("Adds") are not
+func isImperative(word string) bool {
+	if imperativeExceptions[word] || commonVerbs[word] {
+		return true
+	}
+	if len(word) > 4 && strings.HasSuffix(word, "ed") {
+		return false
+	}
+	if len(word) > 5 && strings.HasSuffix(word, "ing") {
+		return false
+	}
+	if strings.HasSuffix(word, "s") {
+		stem := strings.TrimSuffix(word, "s")
+		if commonVerbs[stem] || commonVerbs[strings.TrimSuffix(stem, "e")] ||
+			commonVerbs[strings.TrimSuffix(stem, "ie")+"y"] {
+			return false
+		}
+	}
+	return true
+}
+
+// MessageScore is the quality score of one commit message
+type MessageScore struct {
+	SHA       string
+	ShortSHA  string
+	Author    string
+	Timestamp time.Time
+	Subject   string
+	Score     int
+	Problems  []MessageProblem
+}
+
+// MessageQualityGroup aggregates the scores of an author or a month
+type MessageQualityGroup struct {
+	Name         string
+	Commits      int
+	Average      float64 // Mean score
+	Conventional float64 // Share of Conventional Commits subjects
+	IssueRefs    float64 // Share of messages referencing an issue
+}
+
+// MessageQualityResults holds commit message quality scores
+type MessageQualityResults struct {
+	Overall  MessageQualityGroup
+	Exempt   int            // Merge, revert, fixup! and squash! commits that were not scored
+	Problems map[string]int // Commits per failed check
+	Authors  []MessageQualityGroup
+	Trend    []MessageQualityGroup // Monthly, oldest first
+	Worst    []MessageScore        // Lowest scores first
+}
+
+// maxWorstMessages is how many of the worst messages are kept
+const maxWorstMessages = 50
+
+// messageTotals accumulates the scores of a group
+type messageTotals struct {
+	commits, score, conventional, issueRefs int
+}
+
+func (t *messageTotals) group(name string) MessageQualityGroup {
+	return MessageQualityGroup{
+		Name:         name,
+		Commits:      t.commits,
+		Average:      normalize(t.score, t.commits),
+		Conventional: normalize(t.conventional, t.commits),
+		IssueRefs:    normalize(t.issueRefs, t.commits),
+	}
+}
+
+// AnalyzeMessageQuality scores the messages of all non-merge commits
+func AnalyzeMessageQuality(commits []git.Commit, rules config.MessageRules) MessageQualityResults {
+	results := MessageQualityResults{Problems: make(map[string]int)}
+
+	var overall messageTotals
+	authors := make(map[string]*messageTotals)
+	months := make(map[time.Time]*messageTotals)
+	var scores []MessageScore
+
+	for _, commit := range commits {
+		if commit.IsMerge || IsExemptMessage(commit.Subject) {
+			results.Exempt++
+			continue
+		}
+
+		problems := CheckMessage(commit.Subject, commit.Body, commit.Stats.Insertions+commit.Stats.Deletions, rules)
+		score := ScoreMessage(problems)
+		for _, p := range problems {
+			results.Problems[p.Check]++
+		}
+
+		local := commit.Timestamp
+		month := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if months[month] == nil {
+			months[month] = &messageTotals{}
+		}
+		if authors[commit.Author.Name] == nil {
+			authors[commit.Author.Name] = &messageTotals{}
+		}
+
+		conventional := IsConventional(commit.Subject)
+		issueRef := HasIssueRef(commit.Subject, commit.Body)
+		for _, t := range []*messageTotals{&overall, authors[commit.Author.Name], months[month]} {
+			t.commits++
+			t.score += score
+			if conventional {
+				t.conventional++
+			}
+			if issueRef {
+				t.issueRefs++
+			}
+		}
+
+		if len(problems) > 0 {
+			scores = append(scores, MessageScore{
+				SHA:       commit.SHA,
+				ShortSHA:  commit.ShortSHA,
+				Author:    commit.Author.Name,
+				Timestamp: commit.Timestamp,
+				Subject:   commit.Subject,
+				Score:     score,
+				Problems:  problems,
+			})
+		}
+	}
+
+	results.Overall = overall.group("all")
+
+	for name, t := range authors {
+		results.Authors = append(results.Authors, t.group(name))
+	}
+	sort.Slice(results.Authors, func(i, j int) bool {
+		a, b := results.Authors[i], results.Authors[j]
+		if a.Commits != b.Commits {
+			return a.Commits > b.Commits
+		}
+		return a.Name < b.Name
+	})
+
+	monthKeys := make([]time.Time, 0, len(months))
+	for month := range months {
+		monthKeys = append(monthKeys, month)
+	}
+	sort.Slice(monthKeys, func(i, j int) bool { return monthKeys[i].Before(monthKeys[j]) })
+	for _, month := range monthKeys {
+		results.Trend = append(results.Trend, months[month].group(month.Format("2006-01")))
+	}
+
+	// Commits are newest first, so a stable sort lists recent offenders first
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].Score < scores[j].Score })
+	results.Worst = scores[:min(maxWorstMessages, len(scores))]
+
+	return results
+}
+
+// Type implements AnalysisResult
+func (r *MessageQualityResults) Type() string { return "messagequality" }
+
+// Summary implements AnalysisResult
+func (r *MessageQualityResults) Summary() string {
+	return fmt.Sprintf("average score %.0f over %d messages, %.0f%% conventional",
+		r.Overall.Average, r.Overall.Commits, r.Overall.Conventional*100)
+}
+
+// messageQualityAnalyzer scores commit messages
+type messageQualityAnalyzer struct {
+	rules config.MessageRules
+}
+
+func init() {
+	Register("messagequality", func(s Settings) Analyzer { return messageQualityAnalyzer{rules: s.Config.Messages} })
+}
+
+func (messageQualityAnalyzer) Name() string              { return "messagequality" }
+func (messageQualityAnalyzer) Version() string           { return "1" }
+func (messageQualityAnalyzer) Needs() DataNeeds          { return DataNeeds{FileStats: true} }
+func (messageQualityAnalyzer) Cacheable() bool           { return true }
+func (messageQualityAnalyzer) NewResult() AnalysisResult { return &MessageQualityResults{} }
+
+func (a messageQualityAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	results := AnalyzeMessageQuality(commits, a.rules)
+	return &results, nil
+}
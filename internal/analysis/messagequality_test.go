@@ -0,0 +1,143 @@
+package analysis
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+func TestCheckMessage(t *testing.T) {
+	defaults := config.Default().Messages
+	conventional := defaults
+	conventional.RequireConventional = true
+	issueRef := defaults
+	issueRef.RequireIssueRef = true
+
+	tests := []struct {
+		name    string
+		subject string
+		body    string
+		lines   int
+		rules   config.MessageRules
+		want    []string
+	}{
+		{"good subject", "Add retries to the HTTP client", "", 10, defaults, nil},
+		{"empty", "  ", "", 10, defaults, []string{CheckEmpty}},
+		{"merge is exempt", "Merge branch 'main' into feature", "", 10, defaults, nil},
+		{"revert is exempt", "Revert \"Add retries\"", "", 10, defaults, nil},
+		{"fixup is exempt", "fixup! Add retries", "", 10, defaults, nil},
+		{"forbidden subject", "WIP", "", 10, defaults, []string{CheckTooShort, CheckMeaningless}},
+		{"too long", "Add " + strings.Repeat("x", 70), "", 10, defaults, []string{CheckTooLong}},
+
+		{"past tense", "Added retries to the HTTP client", "", 10, defaults, []string{CheckMood}},
+		{"gerund", "Adding retries to the HTTP client", "", 10, defaults, []string{CheckMood}},
+		{"third person", "Updates the retry documentation", "", 10, defaults, []string{CheckMood}},
+		{"third person of a verb ending in e", "Fixes the login race condition", "", 10, defaults, []string{CheckMood}},
+		{"imperative that looks past tense", "Embed the fonts in the binary", "", 10, defaults, nil},
+		{"imperative that looks like a gerund", "Bring back the old parser", "", 10, defaults, nil},
+		{"imperative ending in s", "Process the queue in order", "", 10, defaults, nil},
+		{"no word first", "123 retries are too many", "", 10, defaults, nil},
+		{"mood after a type prefix", "feat(api): Added pagination", "", 10, defaults, []string{CheckMood}},
+
+		{"large diff without body", "Add retries to the HTTP client", "", 200, defaults, []string{CheckMissingBody}},
+		{"large diff with body", "Add retries to the HTTP client", "The server drops requests.", 200, defaults, nil},
+		{"unknown size", "Add retries to the HTTP client", "", 0, config.MessageRules{}, nil},
+
+		{"conventional", "feat(api): add pagination", "", 10, conventional, nil},
+		{"breaking conventional", "feat!: drop the v1 API", "", 10, conventional, nil},
+		{"not conventional", "Add pagination to the API", "", 10, conventional, []string{CheckConventional}},
+		{"unknown type", "wip: add pagination to the API", "", 10, conventional, []string{CheckConventional}},
+
+		{"issue in body", "Add pagination to the API", "Refs #12", 10, issueRef, nil},
+		{"tracker key in subject", "Add pagination to the API (PROJ-7)", "", 10, issueRef, nil},
+		{"no issue", "Add pagination to the API", "", 10, issueRef, []string{CheckIssueRef}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, p := range CheckMessage(tt.subject, tt.body, tt.lines, tt.rules) {
+				got = append(got, p.Check)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckMessage(%q) = %v, want %v", tt.subject, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreMessage(t *testing.T) {
+	problems := func(checks ...string) []MessageProblem {
+		var list []MessageProblem
+		for _, check := range checks {
+			list = append(list, MessageProblem{Check: check})
+		}
+		return list
+	}
+
+	tests := []struct {
+		problems []MessageProblem
+		want     int
+	}{
+		{nil, 100},
+		{problems(CheckMood), 85},
+		{problems(CheckTooShort, CheckMeaningless), 30},
+		{problems(CheckEmpty), 0},
+		// Scores do not go below 0
+		{problems(CheckMeaningless, CheckTooShort, CheckMood, CheckMissingBody, CheckIssueRef), 0},
+	}
+
+	for _, tt := range tests {
+		if got := ScoreMessage(tt.problems); got != tt.want {
+			t.Errorf("ScoreMessage(%+v) = %d, want %d", tt.problems, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeMessageQuality(t *testing.T) {
+	commit := func(author, subject string, month time.Month) git.Commit {
+		return git.Commit{SHA: subject, Author: git.Author{Name: author}, Subject: subject, Timestamp: time.Date(2024, month, 10, 12, 0, 0, 0, time.UTC)}
+	}
+
+	// Newest first
+	commits := []git.Commit{
+		{Subject: "Add a merge without the usual subject", IsMerge: true},
+		commit("Bob", "Revert \"feat: add pagination (#4)\"", 3),
+		commit("Bob", "wip", 3),
+		commit("Alice", "feat: add pagination (#4)", 3),
+		commit("Alice", "Added some things to the parser", 2),
+	}
+
+	results := AnalyzeMessageQuality(commits, config.Default().Messages)
+
+	if results.Exempt != 2 {
+		t.Errorf("exempt = %d, want the merge and the revert", results.Exempt)
+	}
+	overall := results.Overall
+	if overall.Commits != 3 || math.Abs(overall.Average-215.0/3) > 1e-9 || math.Abs(overall.Conventional-1.0/3) > 1e-9 {
+		t.Errorf("overall = %+v, want 3 commits averaging 215/3, a third conventional", overall)
+	}
+	if want := map[string]int{CheckTooShort: 1, CheckMeaningless: 1, CheckMood: 1}; !reflect.DeepEqual(results.Problems, want) {
+		t.Errorf("problems = %v, want %v", results.Problems, want)
+	}
+
+	var worst []string
+	for _, s := range results.Worst {
+		worst = append(worst, s.Subject)
+	}
+	if want := []string{"wip", "Added some things to the parser"}; !reflect.DeepEqual(worst, want) {
+		t.Errorf("worst = %v, want %v", worst, want)
+	}
+
+	if len(results.Authors) != 2 || results.Authors[0].Name != "Alice" || results.Authors[0].Average != 92.5 {
+		t.Errorf("authors = %+v, want Alice first averaging 92.5", results.Authors)
+	}
+	if len(results.Trend) != 2 || results.Trend[0].Name != "2024-02" || results.Trend[1].Average != 65 {
+		t.Errorf("trend = %+v, want February, then March averaging 65", results.Trend)
+	}
+}
@@ -0,0 +1,429 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// Collaborator is an author in the collaboration network
+type Collaborator struct {
+	Name       string
+	Commits    int     // Commits authored or co-authored
+	Files      int     // Distinct files changed
+	Degree     int     // Authors connected to
+	Centrality float64 // Share of the shortest paths between other authors that pass through this one
+	Community  int     // Index into NetworkResults.Communities, -1 if isolated
+}
+
+// Collaboration connects two authors who work on the same files
+type Collaboration struct {
+	A, B        string
+	SharedFiles int // Files both changed within the time window of each other
+	CoAuthored  int // Commits one authored with the other as co-author
+}
+
+// Weight returns the strength of a collaboration
+func (c Collaboration) Weight() int {
+	return c.SharedFiles + c.CoAuthored
+}
+
+// Community is a group of authors who mostly work with each other
+type Community struct {
+	Members []string // Most commits first
+	Modules []string // Top-level modules the members change most, most changes first
+	Commits int
+}
+
+// NetworkResults holds the developer collaboration network
+type NetworkResults struct {
+	Authors     []Collaborator  // Most commits first
+	Edges       []Collaboration // Strongest first
+	Communities []Community     // Largest first
+	Connectors  []string        // Authors linking others, most central first
+	Isolated    []string        // Authors connected to nobody
+	WindowDays  int
+}
+
+// networkChange is one author's change to a file
+type networkChange struct {
+	author string
+	when   time.Time
+}
+
+// AnalyzeNetwork connects authors who changed the same file within
+// cfg.WindowDays of each other, and the authors and co-authors (from
+// Co-authored-by trailers) of the same commit. Authors sharing at least
+// cfg.MinFiles files or co-authored commits are connected; the network is then
+// split into communities, and authors are ranked by betweenness centrality.
+func AnalyzeNetwork(commits []git.Commit, ignorePatterns []string, cfg config.NetworkConfig) NetworkResults {
+	results := NetworkResults{WindowDays: cfg.WindowDays}
+	window := time.Duration(cfg.WindowDays) * 24 * time.Hour
+
+	authors := make(map[string]*Collaborator)
+	authorFiles := make(map[string]map[string]bool)
+	authorModules := make(map[string]map[string]int)
+	changes := make(map[string][]networkChange) // Path -> changes, newest first
+	edges := make(map[[2]string]*Collaboration)
+	edge := func(a, b string) *Collaboration {
+		if a > b {
+			a, b = b, a
+		}
+		key := [2]string{a, b}
+		if edges[key] == nil {
+			edges[key] = &Collaboration{A: a, B: b}
+		}
+		return edges[key]
+	}
+
+	renames := make(renameTracker)
+	for _, commit := range commits {
+		if commit.IsMerge {
+			continue
+		}
+		names := []string{commit.Author.Name}
+		for _, co := range CoAuthors(commit.Body) {
+			name := co.Name
+			if name == "" {
+				name = co.Email
+			}
+			if !contains(names, name) {
+				names = append(names, name)
+				edge(commit.Author.Name, name).CoAuthored++
+			}
+		}
+		for _, name := range names {
+			if authors[name] == nil {
+				authors[name] = &Collaborator{Name: name, Community: -1}
+				authorFiles[name] = make(map[string]bool)
+				authorModules[name] = make(map[string]int)
+			}
+			authors[name].Commits++
+		}
+
+		bulk := cfg.MaxFiles > 0 && len(commit.FilesChanged) > cfg.MaxFiles
+		for _, fc := range commit.FilesChanged {
+			path := renames.current(fc)
+			if bulk || shouldIgnoreFile(path, ignorePatterns) {
+				continue
+			}
+			for _, name := range names {
+				authorFiles[name][path] = true
+				authorModules[name][rollUp(path, 1)]++
+				changes[path] = append(changes[path], networkChange{author: name, when: commit.Timestamp})
+			}
+		}
+	}
+
+	// Authors who changed a file within the window of each other share it
+	for _, fileChanges := range changes {
+		lastSeen := make(map[string]time.Time)
+		shared := make(map[[2]string]bool)
+		for i := len(fileChanges) - 1; i >= 0; i-- {
+			change := fileChanges[i]
+			for other, when := range lastSeen {
+				if gap := change.when.Sub(when); other != change.author && gap <= window && gap >= -window {
+					a, b := change.author, other
+					if a > b {
+						a, b = b, a
+					}
+					shared[[2]string{a, b}] = true
+				}
+			}
+			lastSeen[change.author] = change.when
+		}
+		for pair := range shared {
+			edge(pair[0], pair[1]).SharedFiles++
+		}
+	}
+
+	neighbors := make(map[string]map[string]int)
+	for _, e := range edges {
+		if e.Weight() < cfg.MinFiles {
+			continue
+		}
+		results.Edges = append(results.Edges, *e)
+		for _, pair := range [][2]string{{e.A, e.B}, {e.B, e.A}} {
+			if neighbors[pair[0]] == nil {
+				neighbors[pair[0]] = make(map[string]int)
+			}
+			neighbors[pair[0]][pair[1]] = e.Weight()
+		}
+	}
+	sort.Slice(results.Edges, func(i, j int) bool {
+		a, b := results.Edges[i], results.Edges[j]
+		if a.Weight() != b.Weight() {
+			return a.Weight() > b.Weight()
+		}
+		if a.A != b.A {
+			return a.A < b.A
+		}
+		return a.B < b.B
+	})
+
+	names := make([]string, 0, len(authors))
+	for name := range authors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	centrality := betweenness(names, neighbors)
+	labels := propagateLabels(names, neighbors)
+
+	// Communities, numbered by size once all are known
+	groups := make(map[int][]string)
+	for _, name := range names {
+		a := authors[name]
+		a.Files = len(authorFiles[name])
+		a.Degree = len(neighbors[name])
+		a.Centrality = centrality[name]
+		if a.Degree == 0 {
+			results.Isolated = append(results.Isolated, name)
+			continue
+		}
+		groups[labels[name]] = append(groups[labels[name]], name)
+		if a.Centrality > 0 {
+			results.Connectors = append(results.Connectors, name)
+		}
+	}
+	byCommits := func(list []string) {
+		sort.SliceStable(list, func(i, j int) bool { return authors[list[i]].Commits > authors[list[j]].Commits })
+	}
+	byCommits(results.Isolated)
+	sort.SliceStable(results.Connectors, func(i, j int) bool {
+		return authors[results.Connectors[i]].Centrality > authors[results.Connectors[j]].Centrality
+	})
+
+	for _, members := range groups {
+		byCommits(members)
+		community := Community{Members: members}
+		modules := make(map[string]int)
+		for _, name := range members {
+			community.Commits += authors[name].Commits
+			for module, n := range authorModules[name] {
+				modules[module] += n
+			}
+		}
+		community.Modules = topKeys(modules, 3)
+		results.Communities = append(results.Communities, community)
+	}
+	sort.Slice(results.Communities, func(i, j int) bool {
+		a, b := results.Communities[i], results.Communities[j]
+		if len(a.Members) != len(b.Members) {
+			return len(a.Members) > len(b.Members)
+		}
+		if a.Commits != b.Commits {
+			return a.Commits > b.Commits
+		}
+		return a.Members[0] < b.Members[0]
+	})
+	for i, community := range results.Communities {
+		for _, name := range community.Members {
+			authors[name].Community = i
+		}
+	}
+
+	for _, name := range names {
+		results.Authors = append(results.Authors, *authors[name])
+	}
+	sort.SliceStable(results.Authors, func(i, j int) bool { return results.Authors[i].Commits > results.Authors[j].Commits })
+
+	return results
+}
+
+// propagateLabels finds communities by weighted label propagation: every
+// author repeatedly takes the label carrying the most weight among their
+// neighbors (the lowest on ties) until no label changes
+func propagateLabels(names []string, neighbors map[string]map[string]int) map[string]int {
+	labels := make(map[string]int, len(names))
+	for i, name := range names {
+		labels[name] = i
+	}
+	for round := 0; round < 100; round++ {
+		changed := false
+		for _, name := range names {
+			if len(neighbors[name]) == 0 {
+				continue
+			}
+			weights := make(map[int]int)
+			for other, w := range neighbors[name] {
+				weights[labels[other]] += w
+			}
+			best, bestWeight := labels[name], weights[labels[name]]
+			for label, w := range weights {
+				if w > bestWeight || (w == bestWeight && label < best) {
+					best, bestWeight = label, w
+				}
+			}
+			if best != labels[name] {
+				labels[name] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return labels
+}
+
+// betweenness computes the normalized betweenness centrality of every author
+// in the unweighted network (Brandes' algorithm)
+func betweenness(names []string, neighbors map[string]map[string]int) map[string]float64 {
+	centrality := make(map[string]float64, len(names))
+	adjacent := make(map[string][]string, len(neighbors))
+	for name, others := range neighbors {
+		for other := range others {
+			adjacent[name] = append(adjacent[name], other)
+		}
+		sort.Strings(adjacent[name])
+	}
+
+	for _, source := range names {
+		if len(adjacent[source]) == 0 {
+			continue
+		}
+		var stack []string
+		predecessors := make(map[string][]string)
+		paths := map[string]float64{source: 1}
+		distance := map[string]int{source: 0}
+		queue := []string{source}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			stack = append(stack, v)
+			for _, w := range adjacent[v] {
+				if _, seen := distance[w]; !seen {
+					distance[w] = distance[v] + 1
+					queue = append(queue, w)
+				}
+				if distance[w] == distance[v]+1 {
+					paths[w] += paths[v]
+					predecessors[w] = append(predecessors[w], v)
+				}
+			}
+		}
+
+		dependency := make(map[string]float64)
+		for i := len(stack) - 1; i >= 0; i-- {
+			w := stack[i]
+			for _, v := range predecessors[w] {
+				dependency[v] += paths[v] / paths[w] * (1 + dependency[w])
+			}
+			if w != source {
+				centrality[w] += dependency[w]
+			}
+		}
+	}
+
+	// Every pair was counted from both ends
+	if n := float64(len(names)); n > 2 {
+		for name := range centrality {
+			centrality[name] /= (n - 1) * (n - 2)
+		}
+	}
+	return centrality
+}
+
+// topKeys returns the n keys with the largest counts, largest first
+func topKeys(counts map[string]int, n int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys[:min(n, len(keys))]
+}
+
+// WriteDOT writes the network as a Graphviz graph, one color per community
+func (r *NetworkResults) WriteDOT(w io.Writer) error {
+	fmt.Fprintln(w, "graph collaboration {")
+	fmt.Fprintln(w, "  node [shape=ellipse, style=filled, colorscheme=set312, fillcolor=white];")
+	for _, a := range r.Authors {
+		attrs := fmt.Sprintf("label=%q", fmt.Sprintf("%s\n%d commits", a.Name, a.Commits))
+		if a.Community >= 0 {
+			attrs += fmt.Sprintf(", fillcolor=%d", a.Community%12+1)
+		}
+		fmt.Fprintf(w, "  %q [%s];\n", a.Name, attrs)
+	}
+	for _, e := range r.Edges {
+		fmt.Fprintf(w, "  %q -- %q [weight=%d, label=%d];\n", e.A, e.B, e.Weight(), e.Weight())
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// WriteJSON writes the network as nodes and links, as graph visualization
+// libraries such as D3 take it
+func (r *NetworkResults) WriteJSON(w io.Writer) error {
+	type node struct {
+		ID         string  `json:"id"`
+		Commits    int     `json:"commits"`
+		Files      int     `json:"files"`
+		Centrality float64 `json:"centrality"`
+		Community  int     `json:"community"`
+	}
+	type link struct {
+		Source      string `json:"source"`
+		Target      string `json:"target"`
+		Weight      int    `json:"weight"`
+		SharedFiles int    `json:"shared_files"`
+		CoAuthored  int    `json:"co_authored"`
+	}
+	graph := struct {
+		Nodes []node `json:"nodes"`
+		Links []link `json:"links"`
+	}{Nodes: []node{}, Links: []link{}}
+	for _, a := range r.Authors {
+		graph.Nodes = append(graph.Nodes, node{a.Name, a.Commits, a.Files, a.Centrality, a.Community})
+	}
+	for _, e := range r.Edges {
+		graph.Links = append(graph.Links, link{e.A, e.B, e.Weight(), e.SharedFiles, e.CoAuthored})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(graph)
+}
+
+// Type implements AnalysisResult
+func (r *NetworkResults) Type() string { return "network" }
+
+// Summary implements AnalysisResult
+func (r *NetworkResults) Summary() string {
+	return fmt.Sprintf("%d authors in %d communities, %d connections, %d isolated",
+		len(r.Authors), len(r.Communities), len(r.Edges), len(r.Isolated))
+}
+
+// networkAnalyzer builds the developer collaboration network
+type networkAnalyzer struct {
+	ignorePatterns []string
+	cfg            config.NetworkConfig
+}
+
+func init() {
+	Register("network", func(s Settings) Analyzer {
+		return networkAnalyzer{ignorePatterns: s.IgnorePatterns, cfg: s.Config.Network}
+	})
+}
+
+func (networkAnalyzer) Name() string              { return "network" }
+func (networkAnalyzer) Version() string           { return "1" }
+func (networkAnalyzer) Needs() DataNeeds          { return DataNeeds{FileStats: true} }
+func (networkAnalyzer) Cacheable() bool           { return true }
+func (networkAnalyzer) NewResult() AnalysisResult { return &NetworkResults{} }
+
+func (a networkAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	results := AnalyzeNetwork(commits, a.ignorePatterns, a.cfg)
+	return &results, nil
+}
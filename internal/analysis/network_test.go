@@ -0,0 +1,158 @@
+package analysis
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// undirected builds the neighbor map of a network from weighted edges
+func undirected(edges map[[2]string]int) map[string]map[string]int {
+	neighbors := make(map[string]map[string]int)
+	for e, w := range edges {
+		for _, pair := range [][2]string{{e[0], e[1]}, {e[1], e[0]}} {
+			if neighbors[pair[0]] == nil {
+				neighbors[pair[0]] = make(map[string]int)
+			}
+			neighbors[pair[0]][pair[1]] = w
+		}
+	}
+	return neighbors
+}
+
+func TestBetweenness(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		edges map[[2]string]int
+		want  map[string]float64
+	}{
+		{
+			"path",
+			[]string{"a", "b", "c", "d", "e"},
+			map[[2]string]int{{"a", "b"}: 1, {"b", "c"}: 1, {"c", "d"}: 1, {"d", "e"}: 5},
+			// b lies on the paths from a to c, d and e: 3 of the 6 pairs of others
+			map[string]float64{"b": 0.5, "c": 4.0 / 6, "d": 0.5},
+		},
+		{
+			"star",
+			[]string{"hub", "x", "y", "z"},
+			map[[2]string]int{{"hub", "x"}: 1, {"hub", "y"}: 1, {"hub", "z"}: 1},
+			map[string]float64{"hub": 1},
+		},
+		{
+			"two shortest paths share the credit",
+			[]string{"a", "b", "c", "d"},
+			map[[2]string]int{{"a", "b"}: 1, {"a", "c"}: 1, {"b", "d"}: 1, {"c", "d"}: 1},
+			map[string]float64{"a": 1.0 / 6, "b": 1.0 / 6, "c": 1.0 / 6, "d": 1.0 / 6},
+		},
+		{
+			"isolated authors count in the normalization",
+			[]string{"a", "b", "c", "lone"},
+			map[[2]string]int{{"a", "b"}: 1, {"b", "c"}: 1},
+			map[string]float64{"b": 2.0 / 6},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := betweenness(tt.names, undirected(tt.edges))
+			for _, name := range tt.names {
+				if math.Abs(got[name]-tt.want[name]) > 1e-9 {
+					t.Errorf("centrality of %s = %v, want %v", name, got[name], tt.want[name])
+				}
+			}
+		})
+	}
+}
+
+func TestPropagateLabels(t *testing.T) {
+	// Two triangles joined by a weak edge between c and d
+	names := []string{"a", "b", "c", "d", "e", "f", "lone"}
+	neighbors := undirected(map[[2]string]int{
+		{"a", "b"}: 3, {"a", "c"}: 3, {"b", "c"}: 3,
+		{"d", "e"}: 3, {"d", "f"}: 3, {"e", "f"}: 3,
+		{"c", "d"}: 1,
+	})
+
+	labels := propagateLabels(names, neighbors)
+	if labels["a"] != labels["b"] || labels["a"] != labels["c"] {
+		t.Errorf("first triangle split: %v", labels)
+	}
+	if labels["d"] != labels["e"] || labels["d"] != labels["f"] {
+		t.Errorf("second triangle split: %v", labels)
+	}
+	if labels["a"] == labels["d"] {
+		t.Errorf("triangles merged through the weak edge: %v", labels)
+	}
+	if labels["lone"] == labels["a"] || labels["lone"] == labels["d"] {
+		t.Errorf("isolated author joined a community: %v", labels)
+	}
+}
+
+func TestAnalyzeNetwork(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, n) }
+	commit := func(author string, when time.Time, body string, paths ...string) git.Commit {
+		c := git.Commit{Author: git.Author{Name: author}, Timestamp: when, Body: body}
+		for _, path := range paths {
+			c.FilesChanged = append(c.FilesChanged, git.FileChange{Path: path})
+		}
+		return c
+	}
+	pairedWithDave := "Co-authored-by: Dave <dave@example.com>"
+
+	// Newest first
+	commits := []git.Commit{
+		// Long after everyone else touched the file
+		commit("Carol", day(150), "", "api/x.go"),
+		{Author: git.Author{Name: "Frank"}, Timestamp: day(30), IsMerge: true, FilesChanged: []git.FileChange{{Path: "api/x.go"}}},
+		commit("Alice", day(25), pairedWithDave, "web/b.js"),
+		commit("Alice", day(20), pairedWithDave, "web/a.js"),
+		// A bulk change does not connect its author to anyone
+		commit("Erin", day(13), "", "api/y.go", "a/1", "a/2", "a/3"),
+		commit("Erin", day(12), "", "api/x.go"),
+		commit("Bob", day(10), "", "api/x.go", "api/y.go"),
+		commit("Alice", day(0), "", "api/x.go", "api/y.go"),
+	}
+	cfg := config.NetworkConfig{WindowDays: 30, MinFiles: 2, MaxFiles: 3}
+
+	results := AnalyzeNetwork(commits, nil, cfg)
+
+	want := []Collaboration{
+		{A: "Alice", B: "Dave", SharedFiles: 2, CoAuthored: 2},
+		{A: "Alice", B: "Bob", SharedFiles: 2},
+	}
+	if !reflect.DeepEqual(results.Edges, want) {
+		t.Errorf("edges = %+v, want %+v", results.Edges, want)
+	}
+	if !reflect.DeepEqual(results.Isolated, []string{"Erin", "Carol"}) {
+		t.Errorf("isolated = %v, want Erin, then Carol with fewer commits", results.Isolated)
+	}
+	if len(results.Communities) != 1 || !reflect.DeepEqual(results.Communities[0].Members, []string{"Alice", "Dave", "Bob"}) {
+		t.Errorf("communities = %+v, want Alice, Dave and Bob", results.Communities)
+	}
+	if !reflect.DeepEqual(results.Connectors, []string{"Alice"}) {
+		t.Errorf("connectors = %v, want Alice", results.Connectors)
+	}
+
+	authors := make(map[string]Collaborator)
+	for _, a := range results.Authors {
+		authors[a.Name] = a
+	}
+	if _, ok := authors["Frank"]; ok {
+		t.Error("the author of a merge is in the network")
+	}
+	if a := authors["Alice"]; a.Commits != 3 || a.Degree != 2 || a.Community != 0 || math.Abs(a.Centrality-2.0/12) > 1e-9 {
+		t.Errorf("Alice = %+v, want 3 commits, degree 2, community 0, centrality 1/6", a)
+	}
+	if d := authors["Dave"]; d.Commits != 2 || d.Files != 2 {
+		t.Errorf("Dave = %+v, want 2 co-authored commits on 2 files", d)
+	}
+	if c := authors["Carol"]; c.Community != -1 || c.Degree != 0 {
+		t.Errorf("Carol = %+v, want isolated", c)
+	}
+}
@@ -0,0 +1,308 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// Kinds of firefighting commits
+const (
+	FirefightRevert   = "revert"    // Reverts another commit
+	FirefightFixup    = "fixup"     // fixup! or squash! commit that was never squashed
+	FirefightHotfix   = "hotfix"    // Hotfix branch merge or hotfix commit
+	FirefightFollowUp = "follow-up" // "oops"/"typo"/"forgot" fix shortly after the author's previous commit
+)
+
+// revertPattern matches the line git revert adds to the message
+var revertPattern = regexp.MustCompile(`This reverts commit ([0-9a-f]{7,40})`)
+
+// mergeBranchPatterns extract the merged branch from merge subjects of git,
+// GitHub and GitLab
+var mergeBranchPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^Merge (?:remote-tracking )?branch '([^']+)'`),
+	regexp.MustCompile(`^Merge pull request #\d+ from (\S+)`),
+}
+
+// Firefight is a commit made to put out a fire
+type Firefight struct {
+	Kind      string
+	SHA       string
+	ShortSHA  string
+	Author    string
+	Timestamp time.Time
+	Subject   string
+	Files     []string
+	Branch    string // Hotfix branch, if known
+
+	// The commit being reverted, fixed up or followed up on; RelatedSHA may
+	// be set for commits outside the analyzed history, the rest is not
+	RelatedSHA     string
+	RelatedSubject string
+	RelatedAuthor  string
+	Delay          time.Duration // Time since the related commit
+}
+
+// FirefightFile counts the firefighting commits touching a file
+type FirefightFile struct {
+	Path   string
+	Events int
+}
+
+// FirefightAuthor counts an author's firefighting commits and reverted commits
+type FirefightAuthor struct {
+	Author   string
+	Events   int // Firefighting commits made by the author
+	Reverted int // Commits of the author that were reverted
+}
+
+// OhShitResults holds reverts, hotfixes and quick follow-up fixes
+type OhShitResults struct {
+	Commits int            // Non-merge commits analyzed
+	Kinds   map[string]int // Firefighting commits per kind
+	Events  []Firefight    // Newest first
+	Files   []FirefightFile
+	Authors []FirefightAuthor
+}
+
+// Ratio returns the share of firefighting commits
+func (r *OhShitResults) Ratio() float64 {
+	return normalize(len(r.Events), r.Commits)
+}
+
+// AnalyzeOhShit finds commits made in a hurry to undo or repair earlier ones.
+// Commits must be ordered newest first; merge commits are only checked for
+// hotfix branches.
+func AnalyzeOhShit(commits []git.Commit, ignorePatterns []string, cfg config.FirefightConfig) OhShitResults {
+	results := OhShitResults{Kinds: make(map[string]int)}
+
+	// Current paths of every commit's files, following renames
+	paths := make([][]string, len(commits))
+	renames := make(renameTracker)
+	bySHA := make(map[string]int, len(commits))
+	for i, commit := range commits {
+		bySHA[commit.SHA] = i
+		for _, fc := range commit.FilesChanged {
+			path := renames.current(fc)
+			if !shouldIgnoreFile(path, ignorePatterns) {
+				paths[i] = append(paths[i], path)
+			}
+		}
+	}
+
+	lookup := func(sha string) (int, bool) {
+		if i, ok := bySHA[sha]; ok {
+			return i, true
+		}
+		for full, i := range bySHA {
+			if strings.HasPrefix(full, sha) {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+
+	window := time.Duration(cfg.FollowUpMinutes) * time.Minute
+	lastBy := make(map[string]int)      // Author -> index of their previous commit
+	lastSubject := make(map[string]int) // Subject -> index of the latest commit with it
+	reverted := make(map[string]int)
+
+	// Oldest first, so previous commits are known
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit := commits[i]
+		event := Firefight{
+			SHA:       commit.SHA,
+			ShortSHA:  commit.ShortSHA,
+			Author:    commit.Author.Name,
+			Timestamp: commit.Timestamp,
+			Subject:   commit.Subject,
+			Files:     paths[i],
+		}
+		related := -1
+
+		if commit.IsMerge {
+			if branch, ok := hotfixBranch(commit.Subject, cfg.HotfixBranches); ok {
+				event.Kind, event.Branch = FirefightHotfix, branch
+			}
+		} else {
+			results.Commits++
+			switch {
+			case revertPattern.MatchString(commit.Body) || strings.HasPrefix(commit.Subject, "Revert \""):
+				event.Kind = FirefightRevert
+				if m := revertPattern.FindStringSubmatch(commit.Body); m != nil {
+					event.RelatedSHA = m[1]
+					if j, ok := lookup(m[1]); ok {
+						related = j
+					}
+				} else if j, ok := lastSubject[strings.TrimSuffix(strings.TrimPrefix(commit.Subject, "Revert \""), "\"")]; ok {
+					related = j
+				}
+
+			case strings.HasPrefix(commit.Subject, "fixup! ") || strings.HasPrefix(commit.Subject, "squash! "):
+				event.Kind = FirefightFixup
+				target := commit.Subject
+				for strings.HasPrefix(target, "fixup! ") || strings.HasPrefix(target, "squash! ") {
+					target = target[strings.Index(target, " ")+1:]
+				}
+				if j, ok := lastSubject[target]; ok {
+					related = j
+				}
+
+			case isHotfixSubject(commit.Subject, cfg.HotfixBranches):
+				event.Kind = FirefightHotfix
+
+			case hasKeyword(commit.Subject, cfg.Keywords):
+				j, ok := lastBy[commit.Author.Name]
+				if ok && commit.Timestamp.Sub(commits[j].Timestamp) <= window {
+					event.Kind = FirefightFollowUp
+					related = j
+				}
+			}
+			lastBy[commit.Author.Name] = i
+			lastSubject[commit.Subject] = i
+		}
+
+		if event.Kind == "" {
+			continue
+		}
+		if related >= 0 {
+			r := commits[related]
+			event.RelatedSHA = r.SHA
+			event.RelatedSubject = r.Subject
+			event.RelatedAuthor = r.Author.Name
+			event.Delay = commit.Timestamp.Sub(r.Timestamp)
+			if event.Kind == FirefightRevert {
+				reverted[r.Author.Name]++
+			}
+		}
+		results.Kinds[event.Kind]++
+		results.Events = append(results.Events, event)
+	}
+
+	// Newest first, like commits
+	for i, j := 0, len(results.Events)-1; i < j; i, j = i+1, j-1 {
+		results.Events[i], results.Events[j] = results.Events[j], results.Events[i]
+	}
+
+	files := make(map[string]int)
+	authors := make(map[string]*FirefightAuthor)
+	author := func(name string) *FirefightAuthor {
+		if authors[name] == nil {
+			authors[name] = &FirefightAuthor{Author: name}
+		}
+		return authors[name]
+	}
+	for _, e := range results.Events {
+		author(e.Author).Events++
+		for _, path := range e.Files {
+			files[path]++
+		}
+	}
+	for name, n := range reverted {
+		author(name).Reverted = n
+	}
+
+	for path, n := range files {
+		results.Files = append(results.Files, FirefightFile{Path: path, Events: n})
+	}
+	sort.Slice(results.Files, func(i, j int) bool {
+		if results.Files[i].Events != results.Files[j].Events {
+			return results.Files[i].Events > results.Files[j].Events
+		}
+		return results.Files[i].Path < results.Files[j].Path
+	})
+
+	for _, a := range authors {
+		results.Authors = append(results.Authors, *a)
+	}
+	sort.Slice(results.Authors, func(i, j int) bool {
+		a, b := results.Authors[i], results.Authors[j]
+		if a.Events+a.Reverted != b.Events+b.Reverted {
+			return a.Events+a.Reverted > b.Events+b.Reverted
+		}
+		return a.Author < b.Author
+	})
+
+	return results
+}
+
+// hotfixBranch returns the merged branch of a merge subject if it is a hotfix branch
+func hotfixBranch(subject string, prefixes []string) (string, bool) {
+	for _, pattern := range mergeBranchPatterns {
+		m := pattern.FindStringSubmatch(subject)
+		if m == nil {
+			continue
+		}
+		for _, segment := range strings.Split(strings.ToLower(m[1]), "/") {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(segment, strings.ToLower(prefix)) {
+					return m[1], true
+				}
+			}
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// isHotfixSubject reports whether a subject is tagged as a hotfix, as in
+// "hotfix: ..." or "[Hotfix] ..." (typical of squash-merged hotfix branches)
+func isHotfixSubject(subject string, prefixes []string) bool {
+	fields := strings.Fields(subject)
+	if len(fields) == 0 {
+		return false
+	}
+	tag := strings.ToLower(strings.Trim(fields[0], "[]():!"))
+	return contains(prefixes, tag)
+}
+
+// hasKeyword reports whether a subject contains one of the keywords as a word
+func hasKeyword(subject string, keywords []string) bool {
+	words := strings.FieldsFunc(strings.ToLower(subject), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+	for _, word := range words {
+		if contains(keywords, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// Type implements AnalysisResult
+func (r *OhShitResults) Type() string { return "ohshit" }
+
+// Summary implements AnalysisResult
+func (r *OhShitResults) Summary() string {
+	return fmt.Sprintf("%d firefighting commits (%.1f%%): %d reverts, %d fixups, %d hotfixes, %d follow-ups",
+		len(r.Events), r.Ratio()*100, r.Kinds[FirefightRevert], r.Kinds[FirefightFixup],
+		r.Kinds[FirefightHotfix], r.Kinds[FirefightFollowUp])
+}
+
+// ohShitAnalyzer finds reverts, hotfixes and quick follow-up fixes
+type ohShitAnalyzer struct {
+	ignorePatterns []string
+	cfg            config.FirefightConfig
+}
+
+func init() {
+	Register("ohshit", func(s Settings) Analyzer {
+		return ohShitAnalyzer{ignorePatterns: s.IgnorePatterns, cfg: s.Config.Firefight}
+	})
+}
+
+func (ohShitAnalyzer) Name() string              { return "ohshit" }
+func (ohShitAnalyzer) Version() string           { return "1" }
+func (ohShitAnalyzer) Needs() DataNeeds          { return DataNeeds{FileStats: true, Merges: true} }
+func (ohShitAnalyzer) Cacheable() bool           { return true }
+func (ohShitAnalyzer) NewResult() AnalysisResult { return &OhShitResults{} }
+
+func (a ohShitAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	results := AnalyzeOhShit(commits, a.ignorePatterns, a.cfg)
+	return &results, nil
+}
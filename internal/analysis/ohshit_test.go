@@ -0,0 +1,124 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+func TestHotfixBranch(t *testing.T) {
+	prefixes := config.Default().Firefight.HotfixBranches
+
+	tests := []struct {
+		subject    string
+		wantBranch string
+		wantOK     bool
+	}{
+		{"Merge branch 'hotfix/login' into main", "hotfix/login", true},
+		{"Merge remote-tracking branch 'origin/Emergency-fix'", "origin/Emergency-fix", true},
+		{"Merge pull request #12 from acme/hot-fix-session", "acme/hot-fix-session", true},
+		{"Merge branch 'feature/hotfix-docs'", "feature/hotfix-docs", true},
+		{"Merge branch 'feature/login'", "", false},
+		{"Merge branch 'fix-hotfixes'", "", false},
+		{"hotfix: restore the session cookie", "", false},
+	}
+
+	for _, tt := range tests {
+		branch, ok := hotfixBranch(tt.subject, prefixes)
+		if branch != tt.wantBranch || ok != tt.wantOK {
+			t.Errorf("hotfixBranch(%q) = %q, %v, want %q, %v", tt.subject, branch, ok, tt.wantBranch, tt.wantOK)
+		}
+	}
+}
+
+func TestAnalyzeOhShit(t *testing.T) {
+	start := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	commit := func(sha, author string, minute int, subject, body string, paths ...string) git.Commit {
+		c := git.Commit{SHA: sha, Author: git.Author{Name: author}, Timestamp: start.Add(time.Duration(minute) * time.Minute), Subject: subject, Body: body}
+		for _, path := range paths {
+			c.FilesChanged = append(c.FilesChanged, git.FileChange{Path: path})
+		}
+		return c
+	}
+	merge := func(sha, author string, minute int, subject string) git.Commit {
+		c := commit(sha, author, minute, subject, "")
+		c.IsMerge = true
+		return c
+	}
+
+	// Newest first
+	commits := []git.Commit{
+		merge("m2", "Carol", 300, "Merge branch 'hotfix/login' into main"),
+		merge("m1", "Carol", 290, "Merge branch 'feature/search'"),
+		commit("c9", "Carol", 280, "hotfix: restore the session cookie", "", "web/session.go"),
+		// Reverts a commit that is not in the analyzed history
+		commit("c8", "Dave", 210, "Revert \"Add the old importer\"", "This reverts commit 1234567abcdef."),
+		// Found by an abbreviated SHA
+		commit("c7", "Bob", 200, "Revert \"Add caching\"", "This reverts commit aaaa111.", "api/cache.go"),
+		// Found by subject, without the line git adds
+		commit("c6", "Bob", 150, "Revert \"Add metrics\"", "", "api/metrics.go"),
+		// Too long after Bob's previous commit to be a follow-up
+		commit("c5", "Bob", 140, "Oops, forgot the metrics docs", "", "docs/metrics.md"),
+		commit("c4", "Alice", 100, "fixup! fixup! Add caching", "", "api/cache.go", "vendor/lib.go"),
+		commit("c3", "Alice", 40, "Fix typo in the cache key", "", "api/cache.go"),
+		commit("c2", "Alice", 20, "Tune the cache size", "", "api/cache.go"),
+		commit("c1", "Bob", 10, "Add metrics", "", "api/metrics.go"),
+		commit("aaaa1111bbbb", "Alice", 0, "Add caching", "", "api/cache.go"),
+	}
+
+	results := AnalyzeOhShit(commits, []string{"vendor/*"}, config.Default().Firefight)
+
+	if results.Commits != 10 {
+		t.Errorf("commits = %d, want the 10 non-merge commits", results.Commits)
+	}
+	wantKinds := map[string]int{FirefightHotfix: 2, FirefightRevert: 3, FirefightFixup: 1, FirefightFollowUp: 1}
+	if !reflect.DeepEqual(results.Kinds, wantKinds) {
+		t.Errorf("kinds = %v, want %v", results.Kinds, wantKinds)
+	}
+
+	type event struct {
+		sha, kind, related, relatedAuthor string
+		delay                             int
+	}
+	var got []event
+	for _, e := range results.Events {
+		got = append(got, event{e.SHA, e.Kind, e.RelatedSHA, e.RelatedAuthor, int(e.Delay / time.Minute)})
+	}
+	want := []event{
+		{"m2", FirefightHotfix, "", "", 0},
+		{"c9", FirefightHotfix, "", "", 0},
+		{"c8", FirefightRevert, "1234567abcdef", "", 0},
+		{"c7", FirefightRevert, "aaaa1111bbbb", "Alice", 200},
+		{"c6", FirefightRevert, "c1", "Bob", 140},
+		{"c4", FirefightFixup, "aaaa1111bbbb", "Alice", 100},
+		{"c3", FirefightFollowUp, "c2", "Alice", 20},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %+v, want %+v", got, want)
+	}
+	if branch := results.Events[0].Branch; branch != "hotfix/login" {
+		t.Errorf("hotfix branch = %q, want hotfix/login", branch)
+	}
+
+	// Ignored files do not count
+	wantFiles := []FirefightFile{{Path: "api/cache.go", Events: 3}, {Path: "api/metrics.go", Events: 1}, {Path: "web/session.go", Events: 1}}
+	if !reflect.DeepEqual(results.Files, wantFiles) {
+		t.Errorf("files = %+v, want %+v", results.Files, wantFiles)
+	}
+
+	wantAuthors := []FirefightAuthor{
+		{Author: "Alice", Events: 2, Reverted: 1},
+		{Author: "Bob", Events: 2, Reverted: 1},
+		{Author: "Carol", Events: 2},
+		{Author: "Dave", Events: 1},
+	}
+	if !reflect.DeepEqual(results.Authors, wantAuthors) {
+		t.Errorf("authors = %+v, want %+v", results.Authors, wantAuthors)
+	}
+	if got := results.Ratio(); got != 0.7 {
+		t.Errorf("ratio = %v, want 0.7", got)
+	}
+}
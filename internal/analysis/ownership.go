@@ -0,0 +1,378 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// PathOwnership compares the actual and the declared owners of a directory
+type PathOwnership struct {
+	Path     string
+	Files    int
+	Changes  int           // Recent changes to the directory's files
+	Authors  []AuthorShare // Recent contributors, largest share first
+	Declared []string      // Owners declared in CODEOWNERS for the directory's files
+}
+
+// InactiveOwner is a declared owner who did not recently change the paths they own
+type InactiveOwner struct {
+	Owner   string
+	Pattern string
+	Line    int
+	Files   int // Files governed by the rule
+	Changes int // Recent changes to those files by anyone
+}
+
+// MissingOwner is a heavy contributor to a rule's files who is not among its owners
+type MissingOwner struct {
+	Author  string
+	Handle  string
+	Pattern string
+	Line    int
+	Share   float64 // Share of the recent changes to the rule's files
+}
+
+// OwnershipResults holds the ownership report
+type OwnershipResults struct {
+	Codeowners     string // Path of the CODEOWNERS file, empty if there is none
+	Rules          int
+	Since          time.Time
+	Directories    []PathOwnership
+	Unowned        []string // Files no rule assigns an owner to
+	InactiveOwners []InactiveOwner
+	MissingOwners  []MissingOwner
+	Unresolved     []string // Owners (teams) that cannot be mapped to commit authors
+	Suggested      string   // Suggested CODEOWNERS contents
+}
+
+// OwnershipOptions configures the ownership report
+type OwnershipOptions struct {
+	Months   int     // See config.OwnershipConfig
+	MinShare float64 // See config.OwnershipConfig
+	Depth    int     // Directory depth of the breakdown and suggestion (default 1)
+}
+
+// ruleStats accumulates the recent changes to the files governed by one rule
+type ruleStats struct {
+	rule    CodeownersRule
+	files   int
+	changes int
+	authors map[string]int
+}
+
+// AnalyzeOwnership compares the recent contributors of the files at the
+// analyzed revision (the keys of lineCounts) with the owners declared in
+// codeowners, which may be nil when the repository has no CODEOWNERS file
+func AnalyzeOwnership(commits []git.Commit, lineCounts map[string]int, codeowners *Codeowners, ids *Identities, ignorePatterns []string, opts OwnershipOptions) OwnershipResults {
+	if opts.Depth <= 0 {
+		opts.Depth = 1
+	}
+
+	var results OwnershipResults
+	if codeowners != nil {
+		results.Codeowners = codeowners.Path
+		results.Rules = len(codeowners.Rules)
+	}
+	if len(commits) > 0 && opts.Months > 0 {
+		results.Since = commits[0].Timestamp.AddDate(0, -opts.Months, 0)
+	}
+
+	// Recent changes per file and author
+	authorOf := make(map[string]git.Author)
+	changes := make(map[string]map[string]int)
+	renames := make(renameTracker)
+	for _, commit := range commits {
+		recent := results.Since.IsZero() || !commit.Timestamp.Before(results.Since)
+		for _, fc := range commit.FilesChanged {
+			path := renames.current(fc)
+			if !recent {
+				continue
+			}
+			// Pure renames are not contributions
+			if _, exists := lineCounts[path]; !exists || fc.LinesAdded+fc.LinesDeleted == 0 {
+				continue
+			}
+			if changes[path] == nil {
+				changes[path] = make(map[string]int)
+			}
+			changes[path][commit.Author.Name]++
+			if _, known := authorOf[commit.Author.Name]; !known {
+				authorOf[commit.Author.Name] = commit.Author
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(lineCounts))
+	for path := range lineCounts {
+		if !shouldIgnoreFile(path, ignorePatterns) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	// Attribute every file to its directory and to the rules that govern it
+	dirs := make(map[string]*PathOwnership)
+	dirAuthors := make(map[string]map[string]int)
+	rules := make(map[int]*ruleStats)
+	for _, path := range paths {
+		dir := rollUp(path, opts.Depth)
+		d, ok := dirs[dir]
+		if !ok {
+			d = &PathOwnership{Path: dir}
+			dirs[dir] = d
+			dirAuthors[dir] = make(map[string]int)
+		}
+		d.Files++
+		for author, n := range changes[path] {
+			d.Changes += n
+			dirAuthors[dir][author] += n
+		}
+
+		if codeowners == nil {
+			continue
+		}
+		matched := codeowners.Match(path)
+		owned := false
+		for _, rule := range matched {
+			owned = owned || len(rule.Owners) > 0
+			for _, owner := range rule.Owners {
+				if !contains(d.Declared, owner) {
+					d.Declared = append(d.Declared, owner)
+				}
+			}
+
+			rs, ok := rules[rule.Line]
+			if !ok {
+				rs = &ruleStats{rule: rule, authors: make(map[string]int)}
+				rules[rule.Line] = rs
+			}
+			rs.files++
+			for author, n := range changes[path] {
+				rs.changes += n
+				rs.authors[author] += n
+			}
+		}
+		if !owned {
+			results.Unowned = append(results.Unowned, path)
+		}
+	}
+
+	for dir, d := range dirs {
+		if len(dirAuthors[dir]) > 0 {
+			d.Authors = shares(dirAuthors[dir])
+		}
+		sort.Strings(d.Declared)
+		results.Directories = append(results.Directories, *d)
+	}
+	sort.Slice(results.Directories, func(i, j int) bool { return results.Directories[i].Path < results.Directories[j].Path })
+
+	lines := make([]int, 0, len(rules))
+	for line := range rules {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+	for _, line := range lines {
+		rs := rules[line]
+		checkRule(rs, authorOf, ids, opts.MinShare, &results)
+	}
+
+	results.Suggested = suggestCodeowners(results.Directories, authorOf, ids, opts.MinShare, results.Since)
+	return results
+}
+
+// checkRule reports the inactive owners of a rule and heavy contributors
+// missing from it
+func checkRule(rs *ruleStats, authorOf map[string]git.Author, ids *Identities, minShare float64, results *OwnershipResults) {
+	unresolved := false
+	for _, owner := range rs.rule.Owners {
+		if _, ok := ids.Resolve(owner); !ok {
+			unresolved = true
+			if !contains(results.Unresolved, owner) {
+				results.Unresolved = append(results.Unresolved, owner)
+			}
+			continue
+		}
+
+		active := false
+		for author := range rs.authors {
+			if ids.Matches(owner, authorOf[author]) {
+				active = true
+				break
+			}
+		}
+		if !active {
+			results.InactiveOwners = append(results.InactiveOwners, InactiveOwner{
+				Owner:   owner,
+				Pattern: rs.rule.Pattern,
+				Line:    rs.rule.Line,
+				Files:   rs.files,
+				Changes: rs.changes,
+			})
+		}
+	}
+
+	// Members of unknown teams cannot be told apart from missing owners
+	if unresolved || len(rs.rule.Owners) == 0 || len(rs.authors) == 0 {
+		return
+	}
+	for _, share := range shares(rs.authors) {
+		if share.Share < minShare {
+			break
+		}
+		author := authorOf[share.Author]
+		isOwner := false
+		for _, owner := range rs.rule.Owners {
+			if ids.Matches(owner, author) {
+				isOwner = true
+				break
+			}
+		}
+		if !isOwner {
+			results.MissingOwners = append(results.MissingOwners, MissingOwner{
+				Author:  share.Author,
+				Handle:  ids.Handle(author),
+				Pattern: rs.rule.Pattern,
+				Line:    rs.rule.Line,
+				Share:   share.Share,
+			})
+		}
+	}
+}
+
+// suggestCodeowners builds a CODEOWNERS file assigning every directory to its
+// heavy recent contributors (at most three), with the overall top
+// contributors as default owners
+func suggestCodeowners(dirs []PathOwnership, authorOf map[string]git.Author, ids *Identities, minShare float64, since time.Time) string {
+	var b strings.Builder
+	b.WriteString("# Suggested by histui from ")
+	if since.IsZero() {
+		b.WriteString("all commits\n")
+	} else {
+		fmt.Fprintf(&b, "commits since %s\n", since.Format("2006-01-02"))
+	}
+
+	handles := func(authors []AuthorShare) []string {
+		var list []string
+		for _, a := range authors {
+			if a.Share < minShare || len(list) == 3 {
+				break
+			}
+			list = append(list, ids.Handle(authorOf[a.Author]))
+		}
+		return list
+	}
+
+	overall := make(map[string]int)
+	for _, d := range dirs {
+		for _, a := range d.Authors {
+			overall[a.Author] += a.Weight
+		}
+	}
+	if len(overall) > 0 {
+		if owners := handles(shares(overall)); len(owners) > 0 {
+			fmt.Fprintf(&b, "* %s\n", strings.Join(owners, " "))
+		}
+	}
+
+	for _, d := range dirs {
+		if d.Path == "./" {
+			continue
+		}
+		if owners := handles(d.Authors); len(owners) > 0 {
+			fmt.Fprintf(&b, "/%s %s\n", strings.ReplaceAll(d.Path, " ", `\ `), strings.Join(owners, " "))
+		}
+	}
+	return b.String()
+}
+
+// Type implements AnalysisResult
+func (r *OwnershipResults) Type() string { return "ownership" }
+
+// Summary implements AnalysisResult
+func (r *OwnershipResults) Summary() string {
+	if r.Codeowners == "" {
+		return fmt.Sprintf("no CODEOWNERS file, %d directories", len(r.Directories))
+	}
+	return fmt.Sprintf("%d unowned files, %d inactive owners, %d missing owners",
+		len(r.Unowned), len(r.InactiveOwners), len(r.MissingOwners))
+}
+
+// ownershipAnalyzer checks CODEOWNERS against recent contributions
+type ownershipAnalyzer struct {
+	repo           git.Repository
+	revision       string
+	ignorePatterns []string
+	cfg            config.Config
+	depth          int
+}
+
+func init() {
+	Register("ownership", func(s Settings) Analyzer {
+		return ownershipAnalyzer{repo: s.Repo, revision: s.Revision, ignorePatterns: s.IgnorePatterns, cfg: s.Config, depth: s.depthOr(1)}
+	})
+}
+
+func (ownershipAnalyzer) Name() string              { return "ownership" }
+func (ownershipAnalyzer) Version() string           { return "1" }
+func (ownershipAnalyzer) Needs() DataNeeds          { return DataNeeds{FileStats: true} }
+func (ownershipAnalyzer) Cacheable() bool           { return true }
+func (ownershipAnalyzer) NewResult() AnalysisResult { return &OwnershipResults{} }
+
+func (a ownershipAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	if a.repo == nil {
+		return nil, fmt.Errorf("ownership analysis needs repository access")
+	}
+	results, err := OwnershipAt(a.repo, revisionOrHead(a.revision), commits, a.ignorePatterns, a.cfg, a.depth)
+	if err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
+
+// OwnershipAt runs the ownership report for the files and CODEOWNERS file at rev
+func OwnershipAt(repo git.Repository, rev string, commits []git.Commit, ignorePatterns []string, cfg config.Config, depth int) (OwnershipResults, error) {
+	lineCounts, err := repo.GetLineCounts(rev)
+	if err != nil {
+		return OwnershipResults{}, err
+	}
+
+	codeowners, err := CodeownersAt(repo, rev)
+	if err != nil {
+		return OwnershipResults{}, err
+	}
+
+	ids := NewIdentities(cfg.Ownership.Aliases, cfg.Teams)
+	return AnalyzeOwnership(commits, lineCounts, codeowners, ids, ignorePatterns, OwnershipOptions{
+		Months:   cfg.Ownership.Months,
+		MinShare: cfg.Ownership.MinShare,
+		Depth:    depth,
+	}), nil
+}
+
+// CodeownersAt reads and parses the CODEOWNERS file at rev, nil if there is none
+func CodeownersAt(repo git.Repository, rev string) (*Codeowners, error) {
+	refs := make([]git.FileRef, len(CodeownersLocations))
+	for i, path := range CodeownersLocations {
+		refs[i] = git.FileRef{Rev: rev, Path: path}
+	}
+	contents, err := repo.ReadFiles(refs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, content := range contents {
+		if content != nil {
+			co, err := ParseCodeowners(CodeownersLocations[i], content)
+			if err != nil {
+				return nil, err
+			}
+			return &co, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,226 @@
+package analysis
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// fileInterner assigns dense integer IDs to file paths
+type fileInterner struct {
+	ids   map[string]uint32
+	paths []string
+}
+
+func newFileInterner() *fileInterner {
+	return &fileInterner{ids: make(map[string]uint32)}
+}
+
+// id returns the ID of path, assigning a new one on first use
+func (in *fileInterner) id(path string) uint32 {
+	if id, ok := in.ids[path]; ok {
+		return id
+	}
+	id := uint32(len(in.paths))
+	in.ids[path] = id
+	in.paths = append(in.paths, path)
+	return id
+}
+
+// path returns the path interned under id
+func (in *fileInterner) path(id uint32) string {
+	return in.paths[id]
+}
+
+// pairKey packs two file IDs into one integer key (lower ID first)
+func pairKey(a, b uint32) uint64 {
+	if a > b {
+		a, b = b, a
+	}
+	return uint64(a)<<32 | uint64(b)
+}
+
+// unpackPairKey returns the two file IDs stored in a pair key
+func unpackPairKey(key uint64) (uint32, uint32) {
+	return uint32(key >> 32), uint32(key)
+}
+
+// pairCounter accumulates co-change counts keyed by pair key. When
+// spillThreshold is set, counts are written to sorted run files on disk
+// whenever that many distinct pairs are held in memory.
+type pairCounter struct {
+	counts         map[uint64]uint32
+	spillThreshold int
+	spillDir       string
+	runs           []string
+}
+
+func newPairCounter(spillThreshold int, spillDir string) *pairCounter {
+	return &pairCounter{
+		counts:         make(map[uint64]uint32),
+		spillThreshold: spillThreshold,
+		spillDir:       spillDir,
+	}
+}
+
+// add increments the count of a pair, spilling to disk if the threshold is reached
+func (pc *pairCounter) add(key uint64) error {
+	pc.counts[key]++
+	if pc.spillThreshold > 0 && len(pc.counts) >= pc.spillThreshold {
+		return pc.spill()
+	}
+	return nil
+}
+
+// spill writes the in-memory counts to a sorted run file and resets them
+func (pc *pairCounter) spill() error {
+	f, err := os.CreateTemp(pc.spillDir, "histui-pairs-*.run")
+	if err != nil {
+		return fmt.Errorf("failed to create spill file: %w", err)
+	}
+	pc.runs = append(pc.runs, f.Name())
+
+	w := bufio.NewWriter(f)
+	for _, key := range pc.sortedKeys() {
+		if err := writeRecord(w, key, pc.counts[key]); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write spill file: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write spill file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close spill file: %w", err)
+	}
+
+	// Reuse the map's storage for the next run
+	clear(pc.counts)
+	return nil
+}
+
+// each calls fn once per distinct pair with its total count, merging spilled
+// runs with the in-memory counts. Spill files are removed afterwards.
+func (pc *pairCounter) each(fn func(key uint64, count uint32)) error {
+	if len(pc.runs) == 0 {
+		for key, count := range pc.counts {
+			fn(key, count)
+		}
+		return nil
+	}
+	defer pc.cleanup()
+
+	// Spill the remainder so every source is a sorted run
+	if len(pc.counts) > 0 {
+		if err := pc.spill(); err != nil {
+			return err
+		}
+	}
+
+	h := &runHeap{}
+	for _, name := range pc.runs {
+		f, err := os.Open(name)
+		if err != nil {
+			return fmt.Errorf("failed to open spill file: %w", err)
+		}
+		defer f.Close()
+
+		r := &runReader{r: bufio.NewReader(f)}
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Push(h, r)
+		}
+	}
+
+	// k-way merge: equal keys from different runs are adjacent in heap order
+	for h.Len() > 0 {
+		key := (*h)[0].key
+		var total uint32
+		for h.Len() > 0 && (*h)[0].key == key {
+			r := (*h)[0]
+			total += r.count
+			ok, err := r.next()
+			if err != nil {
+				return err
+			}
+			if ok {
+				heap.Fix(h, 0)
+			} else {
+				heap.Pop(h)
+			}
+		}
+		fn(key, total)
+	}
+	return nil
+}
+
+// cleanup removes all spill files
+func (pc *pairCounter) cleanup() {
+	for _, name := range pc.runs {
+		os.Remove(name)
+	}
+	pc.runs = nil
+}
+
+// sortedKeys returns the in-memory pair keys in ascending order
+func (pc *pairCounter) sortedKeys() []uint64 {
+	keys := make([]uint64, 0, len(pc.counts))
+	for key := range pc.counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// writeRecord writes one (key, count) record of a spill run
+func writeRecord(w io.Writer, key uint64, count uint32) error {
+	var buf [12]byte
+	binary.LittleEndian.PutUint64(buf[:8], key)
+	binary.LittleEndian.PutUint32(buf[8:], count)
+	_, err := w.Write(buf[:])
+	return err
+}
+
+// runReader streams records from a spill run
+type runReader struct {
+	r     *bufio.Reader
+	key   uint64
+	count uint32
+}
+
+// next advances to the next record, returning false at the end of the run
+func (rr *runReader) next() (bool, error) {
+	var buf [12]byte
+	if _, err := io.ReadFull(rr.r, buf[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read spill file: %w", err)
+	}
+	rr.key = binary.LittleEndian.Uint64(buf[:8])
+	rr.count = binary.LittleEndian.Uint32(buf[8:])
+	return true, nil
+}
+
+// runHeap orders run readers by their current key
+type runHeap []*runReader
+
+func (h runHeap) Len() int            { return len(h) }
+func (h runHeap) Less(i, j int) bool  { return h[i].key < h[j].key }
+func (h runHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x interface{}) { *h = append(*h, x.(*runReader)) }
+func (h *runHeap) Pop() interface{} {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
@@ -0,0 +1,253 @@
+package analysis
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"histui/internal/git"
+)
+
+// syntheticHistory generates commits whose files cluster into modules, so
+// that pair counts resemble a real repository, with a huge commit (a mass
+// rename or reformat) every hugeEvery commits
+func syntheticHistory(numCommits, numFiles, maxFiles, hugeEvery, hugeSize int, seed int64) []git.Commit {
+	rng := rand.New(rand.NewSource(seed))
+
+	paths := make([]string, numFiles)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("module%03d/pkg%02d/file%05d.go", i/200, (i/20)%10, i)
+	}
+
+	commits := make([]git.Commit, numCommits)
+	for i := range commits {
+		var picked map[int]bool
+		if hugeEvery > 0 && i%hugeEvery == hugeEvery-1 {
+			picked = pickFiles(rng, numFiles, min(hugeSize, numFiles), 0, numFiles)
+		} else {
+			// Regular commits mostly stay within one module
+			module := rng.Intn((numFiles + 199) / 200)
+			lo, hi := module*200, min((module+1)*200, numFiles)
+			picked = pickFiles(rng, numFiles, 1+rng.Intn(maxFiles), lo, hi)
+		}
+
+		changes := make([]git.FileChange, 0, len(picked))
+		for idx := range picked {
+			changes = append(changes, git.FileChange{Path: paths[idx], LinesAdded: 1})
+		}
+		commits[i] = git.Commit{FilesChanged: changes}
+	}
+	return commits
+}
+
+// pickFiles picks up to n distinct file indexes, preferring the range [lo, hi)
+func pickFiles(rng *rand.Rand, numFiles, n, lo, hi int) map[int]bool {
+	picked := make(map[int]bool, n)
+	for len(picked) < n && len(picked) < numFiles {
+		if rng.Intn(10) < 9 && hi > lo {
+			picked[lo+rng.Intn(hi-lo)] = true
+		} else {
+			picked[rng.Intn(numFiles)] = true
+		}
+	}
+	return picked
+}
+
+func TestPairKey(t *testing.T) {
+	tests := []struct {
+		a, b uint32
+		want uint64
+	}{
+		{0, 1, 1},
+		{1, 0, 1},
+		{2, 3, 2<<32 | 3},
+		{1<<32 - 1, 0, 1<<32 - 1},
+		{7, 7, 7<<32 | 7},
+	}
+
+	for _, tt := range tests {
+		got := pairKey(tt.a, tt.b)
+		if got != tt.want {
+			t.Errorf("pairKey(%d, %d) = %#x, want %#x", tt.a, tt.b, got, tt.want)
+		}
+		lo, hi := tt.a, tt.b
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		if a, b := unpackPairKey(got); a != lo || b != hi {
+			t.Errorf("unpackPairKey(%#x) = %d, %d, want %d, %d", got, a, b, lo, hi)
+		}
+	}
+}
+
+func TestPairCounterSpill(t *testing.T) {
+	// Keys repeat across and within runs, so the merge has to add them up
+	var keys []uint64
+	for i := 0; i < 500; i++ {
+		keys = append(keys, pairKey(uint32(i%17), uint32(i%31)+17))
+	}
+	want := make(map[uint64]uint32)
+	for _, key := range keys {
+		want[key]++
+	}
+
+	tests := []struct {
+		name      string
+		threshold int
+		spilled   bool
+	}{
+		{"in memory", 0, false},
+		{"threshold above distinct pairs", 1000, false},
+		{"one pair per run", 1, true},
+		{"few pairs per run", 7, true},
+		{"many pairs per run", 100, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			pc := newPairCounter(tt.threshold, dir)
+			for _, key := range keys {
+				if err := pc.add(key); err != nil {
+					t.Fatalf("add: %v", err)
+				}
+			}
+			if spilled := len(pc.runs) > 0; spilled != tt.spilled {
+				t.Errorf("spilled = %v, want %v", spilled, tt.spilled)
+			}
+
+			got := make(map[uint64]uint32)
+			err := pc.each(func(key uint64, count uint32) {
+				if _, dup := got[key]; dup {
+					t.Errorf("pair %#x reported twice", key)
+				}
+				got[key] = count
+			})
+			if err != nil {
+				t.Fatalf("each: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("counts differ: got %d pairs, want %d", len(got), len(want))
+			}
+
+			left, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(left) > 0 {
+				t.Errorf("%d spill files left behind", len(left))
+			}
+		})
+	}
+}
+
+func TestCouplingCountingModesAgree(t *testing.T) {
+	commits := syntheticHistory(2000, 600, 8, 250, 60, 1)
+	dir := t.TempDir()
+
+	modes := []struct {
+		name string
+		opts CouplingOptions
+	}{
+		{"sharded", CouplingOptions{Workers: 4}},
+		{"spilled", CouplingOptions{SpillThreshold: 500, SpillDir: dir}},
+		{"sharded and spilled", CouplingOptions{Workers: 3, SpillThreshold: 200, SpillDir: dir}},
+	}
+
+	// MinCoChanges 1 compares every pair, not only the strong ones
+	serial, err := AnalyzeFileCouplingWithOptions(commits, nil, CouplingOptions{MinCoChanges: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := pairsByName(serial.Pairs)
+	if len(want) == 0 {
+		t.Fatal("synthetic history has no coupled pairs")
+	}
+
+	for _, mode := range modes {
+		t.Run(mode.name, func(t *testing.T) {
+			mode.opts.MinCoChanges = 1
+			results, err := AnalyzeFileCouplingWithOptions(commits, nil, mode.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := pairsByName(results.Pairs)
+			if len(got) != len(want) {
+				t.Errorf("%d pairs, want %d", len(got), len(want))
+			}
+			for name, pair := range want {
+				if got[name] != pair {
+					t.Errorf("%s = %+v, want %+v", name, got[name], pair)
+					break
+				}
+			}
+			if !reflect.DeepEqual(results.FileTotalChanges, serial.FileTotalChanges) {
+				t.Error("file change totals differ")
+			}
+		})
+	}
+}
+
+// pairsByName indexes pairs by their file names, in a fixed order
+func pairsByName(pairs []FilePair) map[string]FilePair {
+	byName := make(map[string]FilePair, len(pairs))
+	for _, p := range pairs {
+		if p.FileA > p.FileB {
+			p.FileA, p.FileB = p.FileB, p.FileA
+		}
+		byName[p.FileA+" <-> "+p.FileB] = p
+	}
+	return byName
+}
+
+// BenchmarkCoupling counts pairs in a mid-sized history and in one of the
+// size the spilling and sharding were built for
+func BenchmarkCoupling(b *testing.B) {
+	sizes := []struct {
+		name             string
+		commits, files   int
+		hugeEvery, huges int
+	}{
+		{"20k commits", 20000, 5000, 2000, 500},
+		{"100k commits", 100000, 20000, 2000, 500},
+	}
+	dir := b.TempDir()
+	workers := runtime.NumCPU()
+
+	modes := []struct {
+		name string
+		opts CouplingOptions
+	}{
+		{"serial", CouplingOptions{}},
+		{"sharded", CouplingOptions{Workers: workers}},
+		{"spilled", CouplingOptions{SpillThreshold: 1 << 16, SpillDir: dir}},
+		{"sharded and spilled", CouplingOptions{Workers: workers, SpillThreshold: 1 << 16, SpillDir: dir}},
+	}
+
+	for _, size := range sizes {
+		b.Run(size.name, func(b *testing.B) {
+			// Generated per size, so that running one size does not pay for the other
+			commits := syntheticHistory(size.commits, size.files, 12, size.hugeEvery, size.huges, 1)
+			occurrences := 0
+			for _, c := range commits {
+				n := len(c.FilesChanged)
+				occurrences += n * (n - 1) / 2
+			}
+
+			for _, mode := range modes {
+				b.Run(mode.name, func(b *testing.B) {
+					b.ReportAllocs()
+					for i := 0; i < b.N; i++ {
+						if _, err := AnalyzeFileCouplingWithOptions(commits, nil, mode.opts); err != nil {
+							b.Fatal(err)
+						}
+					}
+					b.ReportMetric(float64(occurrences)*float64(b.N)/b.Elapsed().Seconds(), "pairs/s")
+				})
+			}
+		})
+	}
+}
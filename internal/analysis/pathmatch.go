@@ -0,0 +1,58 @@
+package analysis
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// MatchPath reports whether a slash-separated file path matches a pattern.
+//
+// Supported pattern forms:
+//   - "api/"            everything below the api directory
+//   - "*.go"            patterns without a slash match the base name
+//   - "src/*/main.go"   '*', '?' and '[...]' match within a single segment
+//   - "src/**/test_*"   '**' matches any number of segments (including none)
+func MatchPath(pattern, path string) bool {
+	if pattern == "" {
+		return false
+	}
+
+	// Directory prefix
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+
+	// Patterns without a slash match the file name anywhere in the tree
+	if !strings.Contains(pattern, "/") {
+		matched, err := filepath.Match(pattern, filepath.Base(path))
+		return err == nil && matched
+	}
+
+	return matchSegments(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), strings.Split(path, "/"))
+}
+
+// matchSegments matches pattern segments against path segments, expanding "**"
+func matchSegments(pattern, path []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Collapse consecutive "**" and try every possible expansion
+			rest := pattern[1:]
+			for i := 0; i <= len(path); i++ {
+				if matchSegments(rest, path[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(path) == 0 {
+			return false
+		}
+		matched, err := filepath.Match(pattern[0], path[0])
+		if err != nil || !matched {
+			return false
+		}
+		pattern, path = pattern[1:], path[1:]
+	}
+	return len(path) == 0
+}
@@ -0,0 +1,59 @@
+package analysis
+
+import "testing"
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		// Directory prefixes
+		{"api/", "api/handlers.go", true},
+		{"api/", "api/v1/users/get.go", true},
+		{"api/", "api.go", false},
+		{"api/", "internal/api/handlers.go", false},
+		{"db/migrations/", "db/migrations/001_init.sql", true},
+		{"db/migrations/", "db/seed.sql", false},
+
+		// Patterns without a slash match the base name anywhere
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/histui/main.go", true},
+		{"*.go", "README.md", false},
+		{"Makefile", "build/Makefile", true},
+		{"test_?.py", "tests/test_a.py", true},
+		{"test_?.py", "tests/test_ab.py", false},
+
+		// Single-segment wildcards
+		{"src/*/main.go", "src/app/main.go", true},
+		{"src/*/main.go", "src/app/sub/main.go", false},
+		{"src/*/main.go", "src/main.go", false},
+		{"cmd/[a-c]*/main.go", "cmd/bench/main.go", true},
+		{"cmd/[a-c]*/main.go", "cmd/histui/main.go", false},
+
+		// "**" spans any number of segments, including none
+		{"src/**/test_*", "src/test_a.py", true},
+		{"src/**/test_*", "src/a/b/c/test_a.py", true},
+		{"src/**/test_*", "lib/test_a.py", false},
+		{"internal/**", "internal/git/cli_repo.go", true},
+		{"internal/**", "internal", true},
+		{"**/*.go", "main.go", true},
+		{"**/**/x.go", "a/x.go", true},
+
+		// Anchored paths
+		{"/cmd/main.go", "cmd/main.go", true},
+		{"cmd/main.go", "cmd/main.go", true},
+		{"cmd/main.go", "x/cmd/main.go", false},
+
+		// Degenerate patterns never match
+		{"", "main.go", false},
+		{"[", "main.go", false},
+		{"src/[", "src/main.go", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchPath(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("MatchPath(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
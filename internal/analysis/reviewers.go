@@ -0,0 +1,297 @@
+package analysis
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// Weights of the evidence behind a reviewer suggestion, relative to expertise
+// in a changed file
+const (
+	reviewerWeightReviews  = 0.5 // Earlier reviews of the changed files
+	reviewerWeightDirs     = 0.5 // Expertise in the directory of a new file
+	reviewerWeightCoupling = 0.5 // Expertise in coupled files, times the coupling score
+)
+
+// ReviewerSuggestion is a suggested reviewer and why
+type ReviewerSuggestion struct {
+	Reviewer string
+	Score    float64
+	Owns     []string // Changed files the reviewer is the top expert of
+	Knows    []string // Other changed files or directories of new files the reviewer knows
+	Reviews  int      // Earlier commits to the changed files the reviewer reviewed
+	Coupled  []string // Files coupled with the change that the reviewer is the top expert of
+}
+
+// Reasons explains a suggestion in short phrases
+func (s ReviewerSuggestion) Reasons() []string {
+	var reasons []string
+	if len(s.Owns) > 0 {
+		reasons = append(reasons, "owns "+abbreviateList(s.Owns, 2))
+	}
+	if len(s.Knows) > 0 {
+		reasons = append(reasons, "knows "+abbreviateList(s.Knows, 2))
+	}
+	if s.Reviews > 0 {
+		reasons = append(reasons, fmt.Sprintf("reviewed %d earlier changes", s.Reviews))
+	}
+	if len(s.Coupled) > 0 {
+		reasons = append(reasons, "owns coupled "+abbreviateList(s.Coupled, 2))
+	}
+	return reasons
+}
+
+// abbreviateList joins the first n items, noting how many were left out
+func abbreviateList(items []string, n int) string {
+	if len(items) <= n {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s (+%d)", strings.Join(items[:n], ", "), len(items)-n)
+}
+
+// ReviewerResults holds the reviewers suggested for a change set
+type ReviewerResults struct {
+	Files       []string     // Changed files considered
+	Authors     []git.Author // Authors of the change, never suggested
+	Suggestions []ReviewerSuggestion
+	Inactive    []string // Experts left out because they stopped committing
+}
+
+// ReviewerOptions configures reviewer suggestions
+type ReviewerOptions struct {
+	HalfLifeDays   int       // See config.ExpertsConfig
+	InactiveMonths int       // Experts without commits for this long are not suggested (0 = never)
+	MinCoupling    float64   // Coupling score from which coupled files count
+	Now            time.Time // Reference time (zero = time.Now())
+}
+
+// SuggestReviewers ranks reviewers for a change to files from history: their
+// expertise in the changed files (or, for new files, their directories), the
+// earlier changes to the files they reviewed (Reviewed-by trailers), and their
+// ownership of files coupled with the change. The authors of the change and
+// inactive experts are left out.
+func SuggestReviewers(history []git.Commit, files []string, authors []git.Author, coupling CouplingResults, opts ReviewerOptions) ReviewerResults {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	results := ReviewerResults{Files: files, Authors: authors}
+	expertOpts := ExpertOptions{HalfLifeDays: opts.HalfLifeDays, InactiveMonths: opts.InactiveMonths, Now: opts.Now}
+
+	excluded := make(map[string]bool)
+	for _, a := range authors {
+		excluded[strings.ToLower(a.Name)] = true
+	}
+	for _, c := range history {
+		for _, a := range authors {
+			if a.Email != "" && strings.EqualFold(c.Author.Email, a.Email) {
+				excluded[strings.ToLower(c.Author.Name)] = true
+			}
+		}
+	}
+
+	suggestions := make(map[string]*ReviewerSuggestion)
+	inactive := make(map[string]bool)
+	credit := func(name string, score float64) *ReviewerSuggestion {
+		if suggestions[name] == nil {
+			suggestions[name] = &ReviewerSuggestion{Reviewer: name}
+		}
+		suggestions[name].Score += score
+		return suggestions[name]
+	}
+
+	// Coupled files the change leaves out
+	missing := SuggestMissingFiles(coupling, files, opts.MinCoupling)
+	missing = missing[:min(10, len(missing))]
+
+	// The experts of every file, directory and coupled file that may be
+	// asked, collected in one pass over the history
+	var targets []string
+	for _, file := range files {
+		targets = append(targets, file, path.Dir(file))
+	}
+	for _, m := range missing {
+		targets = append(targets, m.File)
+	}
+	index := rankExperts(history, targets, nil, nil, expertOpts)
+
+	// experts returns the active experts of target who may review, best first
+	experts := func(target string) []Expert {
+		var active []Expert
+		for _, e := range index[target].Experts {
+			switch {
+			case excluded[strings.ToLower(e.Author)]:
+			case e.Inactive:
+				inactive[e.Author] = true
+			default:
+				active = append(active, e)
+			}
+		}
+		return active
+	}
+
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		fileExperts := experts(file)
+		if len(fileExperts) == 0 {
+			// A new file: ask the people who know its directory
+			dir := path.Dir(file)
+			if dirs[dir] {
+				continue
+			}
+			dirs[dir] = true
+			for _, e := range experts(dir) {
+				s := credit(e.Author, reviewerWeightDirs*e.Score)
+				s.Knows = append(s.Knows, dir+"/")
+			}
+			continue
+		}
+		for i, e := range fileExperts {
+			s := credit(e.Author, e.Score)
+			if i == 0 {
+				s.Owns = append(s.Owns, file)
+			} else {
+				s.Knows = append(s.Knows, file)
+			}
+		}
+	}
+
+	// Earlier reviews of the changed files
+	changed := make(map[string]bool, len(files))
+	for _, file := range files {
+		changed[file] = true
+	}
+	reviews := make(map[string]int)
+	totalReviews := 0
+	renames := make(renameTracker)
+	for _, commit := range history {
+		touches := false
+		for _, fc := range commit.FilesChanged {
+			touches = changed[renames.current(fc)] || touches
+		}
+		if !touches {
+			continue
+		}
+		for _, r := range Reviewers(commit.Body) {
+			name := r.Name
+			if name == "" {
+				name = r.Email
+			}
+			if !excluded[strings.ToLower(name)] {
+				reviews[name]++
+				totalReviews++
+			}
+		}
+	}
+	for name, n := range reviews {
+		if inactive[name] {
+			continue
+		}
+		credit(name, reviewerWeightReviews*normalize(n, totalReviews)).Reviews = n
+	}
+
+	// Owners of coupled files know how the change ripples
+	for _, m := range missing {
+		if fileExperts := experts(m.File); len(fileExperts) > 0 {
+			e := fileExperts[0]
+			s := credit(e.Author, reviewerWeightCoupling*m.Confidence*e.Score)
+			s.Coupled = append(s.Coupled, m.File)
+		}
+	}
+
+	for _, s := range suggestions {
+		s.Score /= float64(max(len(files), 1))
+		results.Suggestions = append(results.Suggestions, *s)
+	}
+	sort.Slice(results.Suggestions, func(i, j int) bool {
+		a, b := results.Suggestions[i], results.Suggestions[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Reviewer < b.Reviewer
+	})
+	for name := range inactive {
+		results.Inactive = append(results.Inactive, name)
+	}
+	sort.Strings(results.Inactive)
+
+	return results
+}
+
+// ReviewersAt suggests reviewers for the commits of a revision range
+// ("main..feature", or a single commit), or for the staged changes when
+// revRange is empty, using the history of the range's tip before it
+func ReviewersAt(repo git.Repository, revRange string, opts git.LoadOptions, ignorePatterns []string, cfg config.Config) (ReviewerResults, error) {
+	var files []string
+	var authors []git.Author
+	tip := "HEAD"
+	inRange := make(map[string]bool)
+
+	if revRange == "" {
+		staged, err := repo.GetStagedFiles()
+		if err != nil {
+			return ReviewerResults{}, err
+		}
+		if len(staged) == 0 {
+			return ReviewerResults{}, nil
+		}
+		files = staged
+		// Without a git identity, no one is left out as the author
+		if user, err := repo.GetUserIdent(); err == nil {
+			authors = append(authors, user)
+		}
+	} else {
+		var selection string
+		selection, tip = splitRange(revRange)
+		opts.Branch = selection
+		selected, _, _, _, err := repo.LoadCommits(opts)
+		if err != nil {
+			return ReviewerResults{}, err
+		}
+		seen := make(map[string]bool)
+		for _, c := range selected {
+			inRange[c.SHA] = true
+			if !seen[strings.ToLower(c.Author.Email)] {
+				seen[strings.ToLower(c.Author.Email)] = true
+				authors = append(authors, c.Author)
+			}
+			for _, fc := range c.FilesChanged {
+				if !contains(files, fc.Path) {
+					files = append(files, fc.Path)
+				}
+			}
+		}
+	}
+
+	var kept []string
+	for _, file := range files {
+		if !shouldIgnoreFile(file, ignorePatterns) {
+			kept = append(kept, file)
+		}
+	}
+	sort.Strings(kept)
+
+	opts.Branch = tip
+	opts.MaxCommits = 0
+	all, _, _, _, err := repo.LoadCommits(opts)
+	if err != nil {
+		return ReviewerResults{}, err
+	}
+	history := make([]git.Commit, 0, len(all))
+	for _, c := range all {
+		if !inRange[c.SHA] {
+			history = append(history, c)
+		}
+	}
+
+	return SuggestReviewers(history, kept, authors, AnalyzeFileCoupling(history, ignorePatterns), ReviewerOptions{
+		HalfLifeDays:   cfg.Experts.HalfLifeDays,
+		InactiveMonths: cfg.Knowledge.OrphanMonths,
+		MinCoupling:    cfg.Impact.MinScore,
+	}), nil
+}
@@ -0,0 +1,113 @@
+package analysis
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"histui/internal/git"
+)
+
+func TestSuggestReviewers(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	commit := func(name, email string, when time.Time, body string, paths ...string) git.Commit {
+		c := git.Commit{Author: git.Author{Name: name, Email: email}, Timestamp: when, Body: body}
+		for _, path := range paths {
+			c.FilesChanged = append(c.FilesChanged, git.FileChange{Path: path, LinesAdded: 1})
+		}
+		return c
+	}
+
+	// Newest first
+	history := []git.Commit{
+		commit("Alice", "alice@example.com", daysAgo(3), "Reviewed-by: Carol <carol@example.com>", "api/handler.go"),
+		commit("Bob", "bob@example.com", daysAgo(5), "", "api/handler.go", "api/routes.go"),
+		// The author of the change, committing under another name
+		commit("Dan W.", "dan@example.com", daysAgo(6), "", "api/handler.go"),
+		commit("Bob", "bob@example.com", daysAgo(10), "Reviewed-by: Carol <carol@example.com>", "api/routes.go"),
+		commit("Erin", "erin@example.com", daysAgo(20), "", "web/app.js"),
+		// Long gone
+		commit("Gus", "gus@example.com", daysAgo(800), "Reviewed-by: Frank <frank@example.com>", "api/handler.go", "api/db.go"),
+	}
+	files := []string{"api/handler.go", "web/new.js"}
+	authors := []git.Author{{Name: "Dan", Email: "dan@example.com"}}
+	coupling := CouplingResults{Pairs: []FilePair{
+		{FileA: "api/handler.go", FileB: "api/routes.go", CoChanges: 2, ScoreValue: 0.8},
+	}}
+
+	results := SuggestReviewers(history, files, authors, coupling, ReviewerOptions{
+		HalfLifeDays:   30,
+		InactiveMonths: 12,
+		MinCoupling:    0.5,
+		Now:            now,
+	})
+
+	suggested := make(map[string]ReviewerSuggestion)
+	var order []string
+	for _, s := range results.Suggestions {
+		suggested[s.Reviewer] = s
+		order = append(order, s.Reviewer)
+	}
+	if _, ok := suggested["Dan W."]; ok {
+		t.Error("the author of the change is suggested under another name")
+	}
+	if _, ok := suggested["Gus"]; ok {
+		t.Error("an inactive expert is suggested")
+	}
+	if !reflect.DeepEqual(results.Inactive, []string{"Gus"}) {
+		t.Errorf("inactive = %v, want Gus", results.Inactive)
+	}
+
+	if a := suggested["Alice"]; !reflect.DeepEqual(a.Owns, []string{"api/handler.go"}) {
+		t.Errorf("Alice owns %v, want api/handler.go", a.Owns)
+	}
+	b := suggested["Bob"]
+	if !reflect.DeepEqual(b.Knows, []string{"api/handler.go"}) || !reflect.DeepEqual(b.Coupled, []string{"api/routes.go"}) {
+		t.Errorf("Bob knows %v and owns coupled %v, want api/handler.go and api/routes.go", b.Knows, b.Coupled)
+	}
+	// Reviews of the changed files count; Frank's review predates Gus leaving
+	// but Frank is not known to be inactive
+	if c := suggested["Carol"]; c.Reviews != 1 || c.Score <= 0 {
+		t.Errorf("Carol = %+v, want credit for 1 review", c)
+	}
+	if f := suggested["Frank"]; f.Reviews != 1 {
+		t.Errorf("Frank = %+v, want credit for 1 review", f)
+	}
+	// Nobody changed web/new.js, so the people who know web/ are asked
+	if e := suggested["Erin"]; !reflect.DeepEqual(e.Knows, []string{"web/"}) {
+		t.Errorf("Erin knows %v, want web/", e.Knows)
+	}
+
+	// Owning the coupled file puts Bob ahead of Alice, the top expert of the file
+	if len(order) < 3 || order[0] != "Bob" || order[2] != "Alice" {
+		t.Errorf("suggestions in order %v, want Bob first and Alice third", order)
+	}
+}
+
+func TestRankExpertsMatchesAnalyzeExperts(t *testing.T) {
+	commits := syntheticHistory(300, 60, 6, 0, 0, 7)
+	for i := range commits {
+		commits[i].Author.Name = []string{"Alice", "Bob", "Carol"}[i%3]
+		commits[i].Timestamp = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -i)
+	}
+	opts := ExpertOptions{HalfLifeDays: 30, InactiveMonths: 1, Now: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}
+	targets := []string{"", ".", "./", commits[0].FilesChanged[0].Path, rollUp(commits[1].FilesChanged[0].Path, 1), "module000/pkg01", "missing/"}
+
+	// Scores are sums in map order, so they may differ in the last bits
+	rounded := func(results ExpertResults) ExpertResults {
+		for i := range results.Experts {
+			results.Experts[i].Score = math.Round(results.Experts[i].Score*1e9) / 1e9
+		}
+		return results
+	}
+
+	index := rankExperts(commits, targets, nil, nil, opts)
+	for _, target := range targets {
+		want := rounded(AnalyzeExperts(commits, target, nil, nil, opts))
+		if got := rounded(index[target]); !reflect.DeepEqual(got, want) {
+			t.Errorf("experts of %q = %+v, want %+v", target, got.Experts, want.Experts)
+		}
+	}
+}
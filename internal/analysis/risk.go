@@ -0,0 +1,247 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// Risk factors, in the order they are reported
+const (
+	RiskSize       = "size"       // Lines changed, on a log scale up to 1000
+	RiskSpread     = "spread"     // Top-level modules touched
+	RiskHotspots   = "hotspots"   // Highest hotspot score of the changed files
+	RiskExperience = "experience" // Share of the files the author never changed before
+	RiskTime       = "time"       // Late-night, weekend or out-of-hours commit
+	RiskCoupling   = "coupling"   // Coupled files left unchanged
+)
+
+// riskWeights are the weights of the factors in the risk score; they sum to 1
+var riskWeights = map[string]float64{
+	RiskSize:       0.25,
+	RiskSpread:     0.15,
+	RiskHotspots:   0.20,
+	RiskExperience: 0.20,
+	RiskTime:       0.10,
+	RiskCoupling:   0.10,
+}
+
+// RiskFactor is one factor's contribution to a commit's risk
+type RiskFactor struct {
+	Name   string
+	Value  float64 // 0.0 (no risk) to 1.0
+	Detail string
+}
+
+// CommitRisk is the risk score of one commit
+type CommitRisk struct {
+	SHA       string
+	ShortSHA  string
+	Author    string
+	Timestamp time.Time
+	Subject   string
+	Score     float64 // 0 to 100
+	Factors   []RiskFactor
+}
+
+// RiskResults holds the risk of the commits in a range, riskiest first
+type RiskResults struct {
+	Commits []CommitRisk
+}
+
+// RiskBaseline is what is known from the history before the scored commits
+type RiskBaseline struct {
+	Hotspots    map[string]float64 // Hotspot score per file
+	Coupling    CouplingResults
+	MinCoupling float64 // Coupling score from which a partner left unchanged counts
+	Time        config.TimeConfig
+}
+
+// NewRiskBaseline computes hotspot scores and coupling from history, the
+// commits before the ones to score
+func NewRiskBaseline(history []git.Commit, lineCounts map[string]int, ignorePatterns []string, cfg config.Config) RiskBaseline {
+	hotspots := AnalyzeHotspots(history, lineCounts, nil, nil, ignorePatterns, HotspotOptions{})
+	scores := make(map[string]float64, len(hotspots.Hotspots))
+	for _, h := range hotspots.Hotspots {
+		scores[h.Path] = h.Score
+	}
+	return RiskBaseline{
+		Hotspots:    scores,
+		Coupling:    AnalyzeFileCoupling(history, ignorePatterns),
+		MinCoupling: cfg.Impact.MinScore,
+		Time:        cfg.Time,
+	}
+}
+
+// AnalyzeRisk scores the commits selected by shas. history must hold them
+// and all older commits, newest first; an author's experience with a file is
+// taken from the commits before the scored one.
+func AnalyzeRisk(history []git.Commit, shas map[string]bool, baseline RiskBaseline, ignorePatterns []string) RiskResults {
+	var results RiskResults
+
+	// Current paths of every commit's files, following renames
+	paths := make([][]string, len(history))
+	renames := make(renameTracker)
+	for i, commit := range history {
+		for _, fc := range commit.FilesChanged {
+			if path := renames.current(fc); !shouldIgnoreFile(path, ignorePatterns) {
+				paths[i] = append(paths[i], path)
+			}
+		}
+	}
+
+	// Oldest first, so that experience only counts earlier commits
+	touched := make(map[string]map[string]bool) // Author -> files changed
+	for i := len(history) - 1; i >= 0; i-- {
+		commit := history[i]
+		author := commit.Author.Name
+		if shas[commit.SHA] {
+			results.Commits = append(results.Commits, scoreCommit(commit, paths[i], touched[author], baseline))
+		}
+		if touched[author] == nil {
+			touched[author] = make(map[string]bool)
+		}
+		for _, path := range paths[i] {
+			touched[author][path] = true
+		}
+	}
+
+	sort.SliceStable(results.Commits, func(i, j int) bool { return results.Commits[i].Score > results.Commits[j].Score })
+	return results
+}
+
+// scoreCommit computes the risk factors of a commit changing paths, by an
+// author who previously changed the files in known
+func scoreCommit(commit git.Commit, paths []string, known map[string]bool, baseline RiskBaseline) CommitRisk {
+	risk := CommitRisk{
+		SHA:       commit.SHA,
+		ShortSHA:  commit.ShortSHA,
+		Author:    commit.Author.Name,
+		Timestamp: commit.Timestamp,
+		Subject:   commit.Subject,
+	}
+	add := func(name string, value float64, format string, args ...interface{}) {
+		risk.Factors = append(risk.Factors, RiskFactor{Name: name, Value: value, Detail: fmt.Sprintf(format, args...)})
+		risk.Score += 100 * riskWeights[name] * value
+	}
+
+	lines := commit.Stats.Insertions + commit.Stats.Deletions
+	add(RiskSize, math.Min(1, math.Log10(float64(1+lines))/3), "%d lines in %d files", lines, len(commit.FilesChanged))
+
+	modules := make(map[string]bool)
+	for _, path := range paths {
+		modules[rollUp(path, 1)] = true
+	}
+	add(RiskSpread, math.Min(1, float64(max(len(modules)-1, 0))/4), "%d modules", len(modules))
+
+	hottest, hotScore := "", 0.0
+	for _, path := range paths {
+		if s := baseline.Hotspots[path]; s > hotScore {
+			hottest, hotScore = path, s
+		}
+	}
+	if hottest != "" {
+		add(RiskHotspots, hotScore, "touches %s (hotspot score %.2f)", hottest, hotScore)
+	} else {
+		add(RiskHotspots, 0, "no hotspots")
+	}
+
+	unknown := 0
+	for _, path := range paths {
+		if !known[path] {
+			unknown++
+		}
+	}
+	add(RiskExperience, normalize(unknown, len(paths)), "first change to %d of %d files", unknown, len(paths))
+
+	local := commit.Timestamp
+	hour, weekday := local.Hour(), local.Weekday()
+	when := local.Format("Mon 15:04")
+	switch {
+	case hour < 6 || hour >= 22:
+		add(RiskTime, 1, "late night (%s)", when)
+	case weekday == time.Saturday || weekday == time.Sunday:
+		add(RiskTime, 0.5, "weekend (%s)", when)
+	case hour < baseline.Time.WorkStart || hour >= baseline.Time.WorkEnd:
+		add(RiskTime, 0.5, "out of hours (%s)", when)
+	default:
+		add(RiskTime, 0, "working hours (%s)", when)
+	}
+
+	missing := SuggestMissingFiles(baseline.Coupling, paths, baseline.MinCoupling)
+	if len(missing) > 0 {
+		names := make([]string, 0, 3)
+		for _, s := range missing[:min(3, len(missing))] {
+			names = append(names, s.File)
+		}
+		add(RiskCoupling, math.Min(1, float64(len(missing))/3), "%d coupled files unchanged: %s", len(missing), strings.Join(names, ", "))
+	} else {
+		add(RiskCoupling, 0, "no coupled files left out")
+	}
+
+	return risk
+}
+
+// Top returns the factors that contribute most to the score, largest first
+func (r CommitRisk) Top(n int) []RiskFactor {
+	factors := make([]RiskFactor, 0, len(r.Factors))
+	for _, f := range r.Factors {
+		if f.Value > 0 {
+			factors = append(factors, f)
+		}
+	}
+	sort.SliceStable(factors, func(i, j int) bool {
+		return riskWeights[factors[i].Name]*factors[i].Value > riskWeights[factors[j].Name]*factors[j].Value
+	})
+	return factors[:min(n, len(factors))]
+}
+
+// splitRange returns the revisions selecting the commits of a range
+// ("main..feature", or a single commit as "<sha>^!") and the range's tip
+func splitRange(revRange string) (selection, tip string) {
+	if i := strings.LastIndex(revRange, ".."); i >= 0 {
+		return revRange, revisionOrHead(revRange[i+2:])
+	}
+	return revRange + "^!", revRange
+}
+
+// RiskAt scores the commits of a revision range ("main..feature", or a single
+// commit) against the history of the range's tip before them
+func RiskAt(repo git.Repository, revRange string, opts git.LoadOptions, ignorePatterns []string, cfg config.Config) (RiskResults, error) {
+	selection, tip := splitRange(revRange)
+	opts.Branch = selection
+	selected, _, _, _, err := repo.LoadCommits(opts)
+	if err != nil {
+		return RiskResults{}, err
+	}
+	shas := make(map[string]bool, len(selected))
+	for _, c := range selected {
+		shas[c.SHA] = true
+	}
+
+	opts.Branch = tip
+	opts.MaxCommits = 0
+	history, _, _, _, err := repo.LoadCommits(opts)
+	if err != nil {
+		return RiskResults{}, err
+	}
+
+	before := make([]git.Commit, 0, len(history))
+	for _, c := range history {
+		if !shas[c.SHA] {
+			before = append(before, c)
+		}
+	}
+	lineCounts, err := repo.GetLineCounts(tip)
+	if err != nil {
+		return RiskResults{}, err
+	}
+
+	baseline := NewRiskBaseline(before, lineCounts, ignorePatterns, cfg)
+	return AnalyzeRisk(history, shas, baseline, ignorePatterns), nil
+}
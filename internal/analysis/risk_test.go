@@ -0,0 +1,162 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+func TestSplitRange(t *testing.T) {
+	tests := []struct {
+		revRange      string
+		wantSelection string
+		wantTip       string
+	}{
+		{"main..feature", "main..feature", "feature"},
+		{"main...feature", "main...feature", "feature"},
+		{"origin/main..", "origin/main..", "HEAD"},
+		{"abc1234", "abc1234^!", "abc1234"},
+	}
+
+	for _, tt := range tests {
+		selection, tip := splitRange(tt.revRange)
+		if selection != tt.wantSelection || tip != tt.wantTip {
+			t.Errorf("splitRange(%q) = %q, %q, want %q, %q", tt.revRange, selection, tip, tt.wantSelection, tt.wantTip)
+		}
+	}
+}
+
+// riskFactor returns the value of the named factor of a commit's risk
+func riskFactor(t *testing.T, risk CommitRisk, name string) float64 {
+	t.Helper()
+	for _, f := range risk.Factors {
+		if f.Name == name {
+			return f.Value
+		}
+	}
+	t.Fatalf("no %s factor in %+v", name, risk.Factors)
+	return 0
+}
+
+func TestScoreCommit(t *testing.T) {
+	// Tuesday, within the default working hours of 9 to 18
+	tuesday := func(hour int) time.Time { return time.Date(2024, 3, 5, hour, 0, 0, 0, time.UTC) }
+	baseline := RiskBaseline{Time: config.Default().Time}
+
+	tests := []struct {
+		name   string
+		commit git.Commit
+		paths  []string
+		known  map[string]bool
+		factor string
+		want   float64
+	}{
+		{"no lines", git.Commit{Timestamp: tuesday(10)}, nil, nil, RiskSize, 0},
+		{"9 lines", git.Commit{Timestamp: tuesday(10), Stats: git.CommitStats{Insertions: 5, Deletions: 4}}, nil, nil, RiskSize, 1.0 / 3},
+		{"99 lines", git.Commit{Timestamp: tuesday(10), Stats: git.CommitStats{Insertions: 99}}, nil, nil, RiskSize, 2.0 / 3},
+		{"over 1000 lines", git.Commit{Timestamp: tuesday(10), Stats: git.CommitStats{Insertions: 5000}}, nil, nil, RiskSize, 1},
+
+		{"one module", git.Commit{Timestamp: tuesday(10)}, []string{"api/a.go", "api/v1/b.go"}, nil, RiskSpread, 0},
+		{"three modules", git.Commit{Timestamp: tuesday(10)}, []string{"api/a.go", "web/b.js", "README.md"}, nil, RiskSpread, 0.5},
+		{"six modules", git.Commit{Timestamp: tuesday(10)}, []string{"a/x", "b/x", "c/x", "d/x", "e/x", "f/x"}, nil, RiskSpread, 1},
+
+		{"all files known", git.Commit{Timestamp: tuesday(10)}, []string{"a.go", "b.go"}, map[string]bool{"a.go": true, "b.go": true}, RiskExperience, 0},
+		{"half the files new", git.Commit{Timestamp: tuesday(10)}, []string{"a.go", "b.go"}, map[string]bool{"a.go": true}, RiskExperience, 0.5},
+		{"first commit of the author", git.Commit{Timestamp: tuesday(10)}, []string{"a.go"}, nil, RiskExperience, 1},
+		{"no files", git.Commit{Timestamp: tuesday(10)}, nil, nil, RiskExperience, 0},
+
+		{"working hours", git.Commit{Timestamp: tuesday(9)}, nil, nil, RiskTime, 0},
+		{"evening", git.Commit{Timestamp: tuesday(18)}, nil, nil, RiskTime, 0.5},
+		{"early morning", git.Commit{Timestamp: tuesday(8)}, nil, nil, RiskTime, 0.5},
+		{"late night", git.Commit{Timestamp: tuesday(22)}, nil, nil, RiskTime, 1},
+		{"small hours", git.Commit{Timestamp: tuesday(5)}, nil, nil, RiskTime, 1},
+		{"weekend", git.Commit{Timestamp: time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)}, nil, nil, RiskTime, 0.5},
+		{"weekend night", git.Commit{Timestamp: time.Date(2024, 3, 9, 23, 0, 0, 0, time.UTC)}, nil, nil, RiskTime, 1},
+		// The commit's own timezone counts, not the reader's
+		{"late night elsewhere", git.Commit{Timestamp: time.Date(2024, 3, 5, 23, 0, 0, 0, time.FixedZone("", -5*3600))}, nil, nil, RiskTime, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			risk := scoreCommit(tt.commit, tt.paths, tt.known, baseline)
+			if got := riskFactor(t, risk, tt.factor); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("%s = %v, want %v", tt.factor, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreCommitBaseline(t *testing.T) {
+	baseline := RiskBaseline{
+		Hotspots: map[string]float64{"api/a.go": 0.8, "api/b.go": 0.3},
+		Coupling: CouplingResults{Pairs: []FilePair{
+			{FileA: "api/a.go", FileB: "api/a_test.go", CoChanges: 5, ScoreValue: 0.9},
+			{FileA: "api/b.go", FileB: "docs/api.md", CoChanges: 2, ScoreValue: 0.2},
+		}},
+		MinCoupling: 0.5,
+		Time:        config.Default().Time,
+	}
+	commit := git.Commit{
+		Timestamp: time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC),
+		Stats:     git.CommitStats{Insertions: 9},
+	}
+	paths := []string{"api/a.go", "api/b.go"}
+
+	risk := scoreCommit(commit, paths, map[string]bool{"api/a.go": true, "api/b.go": true}, baseline)
+	if got := riskFactor(t, risk, RiskHotspots); got != 0.8 {
+		t.Errorf("hotspots = %v, want the hottest file's 0.8", got)
+	}
+	// Only the partner above MinCoupling counts
+	if got := riskFactor(t, risk, RiskCoupling); math.Abs(got-1.0/3) > 1e-9 {
+		t.Errorf("coupling = %v, want 1/3", got)
+	}
+
+	want := 100 * (riskWeights[RiskSize]/3 + riskWeights[RiskHotspots]*0.8 + riskWeights[RiskCoupling]/3)
+	if math.Abs(risk.Score-want) > 1e-9 {
+		t.Errorf("score = %v, want %v", risk.Score, want)
+	}
+	if top := risk.Top(2); len(top) != 2 || top[0].Name != RiskHotspots || top[1].Name != RiskSize {
+		t.Errorf("top factors = %+v, want hotspots then size", top)
+	}
+}
+
+func TestAnalyzeRisk(t *testing.T) {
+	at := func(day int) time.Time { return time.Date(2024, 3, day, 10, 0, 0, 0, time.UTC) }
+	commit := func(sha, author string, day int, paths ...string) git.Commit {
+		c := git.Commit{SHA: sha, Author: git.Author{Name: author}, Timestamp: at(day)}
+		for _, path := range paths {
+			c.FilesChanged = append(c.FilesChanged, git.FileChange{Path: path})
+		}
+		return c
+	}
+
+	// Newest first; Alice changes api/b.go only after the scored commits
+	history := []git.Commit{
+		commit("c5", "Alice", 7, "api/b.go"),
+		commit("c4", "Alice", 6, "api/a.go", "api/b.go"),
+		commit("c3", "Alice", 5, "api/a.go", "vendor/x.go"),
+		commit("c2", "Bob", 4, "api/b.go"),
+		commit("c1", "Bob", 4, "api/a.go"),
+	}
+	baseline := RiskBaseline{Time: config.Default().Time}
+
+	results := AnalyzeRisk(history, map[string]bool{"c3": true, "c4": true}, baseline, []string{"vendor/*"})
+	if len(results.Commits) != 2 {
+		t.Fatalf("%d commits scored, want 2", len(results.Commits))
+	}
+
+	// Bob's earlier changes do not count for Alice; ignored files do not count at all
+	experience := map[string]float64{}
+	for _, r := range results.Commits {
+		experience[r.SHA] = riskFactor(t, r, RiskExperience)
+	}
+	if experience["c3"] != 1 || experience["c4"] != 0.5 {
+		t.Errorf("experience = %v, want c3 1 and c4 0.5", experience)
+	}
+	if results.Commits[0].SHA != "c3" {
+		t.Errorf("riskiest commit %s, want c3", results.Commits[0].SHA)
+	}
+}
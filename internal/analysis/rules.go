@@ -0,0 +1,70 @@
+package analysis
+
+import (
+	"fmt"
+
+	"histui/internal/config"
+)
+
+// RuleViolation represents a coupled file pair that breaks an architecture rule
+type RuleViolation struct {
+	Rule   config.ArchitectureRule
+	Pair   FilePair
+	Reason string
+}
+
+// EvaluateRules checks coupling results against architecture rules. Pairs
+// matched by an "allow" rule are exempt from all other rules. results should
+// hold every co-changing pair (MinCoChanges 1): a single co-change breaks a
+// "forbid" rule, while "limit" rules only judge pairs with at least
+// minCoChanges co-changes, as scores of rarer pairs are noise. Violations are
+// grouped by rule (in rule order) and sorted by coupling score within a rule.
+func EvaluateRules(results CouplingResults, rules []config.ArchitectureRule, minCoChanges int) []RuleViolation {
+	// Pairs are sorted by score, so checking rules in the outer loop keeps
+	// violations of each rule in descending score order
+	allowed := make([]bool, len(results.Pairs))
+	for i, pair := range results.Pairs {
+		for _, rule := range rules {
+			if rule.Policy == config.PolicyAllow && ruleMatches(rule, pair) {
+				allowed[i] = true
+				break
+			}
+		}
+	}
+
+	var violations []RuleViolation
+	for _, rule := range rules {
+		for i, pair := range results.Pairs {
+			if allowed[i] || !ruleMatches(rule, pair) {
+				continue
+			}
+
+			reason := ""
+			switch rule.Policy {
+			case config.PolicyForbid:
+				reason = fmt.Sprintf("forbidden coupling (%d co-changes)", pair.CoChanges)
+			case config.PolicyLimit:
+				if pair.CoChanges >= minCoChanges && pair.ScoreValue > rule.MaxScore {
+					reason = fmt.Sprintf("score %.2f exceeds maximum %.2f", pair.ScoreValue, rule.MaxScore)
+				}
+			}
+			if reason == "" {
+				continue
+			}
+
+			violations = append(violations, RuleViolation{
+				Rule:   rule,
+				Pair:   pair,
+				Reason: reason,
+			})
+		}
+	}
+
+	return violations
+}
+
+// ruleMatches reports whether a pair connects the rule's two sides, in either direction
+func ruleMatches(rule config.ArchitectureRule, pair FilePair) bool {
+	return (MatchPath(rule.From, pair.FileA) && MatchPath(rule.To, pair.FileB)) ||
+		(MatchPath(rule.From, pair.FileB) && MatchPath(rule.To, pair.FileA))
+}
@@ -0,0 +1,85 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"histui/internal/config"
+)
+
+func TestEvaluateRules(t *testing.T) {
+	// Sorted by score, as the coupling analysis returns them
+	results := CouplingResults{Pairs: []FilePair{
+		{FileA: "api/handlers.go", FileB: "api/middleware.go", CoChanges: 12, ScoreValue: 0.9},
+		{FileA: "internal/store/db.go", FileB: "cmd/server/main.go", CoChanges: 8, ScoreValue: 0.7},
+		{FileA: "db/migrations/002.sql", FileB: "api/users.go", CoChanges: 5, ScoreValue: 0.6},
+		{FileA: "internal/log/log.go", FileB: "cmd/tool/main.go", CoChanges: 2, ScoreValue: 0.8},
+		{FileA: "api/orders.go", FileB: "db/migrations/001.sql", CoChanges: 1, ScoreValue: 0.1},
+	}}
+
+	forbid := config.ArchitectureRule{From: "api/", To: "db/migrations/", Policy: config.PolicyForbid}
+	limit := config.ArchitectureRule{From: "internal/**", To: "cmd/**", Policy: config.PolicyLimit, MaxScore: 0.5}
+	allowHandlers := config.ArchitectureRule{From: "api/handlers.go", To: "api/middleware.go", Policy: config.PolicyAllow}
+	forbidAPI := config.ArchitectureRule{From: "api/", To: "api/", Policy: config.PolicyForbid}
+	allowUsers := config.ArchitectureRule{From: "api/users.go", To: "db/**", Policy: config.PolicyAllow}
+
+	type violation struct {
+		rule   string
+		fileA  string
+		reason string
+	}
+	tests := []struct {
+		name  string
+		rules []config.ArchitectureRule
+		want  []violation
+	}{
+		{
+			"no rules",
+			nil,
+			nil,
+		},
+		{
+			"forbid flags a single co-change, in either direction",
+			[]config.ArchitectureRule{forbid},
+			[]violation{
+				{forbid.Label(), "db/migrations/002.sql", "forbidden coupling (5 co-changes)"},
+				{forbid.Label(), "api/orders.go", "forbidden coupling (1 co-changes)"},
+			},
+		},
+		{
+			"limit skips weak scores and rare pairs",
+			[]config.ArchitectureRule{limit},
+			[]violation{
+				{limit.Label(), "internal/store/db.go", "score 0.70 exceeds maximum 0.50"},
+			},
+		},
+		{
+			"allow exempts pairs from every other rule",
+			[]config.ArchitectureRule{forbidAPI, allowHandlers, forbid, allowUsers},
+			[]violation{
+				{forbid.Label(), "api/orders.go", "forbidden coupling (1 co-changes)"},
+			},
+		},
+		{
+			"violations are grouped by rule in rule order",
+			[]config.ArchitectureRule{limit, forbid},
+			[]violation{
+				{limit.Label(), "internal/store/db.go", "score 0.70 exceeds maximum 0.50"},
+				{forbid.Label(), "db/migrations/002.sql", "forbidden coupling (5 co-changes)"},
+				{forbid.Label(), "api/orders.go", "forbidden coupling (1 co-changes)"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []violation
+			for _, v := range EvaluateRules(results, tt.rules, 3) {
+				got = append(got, violation{v.Rule.Label(), v.Pair.FileA, v.Reason})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EvaluateRules() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
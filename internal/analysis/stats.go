@@ -0,0 +1,138 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"histui/internal/git"
+)
+
+// RepositoryStats holds aggregate statistics over the analyzed commits
+type RepositoryStats struct {
+	Commits           int
+	FirstCommit       time.Time
+	LastCommit        time.Time
+	Authors           map[string]int
+	TopAuthors        []AuthorStat
+	TotalFilesChanged int
+	TotalInsertions   int
+	TotalDeletions    int
+	MergeCommits      int
+}
+
+// AuthorStat is the number of commits by one author
+type AuthorStat struct {
+	Name  string
+	Count int
+}
+
+// CalculateStats computes repository statistics from commit metadata
+func CalculateStats(commits []git.Commit) RepositoryStats {
+	stats := RepositoryStats{
+		Commits: len(commits),
+		Authors: make(map[string]int),
+	}
+
+	if len(commits) == 0 {
+		return stats
+	}
+
+	stats.FirstCommit = commits[len(commits)-1].Timestamp
+	stats.LastCommit = commits[0].Timestamp
+
+	for _, c := range commits {
+		stats.Authors[c.Author.Name]++
+		stats.TotalFilesChanged += c.Stats.FilesChanged
+		stats.TotalInsertions += c.Stats.Insertions
+		stats.TotalDeletions += c.Stats.Deletions
+		if c.IsMerge {
+			stats.MergeCommits++
+		}
+	}
+
+	stats.sortAuthors()
+	return stats
+}
+
+// MergeStats adds the statistics of newer commits to previous statistics
+func MergeStats(previous RepositoryStats, newCommits []git.Commit) RepositoryStats {
+	if previous.Commits == 0 {
+		return CalculateStats(newCommits)
+	}
+	if len(newCommits) == 0 {
+		return previous
+	}
+
+	update := CalculateStats(newCommits)
+	merged := previous
+	merged.Commits += update.Commits
+	merged.LastCommit = update.LastCommit
+	merged.TotalFilesChanged += update.TotalFilesChanged
+	merged.TotalInsertions += update.TotalInsertions
+	merged.TotalDeletions += update.TotalDeletions
+	merged.MergeCommits += update.MergeCommits
+
+	merged.Authors = make(map[string]int, len(previous.Authors))
+	for name, count := range previous.Authors {
+		merged.Authors[name] = count
+	}
+	for name, count := range update.Authors {
+		merged.Authors[name] += count
+	}
+	merged.sortAuthors()
+	return merged
+}
+
+// sortAuthors rebuilds TopAuthors from Authors
+func (stats *RepositoryStats) sortAuthors() {
+	stats.TopAuthors = stats.TopAuthors[:0]
+
+	// Create sorted author list
+	for name, count := range stats.Authors {
+		stats.TopAuthors = append(stats.TopAuthors, AuthorStat{
+			Name:  name,
+			Count: count,
+		})
+	}
+
+	// Sort by count (descending), then name for stable output
+	sort.Slice(stats.TopAuthors, func(i, j int) bool {
+		if stats.TopAuthors[i].Count != stats.TopAuthors[j].Count {
+			return stats.TopAuthors[i].Count > stats.TopAuthors[j].Count
+		}
+		return stats.TopAuthors[i].Name < stats.TopAuthors[j].Name
+	})
+}
+
+// Type implements AnalysisResult
+func (s *RepositoryStats) Type() string { return "stats" }
+
+// Summary implements AnalysisResult
+func (s *RepositoryStats) Summary() string {
+	return fmt.Sprintf("%d commits by %d contributors, +%d/-%d lines",
+		s.Commits, len(s.Authors), s.TotalInsertions, s.TotalDeletions)
+}
+
+// statsAnalyzer computes repository statistics
+type statsAnalyzer struct{}
+
+func init() {
+	Register("stats", func(Settings) Analyzer { return statsAnalyzer{} })
+}
+
+func (statsAnalyzer) Name() string              { return "stats" }
+func (statsAnalyzer) Version() string           { return "1" }
+func (statsAnalyzer) Needs() DataNeeds          { return DataNeeds{FileStats: true} }
+func (statsAnalyzer) Cacheable() bool           { return true }
+func (statsAnalyzer) NewResult() AnalysisResult { return &RepositoryStats{} }
+
+func (statsAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	stats := CalculateStats(commits)
+	return &stats, nil
+}
+
+func (statsAnalyzer) Update(previous AnalysisResult, commits []git.Commit) (AnalysisResult, error) {
+	stats := MergeStats(*previous.(*RepositoryStats), commits)
+	return &stats, nil
+}
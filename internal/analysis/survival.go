@@ -0,0 +1,410 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// survivalBucket is the width of the age buckets of survival curves
+const survivalBucket = 30 * 24 * time.Hour
+
+// LineOrigin is the commit and path a line was last changed in
+type LineOrigin struct {
+	SHA  string
+	Path string
+}
+
+// SurvivalSample counts the lines alive at a sampled revision by their origin
+type SurvivalSample struct {
+	SHA   string
+	Time  time.Time
+	Lines map[LineOrigin]int
+}
+
+// SurvivalPoint is the share of added lines still alive at an age
+type SurvivalPoint struct {
+	Months    int
+	Surviving float64
+}
+
+// SurvivalCurve describes how long the lines of an author, a directory or a
+// year cohort survive
+type SurvivalCurve struct {
+	Name     string
+	Added    int             // Lines added in the analyzed history
+	Alive    int             // Of these, lines alive at the newest sample
+	Points   []SurvivalPoint // By age, youngest first
+	HalfLife float64         // Months until half of the lines are gone (0 = not within the observed ages)
+}
+
+// MaxMonths returns the highest age observed, in months
+func (c SurvivalCurve) MaxMonths() int {
+	if len(c.Points) == 0 {
+		return 0
+	}
+	return c.Points[len(c.Points)-1].Months
+}
+
+// At returns the share of lines surviving to an age in months, and whether
+// lines were observed that old
+func (c SurvivalCurve) At(months int) (float64, bool) {
+	share, seen := 1.0, false
+	for _, p := range c.Points {
+		if p.Months > months {
+			break
+		}
+		share, seen = p.Surviving, p.Months == months
+	}
+	return share, seen || c.MaxMonths() > months
+}
+
+// CohortSample holds the lines alive at a sampled revision by the year they
+// were written in
+type CohortSample struct {
+	SHA   string
+	Time  time.Time
+	Lines []int // Per year of SurvivalResults.Years
+}
+
+// SurvivalResults holds line survival curves and the code stratigraphy
+type SurvivalResults struct {
+	Samples     []CohortSample // Oldest first
+	Years       []int          // Cohorts, oldest first; 0 holds lines from before the analyzed history
+	Overall     SurvivalCurve
+	Authors     []SurvivalCurve // Most lines added first
+	Directories []SurvivalCurve // By path
+	Cohorts     []SurvivalCurve // By year
+	Depth       int
+}
+
+// survivalGroup accumulates the lines added and surviving per age bucket
+type survivalGroup struct {
+	added     int
+	alive     int
+	surviving map[int]float64
+	exposed   map[int]float64 // Lines added by the commits observed at each age
+}
+
+func (g *survivalGroup) observe(bucket, added, surviving int) {
+	g.exposed[bucket] += float64(added)
+	g.surviving[bucket] += float64(surviving)
+}
+
+// curve turns the accumulated counts into a survival curve. Shares are capped
+// at 1 and never rise with age, as lines cannot come back. The half-life is
+// interpolated from the ages in the middle of the buckets, starting from all
+// lines alive at age 0, so that it is found in the first bucket too.
+func (g *survivalGroup) curve(name string) SurvivalCurve {
+	c := SurvivalCurve{Name: name, Added: g.added, Alive: g.alive}
+	buckets := make([]int, 0, len(g.exposed))
+	for bucket, n := range g.exposed {
+		if n > 0 {
+			buckets = append(buckets, bucket)
+		}
+	}
+	sort.Ints(buckets)
+
+	prevAge, prevShare := 0.0, 1.0
+	halfLifeFound := false
+	for _, bucket := range buckets {
+		share := g.surviving[bucket] / g.exposed[bucket]
+		if share > prevShare {
+			share = prevShare
+		}
+		c.Points = append(c.Points, SurvivalPoint{Months: bucket, Surviving: share})
+		age := float64(bucket) + 0.5
+		if !halfLifeFound && share <= 0.5 {
+			// The previous share is above half, so the line crosses it here
+			c.HalfLife = prevAge + (prevShare-0.5)/(prevShare-share)*(age-prevAge)
+			halfLifeFound = true
+		}
+		prevAge, prevShare = age, share
+	}
+	return c
+}
+
+// AnalyzeSurvival estimates how long added lines survive. commits must hold
+// the history of the samples, merges included, newest first; samples hold the
+// lines alive at revisions along it. At every sample, the lines still alive
+// from each earlier commit are compared with the lines it added, and the
+// shares are pooled per age (in months) into survival curves per author,
+// directory (depth levels deep) and year cohort. Authors and directories
+// with fewer than cfg.MinLines added lines get no curve.
+func AnalyzeSurvival(commits []git.Commit, samples []SurvivalSample, ignorePatterns []string, cfg config.SurvivalConfig, depth int) SurvivalResults {
+	if depth <= 0 {
+		depth = 1
+	}
+	results := SurvivalResults{Depth: depth}
+
+	bySHA := make(map[string]*git.Commit, len(commits))
+	added := make(map[string]map[string]int) // SHA -> directory -> lines added
+	for i := range commits {
+		c := &commits[i]
+		bySHA[c.SHA] = c
+		if c.IsMerge {
+			continue
+		}
+		for _, fc := range c.FilesChanged {
+			if fc.LinesAdded == 0 || shouldIgnoreFile(fc.Path, ignorePatterns) {
+				continue
+			}
+			if added[c.SHA] == nil {
+				added[c.SHA] = make(map[string]int)
+			}
+			added[c.SHA][rollUp(fc.Path, depth)] += fc.LinesAdded
+		}
+	}
+
+	groups := make(map[string]*survivalGroup)
+	group := func(key string) *survivalGroup {
+		if groups[key] == nil {
+			groups[key] = &survivalGroup{surviving: make(map[int]float64), exposed: make(map[int]float64)}
+		}
+		return groups[key]
+	}
+	cohort := func(c *git.Commit) string { return strconv.Itoa(c.Timestamp.Year()) }
+
+	for sha, dirs := range added {
+		c := bySHA[sha]
+		for dir, n := range dirs {
+			for _, key := range []string{"", "a:" + c.Author.Name, "d:" + dir, "y:" + cohort(c)} {
+				group(key).added += n
+			}
+		}
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Time.Before(samples[j].Time) })
+	years := make(map[int]bool)
+	var strata []map[int]int
+	for si, sample := range samples {
+		// Lines alive per commit and directory, and per year for the stratigraphy
+		alive := make(map[string]map[string]int)
+		stratum := make(map[int]int)
+		for origin, n := range sample.Lines {
+			year := 0
+			if c := bySHA[origin.SHA]; c != nil {
+				year = c.Timestamp.Year()
+			}
+			years[year] = true
+			stratum[year] += n
+			if added[origin.SHA] == nil || shouldIgnoreFile(origin.Path, ignorePatterns) {
+				continue
+			}
+			if alive[origin.SHA] == nil {
+				alive[origin.SHA] = make(map[string]int)
+			}
+			alive[origin.SHA][rollUp(origin.Path, depth)] += n
+		}
+		strata = append(strata, stratum)
+
+		latest := si == len(samples)-1
+		for sha := range ancestors(bySHA, sample.SHA) {
+			dirs := added[sha]
+			if dirs == nil {
+				continue
+			}
+			c := bySHA[sha]
+			bucket := int(max(sample.Time.Sub(c.Timestamp), 0) / survivalBucket)
+			for dir, n := range dirs {
+				// Blame can credit a commit with more lines than it added
+				surviving := min(alive[sha][dir], n)
+				for _, key := range []string{"", "a:" + c.Author.Name, "d:" + dir, "y:" + cohort(c)} {
+					g := group(key)
+					g.observe(bucket, n, surviving)
+					if latest {
+						g.alive += surviving
+					}
+				}
+			}
+		}
+	}
+
+	for year := range years {
+		results.Years = append(results.Years, year)
+	}
+	sort.Ints(results.Years)
+	for i, sample := range samples {
+		cs := CohortSample{SHA: sample.SHA, Time: sample.Time, Lines: make([]int, len(results.Years))}
+		for j, year := range results.Years {
+			cs.Lines[j] = strata[i][year]
+		}
+		results.Samples = append(results.Samples, cs)
+	}
+
+	results.Overall = group("").curve("all")
+	for key, g := range groups {
+		switch key[:min(2, len(key))] {
+		case "a:":
+			if g.added >= cfg.MinLines {
+				results.Authors = append(results.Authors, g.curve(key[2:]))
+			}
+		case "d:":
+			if g.added >= cfg.MinLines {
+				results.Directories = append(results.Directories, g.curve(key[2:]))
+			}
+		case "y:":
+			results.Cohorts = append(results.Cohorts, g.curve(key[2:]))
+		}
+	}
+	sort.Slice(results.Authors, func(i, j int) bool {
+		a, b := results.Authors[i], results.Authors[j]
+		if a.Added != b.Added {
+			return a.Added > b.Added
+		}
+		return a.Name < b.Name
+	})
+	sort.Slice(results.Directories, func(i, j int) bool { return results.Directories[i].Name < results.Directories[j].Name })
+	sort.Slice(results.Cohorts, func(i, j int) bool { return results.Cohorts[i].Name < results.Cohorts[j].Name })
+
+	return results
+}
+
+// ancestors returns the SHAs of sha and the commits reachable from it
+func ancestors(bySHA map[string]*git.Commit, sha string) map[string]bool {
+	seen := map[string]bool{sha: true}
+	queue := []string{sha}
+	for len(queue) > 0 {
+		c := bySHA[queue[0]]
+		queue = queue[1:]
+		if c == nil {
+			continue
+		}
+		for _, parent := range c.ParentSHAs {
+			if !seen[parent] {
+				seen[parent] = true
+				queue = append(queue, parent)
+			}
+		}
+	}
+	return seen
+}
+
+// sampleRevisions picks up to n commits along the first-parent history of
+// tip, evenly spread in time, oldest first; the tip is always included
+func sampleRevisions(bySHA map[string]*git.Commit, tip string, n int) []*git.Commit {
+	var chain []*git.Commit // Newest first
+	for c := bySHA[tip]; c != nil; {
+		chain = append(chain, c)
+		if len(c.ParentSHAs) == 0 {
+			break
+		}
+		c = bySHA[c.ParentSHAs[0]]
+	}
+	if len(chain) == 0 || n <= 0 {
+		return nil
+	}
+
+	first, last := chain[len(chain)-1].Timestamp, chain[0].Timestamp
+	var samples []*git.Commit
+	picked := make(map[string]bool)
+	for i := 1; i <= n; i++ {
+		target := first.Add(last.Sub(first) * time.Duration(i) / time.Duration(n))
+		// The newest commit of the chain at or before the target
+		for _, c := range chain {
+			if !c.Timestamp.After(target) {
+				if !picked[c.SHA] {
+					picked[c.SHA] = true
+					samples = append(samples, c)
+				}
+				break
+			}
+		}
+	}
+	return samples
+}
+
+// SurvivalAt blames every file at up to cfg.Samples revisions along the
+// first-parent history of rev and estimates line survival from them. commits
+// must include merges, which tell which commits each sample contains.
+func SurvivalAt(repo git.Repository, rev string, commits []git.Commit, ignorePatterns []string, cfg config.SurvivalConfig, depth int) (SurvivalResults, error) {
+	tip, err := repo.ResolveRevision(rev)
+	if err != nil {
+		return SurvivalResults{}, err
+	}
+
+	bySHA := make(map[string]*git.Commit, len(commits))
+	for i := range commits {
+		bySHA[commits[i].SHA] = &commits[i]
+	}
+	// Lines added on other branches never had a chance to survive in rev
+	reachable := ancestors(bySHA, tip)
+	history := make([]git.Commit, 0, len(reachable))
+	for _, c := range commits {
+		if reachable[c.SHA] {
+			history = append(history, c)
+		}
+	}
+
+	var samples []SurvivalSample
+	for _, c := range sampleRevisions(bySHA, tip, cfg.Samples) {
+		lineCounts, err := repo.GetLineCounts(c.SHA)
+		if err != nil {
+			return SurvivalResults{}, err
+		}
+		sample := SurvivalSample{SHA: c.SHA, Time: c.Timestamp, Lines: make(map[LineOrigin]int)}
+		for path := range lineCounts {
+			if shouldIgnoreFile(path, ignorePatterns) {
+				continue
+			}
+			lines, err := repo.BlameFile(c.SHA, path)
+			if err != nil {
+				return SurvivalResults{}, err
+			}
+			for _, line := range lines {
+				sample.Lines[LineOrigin{SHA: line.SHA, Path: line.Path}]++
+			}
+		}
+		samples = append(samples, sample)
+	}
+
+	return AnalyzeSurvival(history, samples, ignorePatterns, cfg, depth), nil
+}
+
+// Type implements AnalysisResult
+func (r *SurvivalResults) Type() string { return "survival" }
+
+// Summary implements AnalysisResult
+func (r *SurvivalResults) Summary() string {
+	if r.Overall.HalfLife > 0 {
+		return fmt.Sprintf("half of the added lines are gone after %.1f months (%d samples)", r.Overall.HalfLife, len(r.Samples))
+	}
+	return fmt.Sprintf("%d of %d added lines alive, half-life over %d months (%d samples)",
+		r.Overall.Alive, r.Overall.Added, r.Overall.MaxMonths(), len(r.Samples))
+}
+
+// survivalAnalyzer estimates how long lines survive
+type survivalAnalyzer struct {
+	repo           git.Repository
+	revision       string
+	ignorePatterns []string
+	cfg            config.SurvivalConfig
+	depth          int
+}
+
+func init() {
+	Register("survival", func(s Settings) Analyzer {
+		return survivalAnalyzer{repo: s.Repo, revision: s.Revision, ignorePatterns: s.IgnorePatterns, cfg: s.Config.Survival, depth: s.depthOr(1)}
+	})
+}
+
+func (survivalAnalyzer) Name() string              { return "survival" }
+func (survivalAnalyzer) Version() string           { return "2" }
+func (survivalAnalyzer) Needs() DataNeeds          { return DataNeeds{FileStats: true, Merges: true} }
+func (survivalAnalyzer) Cacheable() bool           { return true }
+func (survivalAnalyzer) NewResult() AnalysisResult { return &SurvivalResults{} }
+
+func (a survivalAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	if a.repo == nil {
+		return nil, fmt.Errorf("survival analysis needs repository access")
+	}
+	results, err := SurvivalAt(a.repo, revisionOrHead(a.revision), commits, a.ignorePatterns, a.cfg, a.depth)
+	if err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
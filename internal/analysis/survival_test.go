@@ -0,0 +1,164 @@
+package analysis
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+func TestSurvivalCurve(t *testing.T) {
+	tests := []struct {
+		name         string
+		exposed      map[int]float64
+		surviving    map[int]float64
+		wantShares   []float64
+		wantHalfLife float64
+	}{
+		{"never below half", map[int]float64{0: 10, 3: 10}, map[int]float64{0: 10, 3: 8}, []float64{1, 0.8}, 0},
+		{"below half in the first bucket", map[int]float64{0: 10, 1: 10}, map[int]float64{0: 4, 1: 3}, []float64{0.4, 0.3}, 0.25 / 0.6},
+		{"exactly half", map[int]float64{0: 10, 1: 10}, map[int]float64{0: 10, 1: 5}, []float64{1, 0.5}, 1.5},
+		{"interpolated between buckets", map[int]float64{0: 10, 2: 10, 4: 10}, map[int]float64{0: 9, 2: 6, 4: 4}, []float64{0.9, 0.6, 0.4}, 3.5},
+		{"shares never rise", map[int]float64{0: 10, 1: 10, 2: 10}, map[int]float64{0: 8, 1: 9, 2: 2}, []float64{0.8, 0.8, 0.2}, 1.5 + 0.3/0.6},
+		{"unobserved buckets skipped", map[int]float64{0: 10, 1: 0, 5: 10}, map[int]float64{0: 10, 5: 10}, []float64{1, 1}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &survivalGroup{exposed: tt.exposed, surviving: tt.surviving}
+			c := g.curve("x")
+			var shares []float64
+			for _, p := range c.Points {
+				shares = append(shares, p.Surviving)
+			}
+			if !reflect.DeepEqual(shares, tt.wantShares) {
+				t.Errorf("shares = %v, want %v", shares, tt.wantShares)
+			}
+			if math.Abs(c.HalfLife-tt.wantHalfLife) > 1e-9 {
+				t.Errorf("half-life = %v, want %v", c.HalfLife, tt.wantHalfLife)
+			}
+		})
+	}
+}
+
+// survivalCommit is a commit adding lines to one file
+func survivalCommit(sha, author string, when time.Time, path string, added int, parents ...string) git.Commit {
+	return git.Commit{
+		SHA:          sha,
+		Author:       git.Author{Name: author},
+		Timestamp:    when,
+		ParentSHAs:   parents,
+		FilesChanged: []git.FileChange{{Path: path, LinesAdded: added}},
+	}
+}
+
+func TestAnalyzeSurvival(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	// Newest first, as loaded from git
+	commits := []git.Commit{
+		survivalCommit("c3", "Alice", day(2024, 1, 1), "a/x.go", 4, "c2"),
+		survivalCommit("c2", "Bob", day(2023, 3, 1), "b/y.go", 10, "c1"),
+		survivalCommit("c1", "Alice", day(2023, 1, 1), "a/x.go", 10),
+	}
+	samples := []SurvivalSample{
+		{SHA: "c3", Time: day(2024, 1, 1), Lines: map[LineOrigin]int{
+			{SHA: "c1", Path: "a/x.go"}:      5,
+			{SHA: "c2", Path: "b/y.go"}:      10,
+			{SHA: "c3", Path: "a/x.go"}:      4,
+			{SHA: "unknown", Path: "a/x.go"}: 3, // From before the analyzed history
+		}},
+		{SHA: "c2", Time: day(2023, 3, 1), Lines: map[LineOrigin]int{
+			{SHA: "c1", Path: "a/x.go"}: 10,
+			{SHA: "c2", Path: "b/y.go"}: 10,
+		}},
+	}
+
+	results := AnalyzeSurvival(commits, samples, nil, config.SurvivalConfig{MinLines: 12}, 1)
+
+	if o := results.Overall; o.Added != 24 || o.Alive != 19 {
+		t.Errorf("overall added %d, alive %d, want 24, 19", o.Added, o.Alive)
+	}
+	// c1 is 12 months old at the newest sample with half its lines left; all
+	// younger lines survive
+	wantPoints := []SurvivalPoint{{0, 1}, {1, 1}, {10, 1}, {12, 0.5}}
+	if !reflect.DeepEqual(results.Overall.Points, wantPoints) {
+		t.Errorf("overall points = %v, want %v", results.Overall.Points, wantPoints)
+	}
+	if results.Overall.HalfLife != 12.5 {
+		t.Errorf("overall half-life = %v, want 12.5", results.Overall.HalfLife)
+	}
+
+	// Bob and directory b added fewer than MinLines lines
+	if len(results.Authors) != 1 || results.Authors[0].Name != "Alice" || results.Authors[0].Added != 14 {
+		t.Errorf("authors = %+v, want only Alice with 14 lines", results.Authors)
+	}
+	if len(results.Directories) != 1 || results.Directories[0].Name != "a/" {
+		t.Errorf("directories = %+v, want only a/", results.Directories)
+	}
+	var cohorts []string
+	for _, c := range results.Cohorts {
+		cohorts = append(cohorts, c.Name)
+	}
+	if !reflect.DeepEqual(cohorts, []string{"2023", "2024"}) {
+		t.Errorf("cohorts = %v, want 2023 and 2024", cohorts)
+	}
+
+	// The stratigraphy counts every line, oldest sample first
+	if !reflect.DeepEqual(results.Years, []int{0, 2023, 2024}) {
+		t.Errorf("years = %v, want [0 2023 2024]", results.Years)
+	}
+	wantStrata := [][]int{{0, 20, 0}, {3, 15, 4}}
+	for i, s := range results.Samples {
+		if !reflect.DeepEqual(s.Lines, wantStrata[i]) {
+			t.Errorf("sample %s lines = %v, want %v", s.SHA, s.Lines, wantStrata[i])
+		}
+	}
+}
+
+func TestSampleRevisions(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(days int) time.Time { return start.AddDate(0, 0, days) }
+	commits := []git.Commit{
+		{SHA: "c5", Timestamp: at(40), ParentSHAs: []string{"c4", "side"}},
+		{SHA: "side", Timestamp: at(35), ParentSHAs: []string{"c1"}},
+		{SHA: "c4", Timestamp: at(30), ParentSHAs: []string{"c3"}},
+		{SHA: "c3", Timestamp: at(20), ParentSHAs: []string{"c2"}},
+		{SHA: "c2", Timestamp: at(10), ParentSHAs: []string{"c1"}},
+		{SHA: "c1", Timestamp: at(0)},
+	}
+	bySHA := make(map[string]*git.Commit)
+	for i := range commits {
+		bySHA[commits[i].SHA] = &commits[i]
+	}
+
+	tests := []struct {
+		name string
+		tip  string
+		n    int
+		want []string
+	}{
+		{"one sample is the tip", "c5", 1, []string{"c5"}},
+		{"evenly spread", "c5", 2, []string{"c3", "c5"}},
+		{"more samples than commits", "c5", 10, []string{"c1", "c2", "c3", "c4", "c5"}},
+		{"older tip", "c3", 2, []string{"c2", "c3"}},
+		{"no samples", "c5", 0, nil},
+		{"unknown tip", "missing", 3, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range sampleRevisions(bySHA, tt.tip, tt.n) {
+				got = append(got, c.SHA)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sampleRevisions(%s, %d) = %v, want %v", tt.tip, tt.n, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// fixesIssuePattern matches issue references that mark a bug fix: "fixes #12", "Fixed PROJ-7"
+var fixesIssuePattern = regexp.MustCompile(`(?i)\bfix(?:es|ed)?:?\s+(?:#\d+|[A-Z][A-Z0-9]+-\d+\b)`)
+
+// BugFix is a bug-fixing commit and the commits that likely introduced the bug
+type BugFix struct {
+	SHA         string
+	ShortSHA    string
+	Author      string
+	Timestamp   time.Time
+	Subject     string
+	Introducers []string // SHAs of the commits that last changed the fixed lines
+}
+
+// DefectRate relates the commits of an author or an hour of the day to the
+// bug-introducing commits among them
+type DefectRate struct {
+	Name           string
+	Commits        int
+	BugIntroducing int
+	Rate           float64 // BugIntroducing / Commits
+}
+
+// FileDefects counts the bug-introducing commits of a file
+type FileDefects struct {
+	Path           string
+	Changes        int     // Commits that changed the file
+	BugIntroducing int     // Commits whose lines in the file were later fixed
+	Lines          int     // Line count at the analyzed revision
+	Density        float64 // Bug-introducing commits per 1000 lines
+}
+
+// SZZResults holds bug-fixing commits, the commits that introduced the bugs
+// and defect rates derived from them
+type SZZResults struct {
+	Commits        int      // Non-merge commits analyzed
+	BugFixes       int      // Commits recognized as bug fixes
+	Traced         []BugFix // Bug fixes traced back, newest first
+	BugIntroducing int      // Distinct commits that introduced a traced bug
+	Authors        []DefectRate
+	Hours          []DefectRate // One per hour of the day, in the commits' own timezones
+	Files          []FileDefects
+}
+
+// IsBugFix reports whether a commit fixes a bug, judged by a keyword in the
+// subject, a trailer such as "Fixes: ..." or "fixes #12" in the message.
+// Merges, reverts and fixup! commits are not bug fixes.
+func IsBugFix(commit git.Commit, cfg config.SZZConfig) bool {
+	if commit.IsMerge || IsExemptMessage(commit.Subject) {
+		return false
+	}
+	if hasKeyword(commit.Subject, cfg.Keywords) || fixesIssuePattern.MatchString(commit.Subject+"\n"+commit.Body) {
+		return true
+	}
+	for _, line := range strings.Split(commit.Body, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+		for _, trailer := range cfg.Trailers {
+			if strings.EqualFold(strings.TrimSpace(key), trailer) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FindBugIntroducers blames the lines a bug fix deleted or modified in the
+// version before it (the SZZ algorithm) and returns, per file, the commits
+// that last changed them
+func FindBugIntroducers(repo git.Repository, fix git.Commit, ignorePatterns []string) (map[string][]string, error) {
+	deleted, err := repo.DeletedLines(fix.SHA)
+	if err != nil {
+		return nil, err
+	}
+
+	introducers := make(map[string][]string)
+	for path, ranges := range deleted {
+		if shouldIgnoreFile(path, ignorePatterns) {
+			continue
+		}
+		shas, err := repo.BlameLines(fix.SHA+"^", path, ranges)
+		if err != nil {
+			return nil, err
+		}
+		for sha := range shas {
+			introducers[path] = append(introducers[path], sha)
+		}
+		sort.Strings(introducers[path])
+	}
+	return introducers, nil
+}
+
+// AnalyzeSZZ computes defect rates from traced bug fixes. introducers maps
+// the SHA of every traced fix to the result of FindBugIntroducers; commits
+// outside the analyzed history are left out. File paths are reported as they
+// are today, and densities use the line counts at the analyzed revision.
+func AnalyzeSZZ(commits []git.Commit, lineCounts map[string]int, introducers map[string]map[string][]string, ignorePatterns []string, cfg config.SZZConfig) SZZResults {
+	var results SZZResults
+
+	bySHA := make(map[string]git.Commit, len(commits))
+	authors := make(map[string]*DefectRate)
+	var hours [24]DefectRate
+	files := make(map[string]*FileDefects)
+	renames := make(renameTracker)
+
+	for _, commit := range commits {
+		bySHA[commit.SHA] = commit
+		for _, fc := range commit.FilesChanged {
+			path := renames.current(fc)
+			if lines, exists := lineCounts[path]; exists && !shouldIgnoreFile(path, ignorePatterns) {
+				if files[path] == nil {
+					files[path] = &FileDefects{Path: path, Lines: lines}
+				}
+				files[path].Changes++
+			}
+		}
+		if commit.IsMerge {
+			continue
+		}
+		results.Commits++
+		if IsBugFix(commit, cfg) {
+			results.BugFixes++
+		}
+		if authors[commit.Author.Name] == nil {
+			authors[commit.Author.Name] = &DefectRate{Name: commit.Author.Name}
+		}
+		authors[commit.Author.Name].Commits++
+		hours[commit.Timestamp.Hour()].Commits++
+	}
+
+	// Paths blamed in older versions, mapped to the path the file has today
+	currentPath := func(path string) string {
+		if current, ok := renames[path]; ok {
+			return current
+		}
+		return path
+	}
+
+	introducing := make(map[string]bool)
+	fileIntroducing := make(map[string]map[string]bool)
+	for _, commit := range commits {
+		byPath, traced := introducers[commit.SHA]
+		if !traced {
+			continue
+		}
+		fix := BugFix{
+			SHA:       commit.SHA,
+			ShortSHA:  commit.ShortSHA,
+			Author:    commit.Author.Name,
+			Timestamp: commit.Timestamp,
+			Subject:   commit.Subject,
+		}
+		for path, shas := range byPath {
+			path = currentPath(path)
+			for _, sha := range shas {
+				// Merges are not counted as commits, so lines blamed on a
+				// conflict resolution cannot make one bug-introducing
+				if introducer, known := bySHA[sha]; !known || introducer.IsMerge || sha == commit.SHA {
+					continue
+				}
+				if !contains(fix.Introducers, sha) {
+					fix.Introducers = append(fix.Introducers, sha)
+				}
+				introducing[sha] = true
+				if files[path] != nil {
+					if fileIntroducing[path] == nil {
+						fileIntroducing[path] = make(map[string]bool)
+					}
+					fileIntroducing[path][sha] = true
+				}
+			}
+		}
+		sort.Slice(fix.Introducers, func(i, j int) bool {
+			return bySHA[fix.Introducers[i]].Timestamp.After(bySHA[fix.Introducers[j]].Timestamp)
+		})
+		results.Traced = append(results.Traced, fix)
+	}
+
+	results.BugIntroducing = len(introducing)
+	for sha := range introducing {
+		commit := bySHA[sha]
+		authors[commit.Author.Name].BugIntroducing++
+		hours[commit.Timestamp.Hour()].BugIntroducing++
+	}
+
+	for _, a := range authors {
+		a.Rate = normalize(a.BugIntroducing, a.Commits)
+		results.Authors = append(results.Authors, *a)
+	}
+	sort.Slice(results.Authors, func(i, j int) bool {
+		a, b := results.Authors[i], results.Authors[j]
+		if a.BugIntroducing != b.BugIntroducing {
+			return a.BugIntroducing > b.BugIntroducing
+		}
+		return a.Name < b.Name
+	})
+
+	for hour := range hours {
+		h := &hours[hour]
+		h.Name = fmt.Sprintf("%02d", hour)
+		h.Rate = normalize(h.BugIntroducing, h.Commits)
+	}
+	results.Hours = hours[:]
+
+	for path, shas := range fileIntroducing {
+		f := files[path]
+		f.BugIntroducing = len(shas)
+		if f.Lines > 0 {
+			f.Density = float64(f.BugIntroducing) * 1000 / float64(f.Lines)
+		}
+		results.Files = append(results.Files, *f)
+	}
+	sort.Slice(results.Files, func(i, j int) bool {
+		a, b := results.Files[i], results.Files[j]
+		if a.BugIntroducing != b.BugIntroducing {
+			return a.BugIntroducing > b.BugIntroducing
+		}
+		if a.Density != b.Density {
+			return a.Density > b.Density
+		}
+		return a.Path < b.Path
+	})
+
+	return results
+}
+
+// Defects returns the bug-introducing commits per file, as hotspots take them
+func (r *SZZResults) Defects() map[string]int {
+	defects := make(map[string]int, len(r.Files))
+	for _, f := range r.Files {
+		defects[f.Path] = f.BugIntroducing
+	}
+	return defects
+}
+
+// SZZAt traces the most recent bug fixes among commits back to the commits
+// that introduced the bugs and computes defect rates for the files at rev
+func SZZAt(repo git.Repository, rev string, commits []git.Commit, ignorePatterns []string, cfg config.SZZConfig) (SZZResults, error) {
+	lineCounts, err := repo.GetLineCounts(rev)
+	if err != nil {
+		return SZZResults{}, err
+	}
+
+	introducers := make(map[string]map[string][]string)
+	for _, commit := range commits {
+		if cfg.MaxFixes > 0 && len(introducers) >= cfg.MaxFixes {
+			break
+		}
+		if !IsBugFix(commit, cfg) || (cfg.MaxFiles > 0 && len(commit.FilesChanged) > cfg.MaxFiles) {
+			continue
+		}
+		byPath, err := FindBugIntroducers(repo, commit, ignorePatterns)
+		if err != nil {
+			return SZZResults{}, err
+		}
+		introducers[commit.SHA] = byPath
+	}
+
+	return AnalyzeSZZ(commits, lineCounts, introducers, ignorePatterns, cfg), nil
+}
+
+// Type implements AnalysisResult
+func (r *SZZResults) Type() string { return "szz" }
+
+// Summary implements AnalysisResult
+func (r *SZZResults) Summary() string {
+	return fmt.Sprintf("%d bug fixes (%d traced), %d bug-introducing commits of %d",
+		r.BugFixes, len(r.Traced), r.BugIntroducing, r.Commits)
+}
+
+// szzAnalyzer identifies bug-introducing commits
+type szzAnalyzer struct {
+	repo           git.Repository
+	revision       string
+	ignorePatterns []string
+	cfg            config.SZZConfig
+}
+
+func init() {
+	Register("szz", func(s Settings) Analyzer {
+		return szzAnalyzer{repo: s.Repo, revision: s.Revision, ignorePatterns: s.IgnorePatterns, cfg: s.Config.SZZ}
+	})
+}
+
+func (szzAnalyzer) Name() string              { return "szz" }
+func (szzAnalyzer) Version() string           { return "1" }
+func (szzAnalyzer) Needs() DataNeeds          { return DataNeeds{FileStats: true} }
+func (szzAnalyzer) Cacheable() bool           { return true }
+func (szzAnalyzer) NewResult() AnalysisResult { return &SZZResults{} }
+
+func (a szzAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	if a.repo == nil {
+		return nil, fmt.Errorf("szz analysis needs repository access")
+	}
+	results, err := SZZAt(a.repo, revisionOrHead(a.revision), commits, a.ignorePatterns, a.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
@@ -0,0 +1,285 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// TestCoupling describes how often a source file changes together with its tests
+type TestCoupling struct {
+	Source        string
+	Tests         []string
+	SourceChanges int     // Commits that changed the source file
+	TestCoChanges int     // Of those, commits that also changed one of its tests
+	Ratio         float64 // TestCoChanges / SourceChanges
+}
+
+// TestCouplingResults holds the complete test-to-source coupling analysis
+type TestCouplingResults struct {
+	Files        []TestCoupling // Source files with tests, most changed first
+	Neglected    []TestCoupling // Frequently changed files whose tests rarely change with them
+	Untested     []TestCoupling // Frequently changed files without any test
+	OverallRatio float64        // Test co-change ratio across all paired source changes
+}
+
+// placeholderPattern matches {dir}, {name} and {path} in convention templates
+var placeholderPattern = regexp.MustCompile(`\{(dir|name|path)\}`)
+
+// placeholderRegex is the regular expression each placeholder expands to
+var placeholderRegex = map[string]string{
+	"dir":  `(?P<dir>(?:[^/]+/)*)`,
+	"name": `(?P<name>[^/]+)`,
+	"path": `(?P<path>[^/]+(?:/[^/]+)*)`,
+}
+
+// testConvention is a compiled config.TestConvention
+type testConvention struct {
+	source     string
+	sourceExpr *regexp.Regexp
+	testExpr   *regexp.Regexp
+}
+
+// AnalyzeTestCoupling pairs source files with their tests using the configured
+// conventions and measures how often they change together
+func AnalyzeTestCoupling(commits []git.Commit, cfg config.TestConfig) (TestCouplingResults, error) {
+	conventions, err := compileConventions(cfg.Conventions)
+	if err != nil {
+		return TestCouplingResults{}, err
+	}
+
+	// Count changes per file
+	fileChanges := make(map[string]int)
+	for _, commit := range commits {
+		for _, fc := range commit.FilesChanged {
+			fileChanges[fc.Path]++
+		}
+	}
+
+	// Classify every file seen in history as test or source
+	testsOf := make(map[string][]string)
+	isTest := make(map[string]bool)
+	for path := range fileChanges {
+		for _, conv := range conventions {
+			source, ok := conv.sourceFor(path)
+			if !ok {
+				continue
+			}
+			isTest[path] = true
+			if _, exists := fileChanges[source]; exists && !contains(testsOf[source], path) {
+				testsOf[source] = append(testsOf[source], path)
+			}
+		}
+	}
+
+	// Count how often each source changes together with one of its tests
+	coChanges := make(map[string]int)
+	for _, commit := range commits {
+		inCommit := make(map[string]bool, len(commit.FilesChanged))
+		for _, fc := range commit.FilesChanged {
+			inCommit[fc.Path] = true
+		}
+		for _, fc := range commit.FilesChanged {
+			for _, test := range testsOf[fc.Path] {
+				if inCommit[test] {
+					coChanges[fc.Path]++
+					break
+				}
+			}
+		}
+	}
+
+	var results TestCouplingResults
+	totalChanges, totalCoChanges := 0, 0
+	for path, changes := range fileChanges {
+		if isTest[path] {
+			continue
+		}
+
+		tests := testsOf[path]
+		if len(tests) == 0 {
+			if changes >= cfg.MinChanges && matchesAnySource(path, conventions) {
+				results.Untested = append(results.Untested, TestCoupling{Source: path, SourceChanges: changes})
+			}
+			continue
+		}
+
+		sort.Strings(tests)
+		tc := TestCoupling{
+			Source:        path,
+			Tests:         tests,
+			SourceChanges: changes,
+			TestCoChanges: coChanges[path],
+			Ratio:         float64(coChanges[path]) / float64(changes),
+		}
+		results.Files = append(results.Files, tc)
+		totalChanges += changes
+		totalCoChanges += tc.TestCoChanges
+
+		if changes >= cfg.MinChanges && tc.Ratio <= cfg.MaxRatio {
+			results.Neglected = append(results.Neglected, tc)
+		}
+	}
+
+	if totalChanges > 0 {
+		results.OverallRatio = float64(totalCoChanges) / float64(totalChanges)
+	}
+
+	sortByChanges(results.Files)
+	sortByChanges(results.Untested)
+	// Least tested first, most changed as tie breaker
+	sort.Slice(results.Neglected, func(i, j int) bool {
+		a, b := results.Neglected[i], results.Neglected[j]
+		if a.Ratio != b.Ratio {
+			return a.Ratio < b.Ratio
+		}
+		if a.SourceChanges != b.SourceChanges {
+			return a.SourceChanges > b.SourceChanges
+		}
+		return a.Source < b.Source
+	})
+
+	return results, nil
+}
+
+// compileConventions turns convention templates into regular expressions
+func compileConventions(conventions []config.TestConvention) ([]testConvention, error) {
+	compiled := make([]testConvention, 0, len(conventions))
+	for _, c := range conventions {
+		sourceExpr, err := compileTemplate(c.Source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source template %q: %w", c.Source, err)
+		}
+		testExpr, err := compileTemplate(c.Test)
+		if err != nil {
+			return nil, fmt.Errorf("invalid test template %q: %w", c.Test, err)
+		}
+		compiled = append(compiled, testConvention{
+			source:     c.Source,
+			sourceExpr: sourceExpr,
+			testExpr:   testExpr,
+		})
+	}
+	return compiled, nil
+}
+
+// compileTemplate converts a path template into an anchored regular expression
+func compileTemplate(template string) (*regexp.Regexp, error) {
+	if template == "" {
+		return nil, fmt.Errorf("template is empty")
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	seen := make(map[string]bool)
+	last := 0
+	for _, loc := range placeholderPattern.FindAllStringSubmatchIndex(template, -1) {
+		name := template[loc[2]:loc[3]]
+		if seen[name] {
+			return nil, fmt.Errorf("placeholder {%s} used more than once", name)
+		}
+		seen[name] = true
+		expr.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		expr.WriteString(placeholderRegex[name])
+		last = loc[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(template[last:]))
+	expr.WriteString("$")
+
+	return regexp.Compile(expr.String())
+}
+
+// sourceFor returns the source path a test file belongs to, if path is a test
+func (c testConvention) sourceFor(path string) (string, bool) {
+	match := c.testExpr.FindStringSubmatch(path)
+	if match == nil {
+		return "", false
+	}
+
+	values := make(map[string]string)
+	for i, name := range c.testExpr.SubexpNames() {
+		if name != "" {
+			values[name] = match[i]
+		}
+	}
+
+	// Derive placeholders the test template does not capture
+	if _, ok := values["path"]; !ok {
+		values["path"] = values["dir"] + values["name"]
+	}
+	if _, ok := values["name"]; !ok {
+		idx := strings.LastIndex(values["path"], "/")
+		values["dir"], values["name"] = values["path"][:idx+1], values["path"][idx+1:]
+	}
+
+	source := placeholderPattern.ReplaceAllStringFunc(c.source, func(p string) string {
+		return values[p[1:len(p)-1]]
+	})
+	return source, true
+}
+
+// matchesAnySource reports whether path looks like a source file under any convention
+func matchesAnySource(path string, conventions []testConvention) bool {
+	for _, c := range conventions {
+		if c.sourceExpr.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortByChanges sorts test couplings by source changes (descending), then path
+func sortByChanges(files []TestCoupling) {
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].SourceChanges != files[j].SourceChanges {
+			return files[i].SourceChanges > files[j].SourceChanges
+		}
+		return files[i].Source < files[j].Source
+	})
+}
+
+// contains reports whether list contains s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Type implements AnalysisResult
+func (r *TestCouplingResults) Type() string { return "tests" }
+
+// Summary implements AnalysisResult
+func (r *TestCouplingResults) Summary() string {
+	return fmt.Sprintf("%d files with tests (%.0f%% test co-change), %d neglected, %d untested",
+		len(r.Files), r.OverallRatio*100, len(r.Neglected), len(r.Untested))
+}
+
+// testCouplingAnalyzer measures how often source files change with their tests
+type testCouplingAnalyzer struct {
+	cfg config.TestConfig
+}
+
+func init() {
+	Register("tests", func(s Settings) Analyzer { return testCouplingAnalyzer{cfg: s.Config.Tests} })
+}
+
+func (testCouplingAnalyzer) Name() string              { return "tests" }
+func (testCouplingAnalyzer) Version() string           { return "1" }
+func (testCouplingAnalyzer) Needs() DataNeeds          { return DataNeeds{FileStats: true} }
+func (testCouplingAnalyzer) Cacheable() bool           { return true }
+func (testCouplingAnalyzer) NewResult() AnalysisResult { return &TestCouplingResults{} }
+
+func (a testCouplingAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	results, err := AnalyzeTestCoupling(commits, a.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
@@ -0,0 +1,245 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// Heatmap counts commits by day of week (Sunday = 0) and hour of day
+type Heatmap [7][24]int
+
+// Max returns the largest cell of the heatmap
+func (h *Heatmap) Max() int {
+	m := 0
+	for _, day := range h {
+		for _, n := range day {
+			m = max(m, n)
+		}
+	}
+	return m
+}
+
+// TimeProfile describes when one author, team or the whole repository commits
+type TimeProfile struct {
+	Name            string
+	Commits         int
+	Heatmap         Heatmap
+	Weekend         int     // Commits on Saturday or Sunday
+	OutOfHours      int     // Weekday commits outside working hours
+	WeekendRatio    float64 // Weekend / Commits
+	OutOfHoursRatio float64 // OutOfHours / Commits
+}
+
+// OffHoursRatio returns the share of commits made on weekends or out of hours
+func (p TimeProfile) OffHoursRatio() float64 {
+	return p.WeekendRatio + p.OutOfHoursRatio
+}
+
+// TimePeriod holds the off-hours ratios of one month
+type TimePeriod struct {
+	Month           time.Time
+	Commits         int
+	WeekendRatio    float64
+	OutOfHoursRatio float64
+}
+
+// BurnoutSignal is an author whose off-hours ratio rose recently
+type BurnoutSignal struct {
+	Author          string
+	RecentCommits   int
+	RecentRatio     float64 // Off-hours ratio in the recent period
+	PreviousRatio   float64 // Off-hours ratio in the year before it
+	PreviousCommits int
+}
+
+// TimePatternResults holds commit time patterns
+type TimePatternResults struct {
+	Overall   TimeProfile
+	Authors   []TimeProfile // Most commits first
+	Teams     []TimeProfile // Most commits first; commits of authors in no team are left out
+	Trend     []TimePeriod  // Monthly, oldest first
+	Burnout   []BurnoutSignal
+	WorkStart int
+	WorkEnd   int
+}
+
+// AnalyzeTimePatterns builds commit heatmaps and off-hours ratios. Hours and
+// days are taken in each commit's own timezone, so a commit at 23:00 in Tokyo
+// counts as late regardless of where the analysis runs.
+func AnalyzeTimePatterns(commits []git.Commit, cfg config.TimeConfig, ids *Identities, teamNames []string) TimePatternResults {
+	results := TimePatternResults{
+		Overall:   TimeProfile{Name: "all"},
+		WorkStart: cfg.WorkStart,
+		WorkEnd:   cfg.WorkEnd,
+	}
+	if len(commits) == 0 {
+		return results
+	}
+
+	authors := make(map[string]*TimeProfile)
+	teams := make(map[string]*TimeProfile)
+	months := make(map[time.Time]*TimeProfile)
+
+	// Recent and previous period per author, back from the newest commit
+	recentStart := commits[0].Timestamp.AddDate(0, -cfg.RecentMonths, 0)
+	previousStart := recentStart.AddDate(-1, 0, 0)
+	recent := make(map[string]*TimeProfile)
+	previous := make(map[string]*TimeProfile)
+
+	teamOf := make(map[string]string)
+	profile := func(profiles map[string]*TimeProfile, name string) *TimeProfile {
+		p, ok := profiles[name]
+		if !ok {
+			p = &TimeProfile{Name: name}
+			profiles[name] = p
+		}
+		return p
+	}
+
+	for _, commit := range commits {
+		local := commit.Timestamp // Keeps the author's UTC offset
+		weekday, hour := local.Weekday(), local.Hour()
+		weekend := weekday == time.Saturday || weekday == time.Sunday
+		outOfHours := !weekend && (hour < cfg.WorkStart || hour >= cfg.WorkEnd)
+
+		name := commit.Author.Name
+		targets := []*TimeProfile{&results.Overall, profile(authors, name)}
+
+		if _, resolved := teamOf[name]; !resolved && ids != nil {
+			teamOf[name], _ = ids.TeamOf(commit.Author, teamNames)
+		}
+		if team := teamOf[name]; team != "" {
+			targets = append(targets, profile(teams, team))
+		}
+
+		month := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if months[month] == nil {
+			months[month] = &TimeProfile{Name: month.Format("2006-01")}
+		}
+		targets = append(targets, months[month])
+
+		if !commit.Timestamp.Before(recentStart) {
+			targets = append(targets, profile(recent, name))
+		} else if !commit.Timestamp.Before(previousStart) {
+			targets = append(targets, profile(previous, name))
+		}
+
+		for _, p := range targets {
+			p.Commits++
+			p.Heatmap[weekday][hour]++
+			if weekend {
+				p.Weekend++
+			}
+			if outOfHours {
+				p.OutOfHours++
+			}
+		}
+	}
+
+	results.Overall.finish()
+	results.Authors = sortedProfiles(authors)
+	results.Teams = sortedProfiles(teams)
+
+	for month, p := range months {
+		p.finish()
+		results.Trend = append(results.Trend, TimePeriod{
+			Month:           month,
+			Commits:         p.Commits,
+			WeekendRatio:    p.WeekendRatio,
+			OutOfHoursRatio: p.OutOfHoursRatio,
+		})
+	}
+	sort.Slice(results.Trend, func(i, j int) bool { return results.Trend[i].Month.Before(results.Trend[j].Month) })
+
+	for name, r := range recent {
+		p, ok := previous[name]
+		if !ok || r.Commits < 5 || p.Commits < 5 {
+			continue
+		}
+		r.finish()
+		p.finish()
+		if r.OffHoursRatio()-p.OffHoursRatio() >= cfg.MinIncrease {
+			results.Burnout = append(results.Burnout, BurnoutSignal{
+				Author:          name,
+				RecentCommits:   r.Commits,
+				RecentRatio:     r.OffHoursRatio(),
+				PreviousRatio:   p.OffHoursRatio(),
+				PreviousCommits: p.Commits,
+			})
+		}
+	}
+	sort.Slice(results.Burnout, func(i, j int) bool {
+		a, b := results.Burnout[i], results.Burnout[j]
+		if da, db := a.RecentRatio-a.PreviousRatio, b.RecentRatio-b.PreviousRatio; da != db {
+			return da > db
+		}
+		return a.Author < b.Author
+	})
+
+	return results
+}
+
+// finish computes the ratios of a profile
+func (p *TimeProfile) finish() {
+	p.WeekendRatio = normalize(p.Weekend, p.Commits)
+	p.OutOfHoursRatio = normalize(p.OutOfHours, p.Commits)
+}
+
+// sortedProfiles finishes profiles and sorts them by commits (descending), then name
+func sortedProfiles(profiles map[string]*TimeProfile) []TimeProfile {
+	list := make([]TimeProfile, 0, len(profiles))
+	for _, p := range profiles {
+		p.finish()
+		list = append(list, *p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Commits != list[j].Commits {
+			return list[i].Commits > list[j].Commits
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Type implements AnalysisResult
+func (r *TimePatternResults) Type() string { return "timepattern" }
+
+// Summary implements AnalysisResult
+func (r *TimePatternResults) Summary() string {
+	return fmt.Sprintf("%.0f%% weekend, %.0f%% out of hours, %d burnout signals",
+		r.Overall.WeekendRatio*100, r.Overall.OutOfHoursRatio*100, len(r.Burnout))
+}
+
+// timePatternAnalyzer finds when people commit
+type timePatternAnalyzer struct {
+	cfg config.Config
+}
+
+func init() {
+	Register("timepattern", func(s Settings) Analyzer { return timePatternAnalyzer{cfg: s.Config} })
+}
+
+func (timePatternAnalyzer) Name() string              { return "timepattern" }
+func (timePatternAnalyzer) Version() string           { return "1" }
+func (timePatternAnalyzer) Needs() DataNeeds          { return DataNeeds{} }
+func (timePatternAnalyzer) Cacheable() bool           { return true }
+func (timePatternAnalyzer) NewResult() AnalysisResult { return &TimePatternResults{} }
+
+func (a timePatternAnalyzer) Analyze(commits []git.Commit) (AnalysisResult, error) {
+	results := AnalyzeTimePatterns(commits, a.cfg.Time, NewIdentities(a.cfg.Ownership.Aliases, a.cfg.Teams), TeamNames(a.cfg.Teams))
+	return &results, nil
+}
+
+// TeamNames returns the names of the configured teams in alphabetical order
+func TeamNames(teams map[string][]string) []string {
+	names := make([]string, 0, len(teams))
+	for name := range teams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
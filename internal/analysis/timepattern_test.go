@@ -0,0 +1,134 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+func TestAnalyzeTimePatterns(t *testing.T) {
+	commit := func(author string, when time.Time) git.Commit {
+		return git.Commit{Author: git.Author{Name: author}, Timestamp: when}
+	}
+	utc := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2024, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	tokyo := time.FixedZone("JST", 9*3600)
+
+	// Newest first; March 5 is a Tuesday, March 9 a Saturday
+	commits := []git.Commit{
+		commit("Alice", utc(3, 9, 12)),
+		commit("Alice", utc(3, 5, 10)),
+		commit("Alice", utc(3, 5, 10)),
+		commit("Alice", utc(3, 5, 8)),
+		// 14:00 UTC, but late in the author's own timezone
+		commit("Bob", time.Date(2024, 3, 5, 23, 0, 0, 0, tokyo)),
+		// The hour working time ends is out of hours
+		commit("Carol", utc(2, 1, 18)),
+	}
+	teams := map[string][]string{"core": {"Alice", "Bob"}}
+
+	results := AnalyzeTimePatterns(commits, config.Default().Time, NewIdentities(nil, teams), TeamNames(teams))
+
+	overall := results.Overall
+	if overall.Commits != 6 || overall.Weekend != 1 || overall.OutOfHours != 3 || overall.OutOfHoursRatio != 0.5 {
+		t.Errorf("overall = %+v, want 6 commits, 1 on a weekend, 3 out of hours", overall)
+	}
+	cells := []struct {
+		day  time.Weekday
+		hour int
+		want int
+	}{
+		{time.Tuesday, 10, 2},
+		{time.Tuesday, 8, 1},
+		{time.Tuesday, 23, 1},
+		{time.Tuesday, 14, 0},
+		{time.Saturday, 12, 1},
+		{time.Thursday, 18, 1},
+	}
+	for _, c := range cells {
+		if got := overall.Heatmap[c.day][c.hour]; got != c.want {
+			t.Errorf("heatmap[%s][%d] = %d, want %d", c.day, c.hour, got, c.want)
+		}
+	}
+	if m := overall.Heatmap.Max(); m != 2 {
+		t.Errorf("heatmap max = %d, want 2", m)
+	}
+
+	var authors []string
+	for _, p := range results.Authors {
+		authors = append(authors, p.Name)
+	}
+	// Ties go by name
+	if want := []string{"Alice", "Bob", "Carol"}; !reflect.DeepEqual(authors, want) {
+		t.Errorf("authors = %v, want %v", authors, want)
+	}
+	if a := results.Authors[0]; a.Commits != 4 || a.WeekendRatio != 0.25 || a.OutOfHoursRatio != 0.25 || a.OffHoursRatio() != 0.5 {
+		t.Errorf("Alice = %+v, want a quarter of 4 commits each on the weekend and out of hours", a)
+	}
+
+	// Carol is in no team
+	if len(results.Teams) != 1 || results.Teams[0].Name != "core" || results.Teams[0].Commits != 5 {
+		t.Errorf("teams = %+v, want core with 5 commits", results.Teams)
+	}
+
+	wantTrend := []TimePeriod{
+		{Month: utc(2, 1, 0), Commits: 1, OutOfHoursRatio: 1},
+		{Month: utc(3, 1, 0), Commits: 5, WeekendRatio: 0.2, OutOfHoursRatio: 0.4},
+	}
+	if !reflect.DeepEqual(results.Trend, wantTrend) {
+		t.Errorf("trend = %+v, want %+v", results.Trend, wantTrend)
+	}
+
+	if empty := AnalyzeTimePatterns(nil, config.Default().Time, nil, nil); empty.Overall.Commits != 0 || empty.Trend != nil {
+		t.Errorf("results of no commits = %+v", empty)
+	}
+}
+
+func TestAnalyzeTimePatternsBurnout(t *testing.T) {
+	// June 4, 2024 and June 6, 2023 are Tuesdays; the recent period starts
+	// three months before the newest commit, the previous one a year earlier
+	recentWeek := func(n, hour int) time.Time { return time.Date(2024, 6, 4-7*n, hour, 0, 0, 0, time.UTC) }
+	previousWeek := func(n, hour int) time.Time { return time.Date(2023, 6, 6-7*n, hour, 0, 0, 0, time.UTC) }
+
+	var commits []git.Commit
+	add := func(author string, recentLate, recentDay, previousLate, previousDay int, previous func(n, hour int) time.Time) {
+		for i := 0; i < recentLate+recentDay; i++ {
+			hour := 10
+			if i < recentLate {
+				hour = 22
+			}
+			commits = append(commits, git.Commit{Author: git.Author{Name: author}, Timestamp: recentWeek(i, hour)})
+		}
+		for i := 0; i < previousLate+previousDay; i++ {
+			hour := 10
+			if i < previousLate {
+				hour = 20
+			}
+			commits = append(commits, git.Commit{Author: git.Author{Name: author}, Timestamp: previous(i, hour)})
+		}
+	}
+	longAgo := func(n, hour int) time.Time { return previousWeek(n, hour).AddDate(-1, 0, 0) }
+
+	add("Alice", 5, 0, 0, 5, previousWeek)
+	add("Erin", 5, 0, 2, 3, previousWeek)
+	// Too few commits before
+	add("Bob", 5, 0, 0, 4, previousWeek)
+	// A rise below the minimum increase
+	add("Carol", 1, 9, 0, 5, previousWeek)
+	// No commits in the year before
+	add("Dave", 5, 0, 0, 5, longAgo)
+
+	results := AnalyzeTimePatterns(commits, config.Default().Time, nil, nil)
+
+	want := []BurnoutSignal{
+		{Author: "Alice", RecentCommits: 5, RecentRatio: 1, PreviousRatio: 0, PreviousCommits: 5},
+		{Author: "Erin", RecentCommits: 5, RecentRatio: 1, PreviousRatio: 0.4, PreviousCommits: 5},
+	}
+	if !reflect.DeepEqual(results.Burnout, want) {
+		t.Errorf("burnout = %+v, want %+v", results.Burnout, want)
+	}
+}
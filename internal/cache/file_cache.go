@@ -0,0 +1,297 @@
+package cache
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"histui/internal/git"
+)
+
+const (
+	metadataFile = "metadata.json"
+	commitsFile  = "commits.gob"
+	entryPrefix  = "entry-" // Followed by the load options fingerprint
+)
+
+// DefaultMaxAge is how long cache entries stay valid regardless of history
+const DefaultMaxAge = 7 * 24 * time.Hour
+
+// FileCache is a CacheManager storing one directory per load options
+// fingerprint, with one file per cached item in it
+type FileCache struct {
+	dir    string
+	maxAge time.Duration
+	mu     sync.Mutex
+}
+
+// NewFileCache creates a file cache in dir. Entries older than maxAge are
+// treated as invalid (0 = DefaultMaxAge).
+func NewFileCache(dir string, maxAge time.Duration) *FileCache {
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+	return &FileCache{dir: dir, maxAge: maxAge}
+}
+
+// Dir returns the directory the cache is stored in
+func (c *FileCache) Dir() string {
+	return c.dir
+}
+
+// IsValid reports whether cached commits exist for key and are not expired
+func (c *FileCache) IsValid(key Key) bool {
+	meta, err := c.Metadata(key)
+	if err != nil {
+		return false
+	}
+	return meta.LatestCommitSHA == key.LatestCommitSHA &&
+		meta.LoadOptions == key.LoadOptions &&
+		time.Since(meta.AnalysisTime) < c.maxAge
+}
+
+// Base returns the SHA cached commits were loaded up to if they were loaded
+// with the same options as key and are not expired. Ranges cannot be extended.
+func (c *FileCache) Base(key Key) (string, bool) {
+	meta, err := c.Metadata(key)
+	if err != nil {
+		return "", false
+	}
+	if meta.LoadOptions != key.LoadOptions || time.Since(meta.AnalysisTime) >= c.maxAge {
+		return "", false
+	}
+	if meta.LatestCommitSHA == "" || strings.Contains(meta.LatestCommitSHA, " ") {
+		return "", false
+	}
+	return meta.LatestCommitSHA, true
+}
+
+// LoadCommits reads the commits cached with the options of key
+func (c *FileCache) LoadCommits(key Key) ([]git.Commit, error) {
+	f, err := os.Open(filepath.Join(c.entryDir(key), commitsFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cached commits: %w", err)
+	}
+	defer f.Close()
+
+	var commits []git.Commit
+	if err := gob.NewDecoder(f).Decode(&commits); err != nil {
+		return nil, fmt.Errorf("failed to decode cached commits: %w", err)
+	}
+	return commits, nil
+}
+
+// SaveCommits replaces the entry for the options of key with commits loaded
+// for key. Expired entries of other options are removed on the way.
+func (c *FileCache) SaveCommits(key Key, commits []git.Commit) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Results of the previous history are meaningless for the new commits
+	dir := c.entryDir(key)
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to reset cache: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	c.removeExpired()
+
+	err := writeFile(filepath.Join(dir, commitsFile), func(f *os.File) error {
+		return gob.NewEncoder(f).Encode(commits)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save commits: %w", err)
+	}
+
+	return c.writeMetadata(key, CacheMetadata{
+		AnalysisTime:     time.Now(),
+		LatestCommitSHA:  key.LatestCommitSHA,
+		LoadOptions:      key.LoadOptions,
+		Options:          key.Options,
+		CommitCount:      len(commits),
+		AnalyzerVersions: make(map[string]AnalyzerEntry),
+	})
+}
+
+// LoadResult decodes an analyzer result cached with the commits of key into
+// result if it was produced by the same analyzer version and settings
+func (c *FileCache) LoadResult(key Key, name, version, settings string, result interface{}) (bool, error) {
+	meta, err := c.Metadata(key)
+	if err != nil {
+		return false, nil
+	}
+	entry, ok := meta.AnalyzerVersions[name]
+	if !ok || entry.Version != version || entry.Settings != settings {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(c.resultPath(key, name))
+	if err != nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return false, fmt.Errorf("failed to decode cached %s result: %w", name, err)
+	}
+	return true, nil
+}
+
+// SaveResult stores an analyzer result next to the commits of key
+func (c *FileCache) SaveResult(key Key, name, version, settings string, result interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	meta, err := c.Metadata(key)
+	if err != nil {
+		return fmt.Errorf("cannot cache %s result without cached commits: %w", name, err)
+	}
+
+	err = writeFile(c.resultPath(key, name), func(f *os.File) error {
+		return json.NewEncoder(f).Encode(result)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save %s result: %w", name, err)
+	}
+
+	meta.AnalyzerVersions[name] = AnalyzerEntry{Version: version, Settings: settings, SavedAt: time.Now()}
+	return c.writeMetadata(key, meta)
+}
+
+// Metadata reads the metadata of the entry for the options of key
+func (c *FileCache) Metadata(key Key) (CacheMetadata, error) {
+	return readMetadata(filepath.Join(c.entryDir(key), metadataFile))
+}
+
+// Entries returns the metadata of every cache entry, most recently built first
+func (c *FileCache) Entries() ([]CacheMetadata, error) {
+	dirs, err := os.ReadDir(c.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var entries []CacheMetadata
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		meta, err := readMetadata(filepath.Join(c.dir, d.Name(), metadataFile))
+		if err != nil {
+			continue // Being written, or not an entry
+		}
+		entries = append(entries, meta)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].AnalysisTime.After(entries[j].AnalysisTime) })
+	return entries, nil
+}
+
+// Invalidate removes the whole cache directory
+func (c *FileCache) Invalidate() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+// Size returns the total size in bytes of all cache files
+func (c *FileCache) Size() (int64, error) {
+	var total int64
+	err := filepath.Walk(c.dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			total += info.Size()
+		}
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	return total, err
+}
+
+// entryDir returns the directory of the entry for the options of key
+func (c *FileCache) entryDir(key Key) string {
+	return filepath.Join(c.dir, entryPrefix+key.LoadOptions)
+}
+
+// resultPath returns the file an analyzer's result is stored in
+func (c *FileCache) resultPath(key Key, name string) string {
+	return filepath.Join(c.entryDir(key), name+".json")
+}
+
+// removeExpired removes the entries that are too old to be used; c.mu must be held
+func (c *FileCache) removeExpired() {
+	dirs, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, d := range dirs {
+		path := filepath.Join(c.dir, d.Name())
+		if !strings.HasPrefix(d.Name(), entryPrefix) {
+			// Left over from a cache that kept a single entry
+			os.RemoveAll(path)
+			continue
+		}
+		meta, err := readMetadata(filepath.Join(path, metadataFile))
+		if err == nil && time.Since(meta.AnalysisTime) >= c.maxAge {
+			os.RemoveAll(path)
+		}
+	}
+}
+
+// readMetadata reads an entry's metadata file
+func readMetadata(path string) (CacheMetadata, error) {
+	var meta CacheMetadata
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return meta, fmt.Errorf("failed to read cache metadata: %w", err)
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return meta, fmt.Errorf("failed to parse cache metadata: %w", err)
+	}
+	if meta.AnalyzerVersions == nil {
+		meta.AnalyzerVersions = make(map[string]AnalyzerEntry)
+	}
+	return meta, nil
+}
+
+// writeMetadata writes the metadata file of the entry for the options of key
+func (c *FileCache) writeMetadata(key Key, meta CacheMetadata) error {
+	return writeFile(filepath.Join(c.entryDir(key), metadataFile), func(f *os.File) error {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		return enc.Encode(meta)
+	})
+}
+
+// writeFile writes a file atomically through a temporary file in the same directory
+func writeFile(path string, write func(f *os.File) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
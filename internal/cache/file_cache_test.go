@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"histui/internal/git"
+)
+
+func TestFileCacheCommits(t *testing.T) {
+	c := NewFileCache(filepath.Join(t.TempDir(), DirName), 0)
+	key := Key{LatestCommitSHA: "abc123", LoadOptions: "branch=main"}
+
+	if c.IsValid(key) {
+		t.Fatal("empty cache is valid")
+	}
+	if _, err := c.LoadCommits(key); err == nil {
+		t.Fatal("LoadCommits of an empty cache succeeded")
+	}
+
+	commits := []git.Commit{
+		{SHA: "abc123", Message: "Second", FilesChanged: []git.FileChange{{Path: "a.go", LinesAdded: 3}}},
+		{SHA: "def456", Message: "First"},
+	}
+	if err := c.SaveCommits(key, commits); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := c.LoadCommits(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, commits) {
+		t.Errorf("LoadCommits = %+v, want %+v", loaded, commits)
+	}
+
+	tests := []struct {
+		name     string
+		key      Key
+		wantOK   bool
+		wantBase bool
+	}{
+		{"same key", key, true, true},
+		{"newer commits", Key{LatestCommitSHA: "fff000", LoadOptions: key.LoadOptions}, false, true},
+		{"other options", Key{LatestCommitSHA: key.LatestCommitSHA, LoadOptions: "branch=dev"}, false, false},
+	}
+	for _, tt := range tests {
+		if got := c.IsValid(tt.key); got != tt.wantOK {
+			t.Errorf("%s: IsValid = %v, want %v", tt.name, got, tt.wantOK)
+		}
+		base, ok := c.Base(tt.key)
+		if ok != tt.wantBase || (ok && base != key.LatestCommitSHA) {
+			t.Errorf("%s: Base = %q, %v, want %q, %v", tt.name, base, ok, key.LatestCommitSHA, tt.wantBase)
+		}
+	}
+}
+
+func TestFileCacheExpiry(t *testing.T) {
+	c := NewFileCache(t.TempDir(), time.Nanosecond)
+	key := Key{LatestCommitSHA: "abc123"}
+	if err := c.SaveCommits(key, nil); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if c.IsValid(key) {
+		t.Error("expired cache is valid")
+	}
+	if _, ok := c.Base(key); ok {
+		t.Error("expired cache can be extended")
+	}
+}
+
+func TestFileCacheRangeHasNoBase(t *testing.T) {
+	c := NewFileCache(t.TempDir(), 0)
+	key := Key{LatestCommitSHA: "abc123 ^def456"}
+	if err := c.SaveCommits(key, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Base(key); ok {
+		t.Error("cached range can be extended")
+	}
+}
+
+func TestFileCacheResults(t *testing.T) {
+	type result struct {
+		Files []string
+		Score float64
+	}
+	c := NewFileCache(t.TempDir(), 0)
+	key := Key{LatestCommitSHA: "abc123"}
+	saved := result{Files: []string{"a.go", "b.go"}, Score: 0.75}
+
+	if err := c.SaveResult(key, "coupling", "2", "s1", saved); err == nil {
+		t.Fatal("SaveResult without cached commits succeeded")
+	}
+	if err := c.SaveCommits(key, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SaveResult(key, "coupling", "2", "s1", saved); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		analyzer string
+		version  string
+		settings string
+		want     bool
+	}{
+		{"same version and settings", "coupling", "2", "s1", true},
+		{"other version", "coupling", "1", "s1", false},
+		{"other settings", "coupling", "2", "s2", false},
+		{"other analyzer", "hotspots", "2", "s1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var loaded result
+			ok, err := c.LoadResult(key, tt.analyzer, tt.version, tt.settings, &loaded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.want {
+				t.Fatalf("LoadResult = %v, want %v", ok, tt.want)
+			}
+			if ok && !reflect.DeepEqual(loaded, saved) {
+				t.Errorf("loaded %+v, want %+v", loaded, saved)
+			}
+		})
+	}
+
+	// New commits drop the results of the previous history
+	key = Key{LatestCommitSHA: "fff000"}
+	if err := c.SaveCommits(key, nil); err != nil {
+		t.Fatal(err)
+	}
+	var loaded result
+	if ok, _ := c.LoadResult(key, "coupling", "2", "s1", &loaded); ok {
+		t.Error("result survived saving new commits")
+	}
+
+	if err := c.Invalidate(); err != nil {
+		t.Fatal(err)
+	}
+	if size, err := c.Size(); err != nil || size != 0 {
+		t.Errorf("Size after Invalidate = %d, %v, want 0, nil", size, err)
+	}
+}
+
+func TestFileCacheEntryPerOptions(t *testing.T) {
+	c := NewFileCache(t.TempDir(), 0)
+	plain := Key{LatestCommitSHA: "abc123", LoadOptions: "plain", Options: git.LoadOptions{Branch: "main"}}
+	merges := Key{LatestCommitSHA: "abc123", LoadOptions: "merges", Options: git.LoadOptions{Branch: "main", IncludeMerges: true}}
+
+	if err := c.SaveCommits(plain, []git.Commit{{SHA: "abc123"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SaveResult(plain, "stats", "1", "s", 42); err != nil {
+		t.Fatal(err)
+	}
+
+	// Loading with other options must not evict the first entry or its results
+	if err := c.SaveCommits(merges, []git.Commit{{SHA: "abc123"}, {SHA: "merge"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !c.IsValid(plain) || !c.IsValid(merges) {
+		t.Fatalf("IsValid = %v, %v, want both entries valid", c.IsValid(plain), c.IsValid(merges))
+	}
+	var n int
+	if ok, _ := c.LoadResult(plain, "stats", "1", "s", &n); !ok || n != 42 {
+		t.Errorf("result of the first entry = %d, %v, want 42, true", n, ok)
+	}
+	if ok, _ := c.LoadResult(merges, "stats", "1", "s", &n); ok {
+		t.Error("result shared between entries of different options")
+	}
+	if commits, err := c.LoadCommits(merges); err != nil || len(commits) != 2 {
+		t.Errorf("LoadCommits(merges) = %d commits, %v, want 2", len(commits), err)
+	}
+
+	entries, err := c.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].LoadOptions != "merges" || entries[1].Options.Branch != "main" {
+		t.Errorf("entries = %+v, want merges then plain", entries)
+	}
+}
+
+func TestFileCacheRemovesExpiredEntries(t *testing.T) {
+	c := NewFileCache(t.TempDir(), 50*time.Millisecond)
+	if err := c.SaveCommits(Key{LatestCommitSHA: "abc123", LoadOptions: "old"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if err := c.SaveCommits(Key{LatestCommitSHA: "abc123", LoadOptions: "new"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := c.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].LoadOptions != "new" {
+		t.Errorf("entries = %+v, want only the new one", entries)
+	}
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"path/filepath"
+	"time"
+
+	"histui/internal/git"
+)
+
+// DirName is the name of the cache directory inside the .git directory
+const DirName = "histui-cache"
+
+// Key identifies the history a cache entry was built from
+type Key struct {
+	LatestCommitSHA string          // Resolved SHAs of the analyzed revision
+	LoadOptions     string          // Fingerprint of the options commits were loaded with
+	Options         git.LoadOptions // The options themselves, to check the entry again later
+}
+
+// AnalyzerEntry records how a cached analyzer result was produced
+type AnalyzerEntry struct {
+	Version  string    `json:"version"`
+	Settings string    `json:"settings"` // Fingerprint of the analyzer settings
+	SavedAt  time.Time `json:"saved_at"`
+}
+
+// CacheMetadata describes the contents of one cache entry
+type CacheMetadata struct {
+	AnalysisTime     time.Time                `json:"analysis_time"`
+	LatestCommitSHA  string                   `json:"latest_commit_sha"`
+	LoadOptions      string                   `json:"load_options"`
+	Options          git.LoadOptions          `json:"options"`
+	CommitCount      int                      `json:"commit_count"`
+	AnalyzerVersions map[string]AnalyzerEntry `json:"analyzers"`
+}
+
+// CacheManager stores parsed commits and analyzer results between runs. It
+// keeps one entry per load options fingerprint, so that runs loading commits
+// differently do not evict each other.
+type CacheManager interface {
+	// Check if cached commits exist and were built for key
+	IsValid(key Key) bool
+
+	// SHA the cached commits were loaded up to, if they were loaded with the
+	// same options as key and can be extended with newer commits
+	Base(key Key) (string, bool)
+
+	// Load the commits cached with the options of key
+	LoadCommits(key Key) ([]git.Commit, error)
+
+	// Save commits loaded for key, dropping the analyzer results cached with them
+	SaveCommits(key Key, commits []git.Commit) error
+
+	// Load an analyzer result cached with the commits of key into result;
+	// false if absent or stale
+	LoadResult(key Key, name, version, settings string, result interface{}) (bool, error)
+
+	// Save an analyzer result next to the commits of key
+	SaveResult(key Key, name, version, settings string, result interface{}) error
+
+	// Metadata of the entry for the options of key
+	Metadata(key Key) (CacheMetadata, error)
+
+	// Clear cache
+	Invalidate() error
+}
+
+// Dir returns the cache directory for a repository's git directory
+func Dir(gitDir string) string {
+	return filepath.Join(gitDir, DirName)
+}
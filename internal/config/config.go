@@ -0,0 +1,391 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// FileName is the name of the per-repository configuration file
+const FileName = ".histui.json"
+
+// Config holds per-repository histui settings
+type Config struct {
+	Hooks     map[string]HookConfig `json:"hooks"`
+	Impact    ImpactConfig          `json:"impact"`
+	Messages  MessageRules          `json:"messages"`
+	Rules     []ArchitectureRule    `json:"rules"`
+	Tests     TestConfig            `json:"tests"`
+	Knowledge KnowledgeConfig       `json:"knowledge"`
+	Ownership OwnershipConfig       `json:"ownership"`
+	Time      TimeConfig            `json:"time"`
+	Firefight FirefightConfig       `json:"firefighting"`
+	SZZ       SZZConfig             `json:"szz"`
+	Classify  ClassifyConfig        `json:"classify"`
+	Network   NetworkConfig         `json:"network"`
+	Conway    ConwayConfig          `json:"conway"`
+	Experts   ExpertsConfig         `json:"experts"`
+	Age       AgeConfig             `json:"age"`
+	Survival  SurvivalConfig        `json:"survival"`
+	Churn     ChurnConfig           `json:"churn"`
+
+	// Teams maps team names (e.g. "@org/backend") to their members: author
+	// names, emails, @handles or other teams
+	Teams map[string][]string `json:"teams"`
+}
+
+// HookMode controls whether failed hook checks abort the git operation
+type HookMode string
+
+const (
+	HookModeWarn  HookMode = "warn"
+	HookModeBlock HookMode = "block"
+)
+
+// HookConfig configures the checks performed by a single git hook
+type HookConfig struct {
+	Mode   HookMode `json:"mode"`
+	Checks []string `json:"checks"`
+}
+
+// ValidateHooks reports the first hook with an unknown mode. An empty mode
+// means warn.
+func ValidateHooks(hooks map[string]HookConfig) error {
+	names := make([]string, 0, len(hooks))
+	for name := range hooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch mode := hooks[name].Mode; mode {
+		case "", HookModeWarn, HookModeBlock:
+		default:
+			return fmt.Errorf("%s hook: unknown mode %q (want %q or %q)", name, mode, HookModeWarn, HookModeBlock)
+		}
+	}
+	return nil
+}
+
+// ImpactConfig configures the missing coupled files check
+type ImpactConfig struct {
+	MinScore float64  `json:"min_score"`
+	Ignore   []string `json:"ignore"`
+}
+
+// MessageRules configures the commit message check
+type MessageRules struct {
+	MinSubjectLength  int      `json:"min_subject_length"`
+	MaxSubjectLength  int      `json:"max_subject_length"`
+	ForbiddenSubjects []string `json:"forbidden_subjects"`
+	RequireIssueRef   bool     `json:"require_issue_ref"`
+
+	// RequireConventional requires Conventional Commits subjects ("feat: ...")
+	RequireConventional bool `json:"require_conventional"`
+	// RequireImperative makes hooks check the imperative mood of subjects;
+	// message quality scores always take it into account
+	RequireImperative bool `json:"require_imperative"`
+	// LargeDiffLines is the diff size from which a message needs a body (0 = never)
+	LargeDiffLines int `json:"large_diff_lines"`
+}
+
+// RulePolicy is what an architecture rule does with the file pairs it matches
+type RulePolicy string
+
+const (
+	PolicyAllow  RulePolicy = "allow"  // Exempt matching pairs from all other rules
+	PolicyForbid RulePolicy = "forbid" // Any coupling between the sides is a violation
+	PolicyLimit  RulePolicy = "limit"  // Coupling above MaxScore is a violation
+)
+
+// ArchitectureRule constrains coupling between two sets of paths. From and To
+// are path patterns (see analysis.MatchPath); rules apply in both directions.
+type ArchitectureRule struct {
+	Name     string     `json:"name"`
+	From     string     `json:"from"`
+	To       string     `json:"to"`
+	Policy   RulePolicy `json:"policy"`
+	MaxScore float64    `json:"max_score"`
+}
+
+// Label returns the rule name, or a description derived from its patterns
+func (r ArchitectureRule) Label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("%s <-> %s (%s)", r.From, r.To, r.Policy)
+}
+
+// ValidateRules reports the first malformed rule
+func ValidateRules(rules []ArchitectureRule) error {
+	for i, r := range rules {
+		if r.From == "" || r.To == "" {
+			return fmt.Errorf("rule %d (%s): both from and to are required", i+1, r.Label())
+		}
+		switch r.Policy {
+		case PolicyAllow, PolicyForbid:
+		case PolicyLimit:
+			if r.MaxScore <= 0 || r.MaxScore > 1 {
+				return fmt.Errorf("rule %d (%s): max_score must be in (0, 1]", i+1, r.Label())
+			}
+		default:
+			return fmt.Errorf("rule %d (%s): unknown policy %q", i+1, r.Label(), r.Policy)
+		}
+	}
+	return nil
+}
+
+// TestConvention pairs source files with their tests using path templates.
+// Templates may contain {dir} (zero or more leading directories), {name} (a
+// single path segment) and {path} (one or more segments); a file matching the
+// test template is paired with the source path built from the same values.
+type TestConvention struct {
+	Source string `json:"source"`
+	Test   string `json:"test"`
+}
+
+// TestConfig configures test-to-source coupling analysis
+type TestConfig struct {
+	Conventions []TestConvention `json:"conventions"`
+	MinChanges  int              `json:"min_changes"` // Source changes before a file is judged
+	MaxRatio    float64          `json:"max_ratio"`   // Test co-change ratio at or below which a file is flagged
+}
+
+// KnowledgeConfig configures the bus factor and knowledge-loss analysis
+type KnowledgeConfig struct {
+	OwnerThreshold float64 `json:"owner_threshold"` // Share of the top author's share at which an author knows a file
+	OrphanMonths   int     `json:"orphan_months"`   // Inactivity after which an author's knowledge counts as lost
+	Blame          bool    `json:"blame"`           // Measure authorship with git blame instead of commit history
+}
+
+// OwnershipConfig configures the ownership report and CODEOWNERS validation
+type OwnershipConfig struct {
+	Months   int     `json:"months"`    // History that counts as recent, back from the newest commit
+	MinShare float64 `json:"min_share"` // Share of recent changes that makes an author a heavy contributor
+
+	// Aliases maps CODEOWNERS owners (@handles or emails) to the author names
+	// and emails they commit as
+	Aliases map[string][]string `json:"aliases"`
+}
+
+// TimeConfig configures the time pattern analysis. Hours are local to each commit.
+type TimeConfig struct {
+	WorkStart    int     `json:"work_start"`    // First working hour (0-23)
+	WorkEnd      int     `json:"work_end"`      // Hour working time ends (1-24)
+	RecentMonths int     `json:"recent_months"` // Period compared with the year before it
+	MinIncrease  float64 `json:"min_increase"`  // Rise of the off-hours ratio that signals overwork
+}
+
+// FirefightConfig configures the detection of reverts, hotfixes and quick follow-up fixes
+type FirefightConfig struct {
+	FollowUpMinutes int      `json:"follow_up_minutes"` // Time after an author's commit in which a fix counts as a follow-up
+	Keywords        []string `json:"keywords"`          // Subject words that mark a follow-up fix
+	HotfixBranches  []string `json:"hotfix_branches"`   // Branch name prefixes of hotfix branches
+}
+
+// SZZConfig configures the identification of bug-fixing and bug-introducing commits
+type SZZConfig struct {
+	Keywords []string `json:"keywords"`  // Subject words that mark a bug fix
+	Trailers []string `json:"trailers"`  // Message trailers that mark a bug fix ("Fixes: ...")
+	MaxFixes int      `json:"max_fixes"` // Most recent bug fixes traced back (0 = all)
+	MaxFiles int      `json:"max_files"` // Bug fixes changing more files are skipped as likely refactorings (0 = no limit)
+}
+
+// ClassifyConfig configures how commits are classified into kinds of work
+// (feature, fix, refactor, test, docs, chore). Path patterns use the syntax
+// of analysis.MatchPath.
+type ClassifyConfig struct {
+	Keywords map[string][]string `json:"keywords"` // Subject words per category
+	Tests    []string            `json:"tests"`    // Test files, besides those of the test conventions
+	Docs     []string            `json:"docs"`     // Documentation files
+	Chores   []string            `json:"chores"`   // Build, CI and dependency files
+}
+
+// NetworkConfig configures the developer collaboration network
+type NetworkConfig struct {
+	WindowDays int `json:"window_days"` // Days between two authors' changes to a file that make them collaborators
+	MinFiles   int `json:"min_files"`   // Shared files (or co-authored commits) needed for a connection
+	MaxFiles   int `json:"max_files"`   // Commits changing more files are skipped as bulk changes (0 = no limit)
+}
+
+// ConwayConfig configures the report on how coupling aligns with team boundaries
+type ConwayConfig struct {
+	MinScore       float64 `json:"min_score"`       // Coupling score from which a file pair counts as strongly coupled
+	MinShare       float64 `json:"min_share"`       // Share of a module's changes from which a team works on it
+	CollisionTeams int     `json:"collision_teams"` // Teams working on a module that make it a collision
+}
+
+// ExpertsConfig configures the expertise lookup. Experts count as inactive
+// after Knowledge.OrphanMonths without commits.
+type ExpertsConfig struct {
+	HalfLifeDays int `json:"half_life_days"` // Age at which a commit counts half
+	MaxBlame     int `json:"max_blame"`      // Files blamed at most, largest first (0 = all)
+}
+
+// AgeConfig sets the age bands of the code age map. Code older than
+// StableDays is frozen.
+type AgeConfig struct {
+	VolatileDays int `json:"volatile_days"` // Changed this recently, with mostly young lines: volatile
+	ActiveDays   int `json:"active_days"`   // Changed this recently: active
+	StableDays   int `json:"stable_days"`   // Changed this recently: stable
+	MaxBlame     int `json:"max_blame"`     // Files blamed at most, largest first (0 = all)
+}
+
+// SurvivalConfig configures the line survival analysis
+type SurvivalConfig struct {
+	Samples  int `json:"samples"`   // Revisions blamed, evenly spread over the history
+	MinLines int `json:"min_lines"` // Lines an author or directory must have added to get a survival curve
+}
+
+// ChurnConfig configures the churn time series
+type ChurnConfig struct {
+	Granularity string `json:"granularity"` // Period of the buckets: "day", "week", "month" or "quarter"
+	Timezone    string `json:"timezone"`    // IANA name or "local" to bucket in; empty = each commit's own timezone
+}
+
+// Default returns the configuration used when no config file exists
+func Default() Config {
+	return Config{
+		Hooks: map[string]HookConfig{
+			"pre-commit": {Mode: HookModeWarn, Checks: []string{"coupling"}},
+			"commit-msg": {Mode: HookModeWarn, Checks: []string{"message"}},
+			"pre-push":   {Mode: HookModeWarn, Checks: []string{"message"}},
+		},
+		Impact: ImpactConfig{
+			MinScore: 0.5,
+			Ignore:   []string{"*.md", "*.txt", "*.json", "*.yaml", "*.yml"},
+		},
+		Messages: MessageRules{
+			MinSubjectLength:  10,
+			MaxSubjectLength:  72,
+			ForbiddenSubjects: []string{"wip", "fix", "fixes", "update", "changes", "stuff", "misc", "."},
+			LargeDiffLines:    200,
+		},
+		Tests: TestConfig{
+			Conventions: []TestConvention{
+				{Source: "{dir}{name}.go", Test: "{dir}{name}_test.go"},
+				{Source: "{dir}{name}.ts", Test: "{dir}{name}.spec.ts"},
+				{Source: "{dir}{name}.ts", Test: "{dir}{name}.test.ts"},
+				{Source: "{dir}{name}.tsx", Test: "{dir}{name}.test.tsx"},
+				{Source: "{dir}{name}.js", Test: "{dir}{name}.spec.js"},
+				{Source: "{dir}{name}.js", Test: "{dir}{name}.test.js"},
+				{Source: "{dir}{name}.py", Test: "{dir}test_{name}.py"},
+				{Source: "src/{path}.py", Test: "tests/{dir}test_{name}.py"},
+				{Source: "src/{path}.ts", Test: "tests/{path}.test.ts"},
+				{Source: "src/main/java/{path}.java", Test: "src/test/java/{path}Test.java"},
+			},
+			MinChanges: 5,
+			MaxRatio:   0.2,
+		},
+		Knowledge: KnowledgeConfig{
+			OwnerThreshold: 0.75,
+			OrphanMonths:   6,
+		},
+		Ownership: OwnershipConfig{
+			Months:   12,
+			MinShare: 0.2,
+		},
+		Time: TimeConfig{
+			WorkStart:    9,
+			WorkEnd:      18,
+			RecentMonths: 3,
+			MinIncrease:  0.15,
+		},
+		Firefight: FirefightConfig{
+			FollowUpMinutes: 30,
+			Keywords:        []string{"oops", "typo", "forgot", "forgotten", "whoops", "missed", "missing", "accidentally"},
+			HotfixBranches:  []string{"hotfix", "hot-fix", "emergency"},
+		},
+		SZZ: SZZConfig{
+			Keywords: []string{"fix", "fixes", "fixed", "fixing", "bug", "bugfix", "bugs", "defect", "crash", "regression", "hotfix"},
+			Trailers: []string{"Fixes", "Bug", "Closes-Bug", "Resolves"},
+			MaxFixes: 200,
+			MaxFiles: 20,
+		},
+		Classify: ClassifyConfig{
+			Keywords: map[string][]string{
+				"refactor": {"refactor", "refactoring", "rename", "move", "extract", "cleanup", "simplify", "restructure", "reorganize", "tidy", "rework"},
+				"docs":     {"doc", "docs", "documentation", "readme", "changelog", "comment", "comments"},
+				"test":     {"test", "tests", "testing", "spec", "specs", "coverage"},
+				"chore":    {"bump", "upgrade", "release", "version", "deps", "dependency", "dependencies", "ci", "lint", "format", "formatting"},
+				"feature":  {"add", "implement", "introduce", "support", "new", "feature", "allow", "enable", "create"},
+			},
+			Tests:  []string{"**/test/", "**/tests/", "**/__tests__/", "*_test.*", "*.test.*", "*.spec.*", "test_*"},
+			Docs:   []string{"*.md", "*.rst", "*.adoc", "*.txt", "docs/", "doc/", "LICENSE*"},
+			Chores: []string{"go.mod", "go.sum", "package.json", "*.lock", "package-lock.json", "pnpm-lock.yaml", "Makefile", "Dockerfile", ".github/", ".gitlab-ci.yml", ".gitignore", ".editorconfig"},
+		},
+		Network: NetworkConfig{
+			WindowDays: 30,
+			MinFiles:   2,
+			MaxFiles:   50,
+		},
+		Conway: ConwayConfig{
+			MinScore:       0.5,
+			MinShare:       0.2,
+			CollisionTeams: 2,
+		},
+		Experts: ExpertsConfig{
+			HalfLifeDays: 180,
+			MaxBlame:     200,
+		},
+		Age: AgeConfig{
+			VolatileDays: 30,
+			ActiveDays:   180,
+			StableDays:   730,
+			MaxBlame:     500,
+		},
+		Survival: SurvivalConfig{
+			Samples:  12,
+			MinLines: 100,
+		},
+		Churn: ChurnConfig{
+			Granularity: "week",
+		},
+	}
+}
+
+// Load reads the config file from the repository root. Missing files yield
+// the defaults; values present in the file override the defaults.
+func Load(repoPath string) (Config, error) {
+	cfg, err := LoadFile(filepath.Join(repoPath, FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return Default(), nil
+	}
+	return cfg, err
+}
+
+// LoadFile reads a config file from an explicit path on top of the defaults.
+// Maps given in the file (hooks, classify.keywords) replace the default ones
+// rather than adding to them, so that defaults can be dropped.
+func LoadFile(path string) (Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	// Unmarshalling into a map keeps the entries it already has
+	var maps struct {
+		Hooks    json.RawMessage `json:"hooks"`
+		Classify struct {
+			Keywords json.RawMessage `json:"keywords"`
+		} `json:"classify"`
+	}
+	if err := json.Unmarshal(data, &maps); err != nil {
+		return cfg, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if maps.Hooks != nil {
+		cfg.Hooks = nil
+	}
+	if maps.Classify.Keywords != nil {
+		cfg.Classify.Keywords = nil
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
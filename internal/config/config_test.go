@@ -0,0 +1,129 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestValidateRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []ArchitectureRule
+		wantErr string
+	}{
+		{"no rules", nil, ""},
+		{
+			"every policy",
+			[]ArchitectureRule{
+				{From: "api/", To: "db/", Policy: PolicyForbid},
+				{From: "internal/**", To: "cmd/**", Policy: PolicyLimit, MaxScore: 0.5},
+				{From: "api/a.go", To: "db/b.go", Policy: PolicyAllow},
+				{From: "x/", To: "y/", Policy: PolicyLimit, MaxScore: 1},
+			},
+			"",
+		},
+		{"missing from", []ArchitectureRule{{To: "db/", Policy: PolicyForbid}}, "rule 1"},
+		{"missing to", []ArchitectureRule{{From: "api/", Policy: PolicyForbid}}, "both from and to are required"},
+		{"limit without score", []ArchitectureRule{{From: "a/", To: "b/", Policy: PolicyLimit}}, "max_score"},
+		{"limit above one", []ArchitectureRule{{From: "a/", To: "b/", Policy: PolicyLimit, MaxScore: 1.5}}, "max_score"},
+		{"unknown policy", []ArchitectureRule{{From: "a/", To: "b/", Policy: "deny"}}, `unknown policy "deny"`},
+		{
+			"first bad rule is reported",
+			[]ArchitectureRule{
+				{Name: "ok", From: "a/", To: "b/", Policy: PolicyForbid},
+				{Name: "broken", From: "a/", To: "b/"},
+			},
+			"rule 2 (broken)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRules(tt.rules)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("ValidateRules() = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("ValidateRules() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateHooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		hooks   map[string]HookConfig
+		wantErr string
+	}{
+		{"no hooks", nil, ""},
+		{"defaults", Default().Hooks, ""},
+		{"empty mode", map[string]HookConfig{"pre-commit": {Checks: []string{"coupling"}}}, ""},
+		{"block", map[string]HookConfig{"pre-push": {Mode: HookModeBlock}}, ""},
+		{"typo", map[string]HookConfig{"pre-commit": {Mode: "blok"}}, `pre-commit hook: unknown mode "blok"`},
+		{"case matters", map[string]HookConfig{"commit-msg": {Mode: "Warn"}}, `unknown mode "Warn"`},
+		{
+			"first hook by name is reported",
+			map[string]HookConfig{"pre-push": {Mode: "x"}, "commit-msg": {Mode: "y"}, "pre-commit": {Mode: HookModeWarn}},
+			"commit-msg hook",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHooks(tt.hooks)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("ValidateHooks() = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("ValidateHooks() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadFileReplacesMaps(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantHooks    []string
+		wantKeywords []string // Categories with keywords
+	}{
+		{"defaults kept", `{"impact": {"min_score": 0.7}}`, []string{"commit-msg", "pre-commit", "pre-push"}, []string{"chore", "docs", "feature", "refactor", "test"}},
+		{"hooks replaced", `{"hooks": {"pre-push": {"mode": "block", "checks": ["message"]}}}`, []string{"pre-push"}, []string{"chore", "docs", "feature", "refactor", "test"}},
+		{"no hooks", `{"hooks": {}}`, nil, []string{"chore", "docs", "feature", "refactor", "test"}},
+		{"keywords replaced", `{"classify": {"keywords": {"feature": ["add"]}, "docs": ["*.md"]}}`, []string{"commit-msg", "pre-commit", "pre-push"}, []string{"feature"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileName)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var hooks, keywords []string
+			for name := range cfg.Hooks {
+				hooks = append(hooks, name)
+			}
+			for category := range cfg.Classify.Keywords {
+				keywords = append(keywords, category)
+			}
+			sort.Strings(hooks)
+			sort.Strings(keywords)
+			if !reflect.DeepEqual(hooks, tt.wantHooks) {
+				t.Errorf("hooks = %v, want %v", hooks, tt.wantHooks)
+			}
+			if !reflect.DeepEqual(keywords, tt.wantKeywords) {
+				t.Errorf("keyword categories = %v, want %v", keywords, tt.wantKeywords)
+			}
+		})
+	}
+}
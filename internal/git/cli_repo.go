@@ -2,7 +2,10 @@ package git
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os/exec"
 	"path/filepath"
 	"strconv"
@@ -300,14 +303,15 @@ func (r *CLIRepository) LoadCommits(opts LoadOptions) ([]Commit, int, int, int,
 // 1. Creates a custom format string using commitDelimiter and fieldSeparator to structure output
 // 2. Format includes placeholders like %H (full SHA), %an (author name), %aI (ISO date), %s (subject), etc.
 // 3. Builds base arguments: "log", "--format=...", "--root", "--no-color", "--no-decorate"
-// 4. If IncludeFileStats is true, adds "--numstat" to get file change statistics
+// 4. Adds "--numstat" for file change statistics and "--summary" to tell added and deleted files apart
 // 5. Adds branch name or defaults to "HEAD"
 // 6. If IncludeMerges is false, adds "--no-merges" to skip merge commits
 // 7. If MaxCommits > 0, adds "--max-count=N" to limit results
 // 8. If Since is set, adds "--since=TIMESTAMP" for date filtering
 // 9. If Until is set, adds "--until=TIMESTAMP" for date filtering
 // 10. If Author is set, adds "--author=NAME" to filter by author
-// 11. Returns the complete argument slice
+// 11. If Exclude is set, adds "--not" followed by the excluded revisions
+// 12. Returns the complete argument slice
 //
 // Parameters:
 // - opts: LoadOptions struct with filtering criteria
@@ -318,7 +322,8 @@ func (r *CLIRepository) LoadCommits(opts LoadOptions) ([]Commit, int, int, int,
 // Example output:
 // ["log", "--format=---HISTUI_COMMIT_BOUNDARY------HISTUI_FIELD---%H---HISTUI_FIELD---...",
 //
-//	"--root", "--no-color", "--no-decorate", "--numstat", "main", "--no-merges", "--max-count=50"]
+//	"--root", "--no-color", "--no-decorate", "--numstat", "--summary", "--diff-merges=first-parent", "-M",
+//	"main", "--no-merges", "--max-count=50"]
 func (r *CLIRepository) buildLogArgs(opts LoadOptions) []string {
 	format := commitDelimiter + fieldSeparator +
 		"%H" + fieldSeparator +
@@ -340,7 +345,7 @@ func (r *CLIRepository) buildLogArgs(opts LoadOptions) []string {
 		"--no-decorate",
 	}
 
-	args = append(args, "--numstat", "--diff-merges=first-parent", "-M")
+	args = append(args, "--numstat", "--summary", "--diff-merges=first-parent", "-M")
 
 	if opts.Branch != "" {
 		args = append(args, opts.Branch)
@@ -368,6 +373,11 @@ func (r *CLIRepository) buildLogArgs(opts LoadOptions) []string {
 		args = append(args, fmt.Sprintf("--author=%s", opts.Author))
 	}
 
+	if len(opts.Exclude) > 0 {
+		args = append(args, "--not")
+		args = append(args, opts.Exclude...)
+	}
+
 	return args
 }
 
@@ -434,7 +444,8 @@ func (r *CLIRepository) parseLogOutput(output string, includeFileStats bool) []C
 //
 // 4. Parses the timestamp string into a time.Time object
 // 5. Splits parent SHAs to determine if it's a merge commit (>1 parent)
-// 6. If file stats are included, parses numstat lines using parseNumstatLine()
+// 6. If file stats are included, parses numstat lines using parseNumstatLine() and
+// marks files listed as created or deleted by the summary lines (parseSummaryLine())
 // 7. Calculates aggregate stats (files changed, total insertions, total deletions)
 // 8. Constructs the full message by combining subject and body
 // 9. Returns a pointer to the populated Commit struct
@@ -503,12 +514,17 @@ func (r *CLIRepository) parseCommitBlock(block string, includeFileStats bool) *C
 	stats := CommitStats{}
 
 	if numstatText != "" {
+		created := make(map[string]ChangeType)
 		scanner := bufio.NewScanner(strings.NewReader(numstatText))
 		for scanner.Scan() {
 			line := scanner.Text()
 			if line == "" {
 				continue
 			}
+			if path, changeType, ok := parseSummaryLine(line); ok {
+				created[path] = changeType
+				continue
+			}
 			fc := parseNumstatLine(line)
 			if fc != nil {
 				fileChanges = append(fileChanges, *fc)
@@ -517,6 +533,11 @@ func (r *CLIRepository) parseCommitBlock(block string, includeFileStats bool) *C
 				stats.Deletions += fc.LinesDeleted
 			}
 		}
+		for i := range fileChanges {
+			if changeType, ok := created[fileChanges[i].Path]; ok && fileChanges[i].ChangeType == ChangeTypeModified {
+				fileChanges[i].ChangeType = changeType
+			}
+		}
 	}
 
 	msg := subject
@@ -655,3 +676,627 @@ func parseNumstatLine(line string) *FileChange {
 		OldPath:      oldPath,
 	}
 }
+
+// parseSummaryLine parses a "--summary" line reporting a created or deleted file.
+//
+// How it works:
+// 1. Takes a line formatted as " create mode MODE path" or " delete mode MODE path"
+// 2. Drops the file mode and returns the path with ChangeTypeAdded or ChangeTypeDeleted
+// 3. Other summary lines (mode changes, renames) are not reported
+//
+// Parameters:
+// - line: a single line from the numstat section of git log --numstat --summary
+//
+// Returns:
+// - string: path of the created or deleted file
+// - ChangeType: ChangeTypeAdded or ChangeTypeDeleted
+// - bool: whether the line reports a created or deleted file
+//
+// Example input/output:
+// Input: " create mode 100644 src/auth.go"
+// Output: "src/auth.go", ChangeTypeAdded, true
+func parseSummaryLine(line string) (string, ChangeType, bool) {
+	line = strings.TrimSpace(line)
+	changeType := ChangeTypeAdded
+	rest, ok := strings.CutPrefix(line, "create mode ")
+	if !ok {
+		changeType = ChangeTypeDeleted
+		if rest, ok = strings.CutPrefix(line, "delete mode "); !ok {
+			return "", 0, false
+		}
+	}
+	_, path, ok := strings.Cut(rest, " ")
+	if !ok || path == "" {
+		return "", 0, false
+	}
+	return path, changeType, true
+}
+
+// GetStagedFiles returns the paths of all files currently staged in the index.
+//
+// How it works:
+// 1. Executes 'git diff --cached --name-only' which lists staged paths relative to the repo root
+// 2. Splits the output by newlines
+// 3. Trims whitespace from each line and filters out empty lines
+//
+// Returns:
+// - []string: slice of staged file paths (empty if nothing is staged)
+// - error: if the git command fails
+//
+// Example output:
+// Success: []string{"internal/auth/login.go", "internal/db/sessions.go"}
+// Error: "failed to list staged files: exit status 128"
+func (r *CLIRepository) GetStagedFiles() ([]string, error) {
+	out, err := r.git("diff", "--cached", "--name-only").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staged files: %w", err)
+	}
+	return splitLines(string(out)), nil
+}
+
+// GetUserIdent returns the name and email git records as the author of new commits.
+//
+// How it works:
+// 1. Executes 'git var GIT_AUTHOR_IDENT', which honours user.name, user.email
+// and the GIT_AUTHOR_NAME/GIT_AUTHOR_EMAIL environment variables
+// 2. Parses the "Name <email> timestamp timezone" output
+//
+// Returns:
+// - Author: the name and email of the current user
+// - error: if no identity is configured or the output cannot be parsed
+//
+// Example output:
+// Success: Author{Name: "Jane Doe", Email: "jane@example.com"}
+// Error: "failed to get user identity: exit status 128"
+func (r *CLIRepository) GetUserIdent() (Author, error) {
+	out, err := r.git("var", "GIT_AUTHOR_IDENT").Output()
+	if err != nil {
+		return Author{}, fmt.Errorf("failed to get user identity: %w", err)
+	}
+	ident := strings.TrimSpace(string(out))
+	open, end := strings.LastIndex(ident, "<"), strings.LastIndex(ident, ">")
+	if open < 0 || end < open {
+		return Author{}, fmt.Errorf("failed to parse user identity %q", ident)
+	}
+	return Author{Name: strings.TrimSpace(ident[:open]), Email: ident[open+1 : end]}, nil
+}
+
+// GetHooksDir returns the absolute path of the directory git executes hooks from.
+//
+// How it works:
+// 1. Executes 'git rev-parse --git-path hooks', which honours core.hooksPath
+// 2. Trims whitespace from the result
+// 3. Resolves relative results against the repository path
+//
+// Returns:
+// - string: absolute path to the hooks directory
+// - error: if the git command fails
+//
+// Example output:
+// Success: "/home/user/project/.git/hooks"
+// Success: "/home/user/project/.githooks" (with core.hooksPath=.githooks)
+// Error: "failed to resolve hooks directory: exit status 128"
+func (r *CLIRepository) GetHooksDir() (string, error) {
+	out, err := r.git("rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve hooks directory: %w", err)
+	}
+	dir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(r.path, dir)
+	}
+	return dir, nil
+}
+
+// GetGitDir returns the absolute path of the repository's git directory.
+//
+// How it works:
+// 1. Executes 'git rev-parse --absolute-git-dir'
+// 2. Trims whitespace from the result
+//
+// Returns:
+// - string: absolute path to the git directory
+// - error: if the git command fails
+//
+// Example output:
+// Success: "/home/user/project/.git"
+// Error: "failed to resolve git directory: exit status 128"
+func (r *CLIRepository) GetGitDir() (string, error) {
+	out, err := r.git("rev-parse", "--absolute-git-dir").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve git directory: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ResolveRevision resolves a revision, branch name or range to commit SHAs.
+//
+// How it works:
+// 1. Executes 'git rev-parse REV', which prints one SHA per line (ranges like "a..b" print "b" and "^a")
+// 2. Joins the resulting lines with spaces
+//
+// Parameters:
+// - rev: revision, branch name or range to resolve
+//
+// Returns:
+// - string: space-separated SHAs identifying the revision
+// - error: if the revision cannot be resolved
+//
+// Example output:
+// Success: "a1b2c3d4e5f6..." (for "main")
+// Success: "a1b2c3d4e5f6... ^f6e5d4c3b2a1..." (for "v1.0..main")
+// Error: "failed to resolve revision main: exit status 128"
+func (r *CLIRepository) ResolveRevision(rev string) (string, error) {
+	out, err := r.git("rev-parse", rev).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve revision %s: %w", rev, err)
+	}
+	return strings.Join(splitLines(string(out)), " "), nil
+}
+
+// GetLineCounts counts the lines of every text file in the tree of a revision.
+//
+// How it works:
+// 1. Executes 'git grep -I -c -e "" REV --', which counts the lines matching the
+// empty pattern (all of them) in each file of the revision's tree
+// 2. Skips binary files (-I); empty files have no matching lines and are not listed
+// 3. Parses each "REV:path:count" output line
+// 4. An exit status of 1 (no matching lines at all) yields an empty map
+//
+// Parameters:
+// - rev: revision whose tree is counted (e.g. "HEAD")
+//
+// Returns:
+// - map[string]int: line count per file path
+// - error: if the revision does not exist or output cannot be parsed
+//
+// Example output:
+// Success: map[string]int{"main.go": 120, "internal/git/cli_repo.go": 640}
+// Error: "failed to count lines at HEAD: exit status 128"
+func (r *CLIRepository) GetLineCounts(rev string) (map[string]int, error) {
+	out, err := r.git("grep", "-I", "-c", "-e", "", rev, "--").Output()
+	counts := make(map[string]int)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return counts, nil
+		}
+		return nil, fmt.Errorf("failed to count lines at %s: %w", rev, err)
+	}
+
+	prefix := rev + ":"
+	for _, line := range splitLines(string(out)) {
+		idx := strings.LastIndex(line, ":")
+		if !strings.HasPrefix(line, prefix) || idx < len(prefix) {
+			return nil, fmt.Errorf("unexpected git grep output: %q", line)
+		}
+		n, err := strconv.Atoi(line[idx+1:])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected git grep output: %q", line)
+		}
+		counts[line[len(prefix):idx]] = n
+	}
+	return counts, nil
+}
+
+// BlameAuthors counts the lines of a file attributed to each author by git blame.
+//
+// How it works:
+// 1. Executes 'git blame --line-porcelain REV -- PATH', which prints a header
+// block for every line of the file, including an "author NAME" line
+// 2. Counts the "author " lines per author name
+//
+// Parameters:
+// - rev: revision whose version of the file is blamed
+// - path: file path relative to the repository root
+//
+// Returns:
+// - map[string]int: number of lines per author name
+// - error: if the file does not exist at the revision
+//
+// Example output:
+// Success: map[string]int{"Jane Doe": 310, "John Smith": 42}
+// Error: "failed to blame main.go at HEAD: exit status 128"
+func (r *CLIRepository) BlameAuthors(rev, path string) (map[string]int, error) {
+	out, err := r.git("blame", "--line-porcelain", rev, "--", path).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame %s at %s: %w", path, rev, err)
+	}
+
+	authors := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if name, ok := strings.CutPrefix(scanner.Text(), "author "); ok {
+			authors[name]++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse blame of %s: %w", path, err)
+	}
+	return authors, nil
+}
+
+// ReadFiles reads the contents of files at given revisions in one git process.
+//
+// How it works:
+// 1. Starts 'git cat-file --batch' and writes one "REV:PATH" line per file to its stdin
+// 2. For every request, git prints a header "<sha> <type> <size>" followed by
+// the object contents and a newline, or "<spec> missing" if it does not exist
+// 3. Reads exactly <size> bytes for each found blob
+//
+// Parameters:
+// - refs: revision and path of each file to read
+//
+// Returns:
+// - [][]byte: contents in the order of refs; nil where the file does not exist at that revision
+// - error: if git fails or its output cannot be parsed
+//
+// Example output:
+// Success: [][]byte{[]byte("package main\n..."), nil} (for main.go@HEAD, deleted.go@HEAD)
+// Error: "failed to read files: exit status 128"
+func (r *CLIRepository) ReadFiles(refs []FileRef) ([][]byte, error) {
+	cmd := r.git("cat-file", "--batch")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read files: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read files: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to read files: %w", err)
+	}
+
+	// Feed requests concurrently so that neither side blocks on a full pipe
+	go func() {
+		w := bufio.NewWriter(stdin)
+		for _, ref := range refs {
+			fmt.Fprintf(w, "%s:%s\n", ref.Rev, ref.Path)
+		}
+		w.Flush()
+		stdin.Close()
+	}()
+
+	contents := make([][]byte, len(refs))
+	reader := bufio.NewReader(stdout)
+	for i := range refs {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			cmd.Wait()
+			return nil, fmt.Errorf("failed to read files: %w", err)
+		}
+		header = strings.TrimSuffix(header, "\n")
+		if strings.HasSuffix(header, " missing") || strings.HasSuffix(header, " ambiguous") {
+			continue
+		}
+
+		fields := strings.Fields(header)
+		size := -1
+		if len(fields) == 3 {
+			size, err = strconv.Atoi(fields[2])
+		}
+		if size < 0 || err != nil {
+			cmd.Wait()
+			return nil, fmt.Errorf("unexpected git cat-file output: %q", header)
+		}
+
+		content := make([]byte, size+1) // Contents are followed by a newline
+		if _, err := io.ReadFull(reader, content); err != nil {
+			cmd.Wait()
+			return nil, fmt.Errorf("failed to read files: %w", err)
+		}
+		// Directories and other objects are not files
+		if fields[1] == "blob" {
+			contents[i] = content[:size]
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to read files: %w", err)
+	}
+	return contents, nil
+}
+
+// HasCommit reports whether a commit exists in the local object store.
+//
+// How it works:
+// 1. Executes 'git cat-file -e SHA^{commit}'
+// 2. Exit status 0 means the commit exists; any failure means it does not
+//
+// A remote tip handed to the pre-push hook is missing locally when someone
+// else pushed to the branch, or after a force push rewrote it.
+//
+// Parameters:
+// - sha: SHA of the commit to look up
+//
+// Returns:
+// - bool: true if the commit exists locally
+//
+// Example output:
+// Success: true (for the SHA of HEAD)
+// Missing: false (for a commit that was never fetched)
+func (r *CLIRepository) HasCommit(sha string) bool {
+	return r.git("cat-file", "-e", sha+"^{commit}").Run() == nil
+}
+
+// IsAncestor reports whether one commit is an ancestor of another.
+//
+// How it works:
+// 1. Executes 'git merge-base --is-ancestor ANCESTOR DESCENDANT'
+// 2. Exit status 0 means ancestor, 1 means not an ancestor, anything else is an error
+//
+// A commit counts as its own ancestor. A false result for a previously seen
+// HEAD means history was rewritten (rebase, amend, force push).
+//
+// Parameters:
+// - ancestor: SHA or revision of the presumed ancestor
+// - descendant: SHA or revision of the presumed descendant
+//
+// Returns:
+// - bool: true if ancestor is reachable from descendant
+// - error: if either revision does not exist
+//
+// Example output:
+// Success: true (for "v1.0", "main")
+// Error: "failed to check ancestry of a1b2c3d: exit status 128"
+func (r *CLIRepository) IsAncestor(ancestor, descendant string) (bool, error) {
+	err := r.git("merge-base", "--is-ancestor", ancestor, descendant).Run()
+	if err == nil {
+		return true, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to check ancestry of %s: %w", ancestor, err)
+}
+
+// DeletedLines returns the lines of the first parent's version of each file
+// that a commit deleted or modified.
+//
+// How it works:
+// 1. Executes 'git diff -U0 -M SHA^ SHA', which prints every hunk without
+// context lines as "@@ -START,COUNT +START,COUNT @@"
+// 2. Takes the file path from the "--- a/PATH" line of each file's header;
+// added files ("--- /dev/null") have no previous version and are skipped
+// 3. Records the old-side range of every hunk that removes lines (COUNT > 0)
+//
+// Parameters:
+// - sha: commit whose changes are inspected; root commits have no deleted lines
+//
+// Returns:
+// - map[string][]LineRange: old path -> removed or replaced line ranges
+// - error: if the commit does not exist
+//
+// Example output:
+// Success: map[string][]LineRange{"main.go": {{Start: 12, Count: 3}, {Start: 40, Count: 1}}}
+// Error: "failed to diff a1b2c3d: exit status 128"
+func (r *CLIRepository) DeletedLines(sha string) (map[string][]LineRange, error) {
+	if err := r.git("rev-parse", "--verify", "--quiet", sha+"^").Run(); err != nil {
+		return make(map[string][]LineRange), nil // Root commit
+	}
+
+	out, err := r.git("diff", "-U0", "-M", "--no-color", "--no-ext-diff",
+		"--src-prefix=a/", "--dst-prefix=b/", sha+"^", sha).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s: %w", sha, err)
+	}
+
+	deleted, err := parseDeletedLines(string(out))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse diff of %s: %w", sha, err)
+	}
+	return deleted, nil
+}
+
+// parseDeletedLines collects the old-side line ranges of the hunks of a diff.
+//
+// How it works:
+// 1. Each "diff --git" line starts a file header; the path is taken from its
+// "--- a/PATH" line and the header ends at the "+++" line; paths with special
+// characters are C-quoted by git and unquoted here
+// 2. Files without an "a/" side ("--- /dev/null") are skipped
+// 3. Each "@@ -START[,COUNT] ..." line of a file records its old-side range;
+// COUNT defaults to 1 and pure additions (COUNT 0) are skipped
+//
+// Parameters:
+// - diff: output of 'git diff -U0' with the "a/" and "b/" prefixes
+//
+// Returns:
+// - map[string][]LineRange: old path -> removed or replaced line ranges
+// - error: if a file or hunk header is malformed
+//
+// Example input/output:
+// Input: "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -12,3 +12 @@\n"
+// Output: map[string][]LineRange{"main.go": {{Start: 12, Count: 3}}}
+func parseDeletedLines(diff string) (map[string][]LineRange, error) {
+	deleted := make(map[string][]LineRange)
+	path, header := "", false
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			path, header = "", true
+		case header && strings.HasPrefix(line, "--- "):
+			old := strings.TrimRight(line[4:], "\t")
+			if strings.HasPrefix(old, "\"") {
+				unquoted, err := strconv.Unquote(old)
+				if err != nil {
+					return nil, fmt.Errorf("unexpected file header %q", line)
+				}
+				old = unquoted
+			}
+			if strings.HasPrefix(old, "a/") {
+				path = old[2:]
+			}
+		case header && strings.HasPrefix(line, "+++ "):
+			header = false
+		case !header && strings.HasPrefix(line, "@@ -") && path != "":
+			fields := strings.Fields(line[4:])
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("unexpected hunk header %q", line)
+			}
+			old := fields[0]
+			start, count := old, "1"
+			if i := strings.Index(old, ","); i >= 0 {
+				start, count = old[:i], old[i+1:]
+			}
+			s, err1 := strconv.Atoi(start)
+			c, err2 := strconv.Atoi(count)
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("unexpected hunk header %q", line)
+			}
+			if c > 0 {
+				deleted[path] = append(deleted[path], LineRange{Start: s, Count: c})
+			}
+		}
+	}
+	return deleted, nil
+}
+
+// BlameLines counts the non-blank lines among the given ranges of a file that
+// each commit last changed.
+//
+// How it works:
+// 1. Executes 'git blame -w --line-porcelain -L START,+COUNT ... REV -- PATH';
+// -w ignores whitespace-only changes when looking for a line's origin
+// 2. Every line starts with a header "<sha> <orig-line> <final-line>" and
+// ends with its content prefixed by a tab
+// 3. Counts the header SHAs of lines whose content is not blank
+//
+// Parameters:
+// - rev: revision whose version of the file is blamed
+// - path: file path relative to the repository root
+// - ranges: lines to blame
+//
+// Returns:
+// - map[string]int: commit SHA -> number of lines
+// - error: if the file or a line does not exist at the revision
+//
+// Example output:
+// Success: map[string]int{"a1b2c3d4...": 3, "e5f6a7b8...": 1}
+// Error: "failed to blame main.go at a1b2c3d^: exit status 128"
+func (r *CLIRepository) BlameLines(rev, path string, ranges []LineRange) (map[string]int, error) {
+	shas := make(map[string]int)
+	if len(ranges) == 0 {
+		return shas, nil
+	}
+
+	args := []string{"blame", "-w", "--line-porcelain"}
+	for _, lr := range ranges {
+		args = append(args, "-L", fmt.Sprintf("%d,+%d", lr.Start, lr.Count))
+	}
+	args = append(args, rev, "--", path)
+	out, err := r.git(args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame %s at %s: %w", path, rev, err)
+	}
+
+	sha := ""
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			if sha != "" && strings.TrimSpace(line) != "" {
+				shas[sha]++
+			}
+			sha = ""
+		case sha == "" && len(line) > 40 && line[40] == ' ':
+			sha = line[:40]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse blame of %s: %w", path, err)
+	}
+	return shas, nil
+}
+
+// BlameFile returns the commit, author and author time that every line of a
+// file was last changed in.
+//
+// How it works:
+// 1. Executes 'git blame -w --line-porcelain REV -- PATH'
+// 2. Every line starts with a header "<sha> <orig-line> <final-line>",
+// followed by "author", "author-time", "author-tz" and "filename" fields
+// among others, and ends with its content prefixed by a tab
+// 3. Emits one BlameLine per content line, with the time in the author's timezone
+//
+// Parameters:
+// - rev: revision whose version of the file is blamed
+// - path: file path relative to the repository root
+//
+// Returns:
+// - []BlameLine: one entry per line of the file, in file order
+// - error: if the file does not exist at the revision
+//
+// Example output:
+// Success: []BlameLine{{SHA: "a1b2c3d4...", Author: "Jane Doe", Time: 2024-03-01 10:00:00 +0100, Path: "main.go"}}
+// Error: "failed to blame main.go at HEAD: exit status 128"
+func (r *CLIRepository) BlameFile(rev, path string) ([]BlameLine, error) {
+	out, err := r.git("blame", "-w", "--line-porcelain", rev, "--", path).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame %s at %s: %w", path, rev, err)
+	}
+
+	var lines []BlameLine
+	var current BlameLine
+	var unix int64
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			lines = append(lines, current)
+			current = BlameLine{}
+		case current.SHA == "" && len(line) > 40 && line[40] == ' ':
+			current.SHA = line[:40]
+		case strings.HasPrefix(line, "author "):
+			current.Author = line[len("author "):]
+		case strings.HasPrefix(line, "author-time "):
+			unix, _ = strconv.ParseInt(line[len("author-time "):], 10, 64)
+			current.Time = time.Unix(unix, 0)
+		case strings.HasPrefix(line, "author-tz "):
+			current.Time = current.Time.In(parseTimezone(line[len("author-tz "):]))
+		case strings.HasPrefix(line, "filename "):
+			current.Path = line[len("filename "):]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse blame of %s: %w", path, err)
+	}
+	return lines, nil
+}
+
+// parseTimezone turns a git timezone offset ("+0130", "-0800") into a location
+func parseTimezone(tz string) *time.Location {
+	if len(tz) != 5 {
+		return time.UTC
+	}
+	hours, err1 := strconv.Atoi(tz[1:3])
+	minutes, err2 := strconv.Atoi(tz[3:5])
+	if err1 != nil || err2 != nil {
+		return time.UTC
+	}
+	offset := hours*3600 + minutes*60
+	if tz[0] == '-' {
+		offset = -offset
+	}
+	return time.FixedZone(tz, offset)
+}
+
+// splitLines splits command output into trimmed, non-empty lines.
+func splitLines(output string) []string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	result := make([]string, 0, len(lines))
+	for _, l := range lines {
+		l = strings.TrimSpace(l)
+		if l != "" {
+			result = append(result, l)
+		}
+	}
+	return result
+}
@@ -0,0 +1,165 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDeletedLines(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want map[string][]LineRange
+	}{
+		{"empty diff", "", map[string][]LineRange{}},
+		{
+			"replaced and removed lines",
+			"diff --git a/main.go b/main.go\n" +
+				"index 1111111..2222222 100644\n" +
+				"--- a/main.go\n" +
+				"+++ b/main.go\n" +
+				"@@ -12,3 +12 @@ func main() {\n" +
+				"-a\n-b\n-c\n+d\n" +
+				"@@ -40 +38,2 @@\n" +
+				"-e\n+f\n+g\n",
+			map[string][]LineRange{"main.go": {{Start: 12, Count: 3}, {Start: 40, Count: 1}}},
+		},
+		{
+			"pure additions are skipped",
+			"diff --git a/main.go b/main.go\n" +
+				"--- a/main.go\n" +
+				"+++ b/main.go\n" +
+				"@@ -5,0 +6,2 @@\n" +
+				"+a\n+b\n",
+			map[string][]LineRange{},
+		},
+		{
+			"added file has no old version",
+			"diff --git a/new.go b/new.go\n" +
+				"new file mode 100644\n" +
+				"--- /dev/null\n" +
+				"+++ b/new.go\n" +
+				"@@ -0,0 +1,2 @@\n" +
+				"+a\n+b\n",
+			map[string][]LineRange{},
+		},
+		{
+			"renamed file keeps its old path",
+			"diff --git a/old.go b/new.go\n" +
+				"similarity index 90%\n" +
+				"rename from old.go\n" +
+				"rename to new.go\n" +
+				"--- a/old.go\n" +
+				"+++ b/new.go\n" +
+				"@@ -7,2 +7,2 @@\n" +
+				"-a\n-b\n+c\n+d\n",
+			map[string][]LineRange{"old.go": {{Start: 7, Count: 2}}},
+		},
+		{
+			"removed lines that look like headers",
+			"diff --git a/a.txt b/a.txt\n" +
+				"--- a/a.txt\n" +
+				"+++ b/a.txt\n" +
+				"@@ -1,2 +0,0 @@\n" +
+				"--- a/b.txt\n" +
+				"-x\n" +
+				"diff --git a/b.txt b/b.txt\n" +
+				"--- a/b.txt\n" +
+				"+++ b/b.txt\n" +
+				"@@ -3 +3 @@\n" +
+				"-y\n+z\n",
+			map[string][]LineRange{"a.txt": {{Start: 1, Count: 2}}, "b.txt": {{Start: 3, Count: 1}}},
+		},
+		{
+			"quoted path",
+			"diff --git \"a/my file.go\" \"b/my file.go\"\n" +
+				"--- \"a/my file.go\"\n" +
+				"+++ \"b/my file.go\"\n" +
+				"@@ -2 +2 @@\n" +
+				"-a\n+b\n",
+			map[string][]LineRange{"my file.go": {{Start: 2, Count: 1}}},
+		},
+		{
+			"quoted path with escapes",
+			"diff --git \"a/caf\\303\\251 \\\"x\\\".go\" \"b/caf\\303\\251 \\\"x\\\".go\"\n" +
+				"--- \"a/caf\\303\\251 \\\"x\\\".go\"\n" +
+				"+++ \"b/caf\\303\\251 \\\"x\\\".go\"\n" +
+				"@@ -4,2 +4 @@\n" +
+				"-a\n-b\n+c\n",
+			map[string][]LineRange{"café \"x\".go": {{Start: 4, Count: 2}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDeletedLines(tt.diff)
+			if err != nil {
+				t.Fatalf("parseDeletedLines: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDeletedLines = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDeletedLinesMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+	}{
+		{"hunk header", "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -x,1 +1 @@\n"},
+		{"truncated hunk header", "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -\n"},
+		{"file header", "diff --git \"a/x\" \"b/x\"\n--- \"a/x\n+++ \"b/x\"\n@@ -1 +1 @@\n"},
+	}
+
+	for _, tt := range tests {
+		if got, err := parseDeletedLines(tt.diff); err == nil {
+			t.Errorf("parseDeletedLines of a malformed %s = %v, want an error", tt.name, got)
+		}
+	}
+}
+
+func TestParseNumstatLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want *FileChange
+	}{
+		{"10\t5\tsrc/main.go", &FileChange{Path: "src/main.go", ChangeType: ChangeTypeModified, LinesAdded: 10, LinesDeleted: 5}},
+		{"-\t-\timage.png", &FileChange{Path: "image.png", ChangeType: ChangeTypeModified}},
+		{"0\t0\told.go => new.go", &FileChange{Path: "new.go", ChangeType: ChangeTypeRenamed, OldPath: "old.go"}},
+		{"1\t2\tsrc/{old_name.go => new_name.go}", &FileChange{Path: "src/new_name.go", ChangeType: ChangeTypeRenamed, LinesAdded: 1, LinesDeleted: 2, OldPath: "src/old_name.go"}},
+		{"3\t0\t{a => b}/main.go", &FileChange{Path: "b/main.go", ChangeType: ChangeTypeRenamed, LinesAdded: 3, OldPath: "a/main.go"}},
+		{"not a numstat line", nil},
+	}
+
+	for _, tt := range tests {
+		if got := parseNumstatLine(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseNumstatLine(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseSummaryLine(t *testing.T) {
+	tests := []struct {
+		line       string
+		wantPath   string
+		wantChange ChangeType
+		wantOK     bool
+	}{
+		{" create mode 100644 src/auth.go", "src/auth.go", ChangeTypeAdded, true},
+		{" delete mode 100755 bin/run.sh", "bin/run.sh", ChangeTypeDeleted, true},
+		{" create mode 100644 my file.txt", "my file.txt", ChangeTypeAdded, true},
+		{" mode change 100644 => 100755 run.sh", "", 0, false},
+		{" rename src/{a.go => b.go} (100%)", "", 0, false},
+		{" create mode 100644", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		path, change, ok := parseSummaryLine(tt.line)
+		if path != tt.wantPath || change != tt.wantChange || ok != tt.wantOK {
+			t.Errorf("parseSummaryLine(%q) = %q, %v, %v, want %q, %v, %v",
+				tt.line, path, change, ok, tt.wantPath, tt.wantChange, tt.wantOK)
+		}
+	}
+}
@@ -63,6 +63,27 @@ type Commit struct {
 	Stats        CommitStats
 	ParentSHAs   []string
 	IsMerge      bool
+	Category     string // Kind of work (feature, fix, ...), set by analysis.Classifier
+}
+
+// FileRef identifies a file at a revision
+type FileRef struct {
+	Rev  string
+	Path string
+}
+
+// LineRange is a range of line numbers in a file, starting at 1
+type LineRange struct {
+	Start int
+	Count int
+}
+
+// BlameLine is the origin of one line of a file: the commit that last changed it
+type BlameLine struct {
+	SHA    string
+	Author string
+	Time   time.Time // Author time of the commit, in the author's timezone
+	Path   string    // Path of the file in that commit
 }
 
 // LoadOptions configures how commits are loaded from the repository
@@ -74,4 +95,5 @@ type LoadOptions struct {
 	MaxCommits       int        // Limit number of commits (0 = unlimited)
 	IncludeMerges    bool       // Whether to include merge commits
 	IncludeFileStats bool       // Whether to include per-file diff stats (slower)
+	Exclude          []string   // Revisions whose history is excluded (passed after --not)
 }
@@ -22,4 +22,43 @@ type Repository interface {
 
 	// GetLatestCommitSHA returns the SHA of the most recent commit
 	GetLatestCommitSHA() (string, error)
+
+	// GetStagedFiles returns the paths of files staged in the index
+	GetStagedFiles() ([]string, error)
+
+	// GetUserIdent returns the author identity git uses for new commits
+	GetUserIdent() (Author, error)
+
+	// GetHooksDir returns the absolute path of the directory git runs hooks from
+	GetHooksDir() (string, error)
+
+	// GetGitDir returns the absolute path of the repository's .git directory
+	GetGitDir() (string, error)
+
+	// ResolveRevision resolves a revision or range to the commit SHAs it names
+	ResolveRevision(rev string) (string, error)
+
+	// GetLineCounts returns the number of lines of every text file at a revision
+	GetLineCounts(rev string) (map[string]int, error)
+
+	// BlameAuthors returns how many lines of a file at a revision each author last changed
+	BlameAuthors(rev, path string) (map[string]int, error)
+
+	// ReadFiles returns the contents of files at revisions (nil for missing files)
+	ReadFiles(refs []FileRef) ([][]byte, error)
+
+	// HasCommit reports whether a commit exists in the local object store
+	HasCommit(sha string) bool
+
+	// IsAncestor reports whether commit ancestor is reachable from descendant
+	IsAncestor(ancestor, descendant string) (bool, error)
+
+	// DeletedLines returns the lines of each file's previous version that a commit deleted or modified
+	DeletedLines(sha string) (map[string][]LineRange, error)
+
+	// BlameFile returns the origin of every line of a file at a revision
+	BlameFile(rev, path string) ([]BlameLine, error)
+
+	// BlameLines returns how many of the given non-blank lines of a file at a revision each commit last changed
+	BlameLines(rev, path string, ranges []LineRange) (map[string]int, error)
 }
@@ -0,0 +1,113 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// marker identifies hook scripts written by histui
+const marker = "# Installed by histui"
+
+// backupSuffix is appended to pre-existing hooks replaced with --force
+const backupSuffix = ".histui-backup"
+
+// Supported lists the git hooks histui can install
+var Supported = []string{"pre-commit", "commit-msg", "pre-push"}
+
+// IsSupported reports whether name is a hook histui can install and run
+func IsSupported(name string) bool {
+	for _, h := range Supported {
+		if h == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Install writes histui hook scripts for the given hooks into dir. Existing
+// hooks not written by histui are left alone unless force is set, in which
+// case they are renamed with a backup suffix.
+func Install(dir string, names []string, command string, force bool) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+
+	for _, name := range names {
+		if !IsSupported(name) {
+			return fmt.Errorf("unsupported hook: %s", name)
+		}
+
+		path := filepath.Join(dir, name)
+		managed, err := isManaged(path)
+		if err != nil {
+			return err
+		}
+		if !managed {
+			if _, err := os.Stat(path); err == nil {
+				if !force {
+					return fmt.Errorf("hook %s already exists (use --force to back it up and replace it)", path)
+				}
+				if err := os.Rename(path, path+backupSuffix); err != nil {
+					return fmt.Errorf("failed to back up %s: %w", path, err)
+				}
+			}
+		}
+
+		if err := os.WriteFile(path, []byte(script(name, command)), 0o755); err != nil {
+			return fmt.Errorf("failed to write hook %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Uninstall removes histui hook scripts from dir and restores any hooks that
+// were backed up during installation. Hooks not written by histui are kept.
+func Uninstall(dir string, names []string) error {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		managed, err := isManaged(path)
+		if err != nil {
+			return err
+		}
+		if !managed {
+			continue
+		}
+
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove hook %s: %w", path, err)
+		}
+		if _, err := os.Stat(path + backupSuffix); err == nil {
+			if err := os.Rename(path+backupSuffix, path); err != nil {
+				return fmt.Errorf("failed to restore %s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// script returns the shell script that forwards a hook to histui; command is
+// the path of the histui executable
+func script(name, command string) string {
+	return fmt.Sprintf("#!/bin/sh\n%s - remove with 'histui hooks uninstall'\nexec %s hook run %s \"$@\"\n",
+		marker, shellQuote(command), name)
+}
+
+// shellQuote quotes s as a single word for sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// isManaged reports whether the hook at path was written by histui
+func isManaged(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read hook %s: %w", path, err)
+	}
+	return strings.Contains(string(data), marker), nil
+}
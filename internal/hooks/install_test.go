@@ -0,0 +1,54 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"histui", "'histui'"},
+		{"/opt/my tools/histui", "'/opt/my tools/histui'"},
+		{"it's", `'it'\''s'`},
+		{"$(rm -rf ~)", "'$(rm -rf ~)'"},
+	}
+
+	for _, tt := range tests {
+		if got := shellQuote(tt.s); got != tt.want {
+			t.Errorf("shellQuote(%q) = %s, want %s", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestInstalledHookRunsCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found on PATH")
+	}
+
+	// An executable whose path needs quoting, printing its arguments
+	binDir := filepath.Join(t.TempDir(), "it's my $HOME")
+	if err := os.MkdirAll(binDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	command := filepath.Join(binDir, "histui")
+	if err := os.WriteFile(command, []byte("#!/bin/sh\necho \"$@\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	hooksDir := t.TempDir()
+	if err := Install(hooksDir, []string{"commit-msg"}, command, false); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(filepath.Join(hooksDir, "commit-msg"), ".git/COMMIT_EDITMSG").CombinedOutput()
+	if err != nil {
+		t.Fatalf("hook failed: %v\n%s", err, out)
+	}
+	if got, want := strings.TrimSpace(string(out)), "hook run commit-msg .git/COMMIT_EDITMSG"; got != want {
+		t.Errorf("hook ran with %q, want %q", got, want)
+	}
+}
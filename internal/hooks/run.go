@@ -0,0 +1,297 @@
+package hooks
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"histui/internal/analysis"
+	"histui/internal/cache"
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// zeroSHA is what git passes to pre-push for refs that do not exist
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+// Finding is a single problem reported by a hook check
+type Finding struct {
+	Check   string
+	Message string
+}
+
+// Report is the outcome of running a hook
+type Report struct {
+	Hook     string
+	Mode     config.HookMode
+	Findings []Finding
+}
+
+// Blocking reports whether the git operation should be aborted
+func (r Report) Blocking() bool {
+	return r.Mode == config.HookModeBlock && len(r.Findings) > 0
+}
+
+// pushRef is one line of the pre-push hook's stdin
+type pushRef struct {
+	LocalRef  string
+	LocalSHA  string
+	RemoteRef string
+	RemoteSHA string
+}
+
+// hookContext carries the inputs git hands to a hook
+type hookContext struct {
+	name     string
+	args     []string
+	pushRefs []pushRef
+}
+
+// check is a named hook check
+type check func(r *Runner, ctx hookContext) ([]Finding, error)
+
+// checks maps check names usable in the config file to their implementation
+var checks = map[string]check{
+	"coupling": checkCoupling,
+	"message":  checkMessages,
+}
+
+// Runner executes the checks configured for git hooks
+type Runner struct {
+	repo  git.Repository
+	cfg   config.Config
+	store cache.CacheManager
+}
+
+// NewRunner creates a hook runner for the repository. store, if not nil,
+// caches the history the checks analyze between runs.
+func NewRunner(repo git.Repository, cfg config.Config, store cache.CacheManager) *Runner {
+	return &Runner{repo: repo, cfg: cfg, store: store}
+}
+
+// Run executes the checks configured for the named hook. args are the
+// arguments git passed to the hook and stdin is the hook's standard input.
+func (r *Runner) Run(name string, args []string, stdin io.Reader) (Report, error) {
+	hookCfg, ok := r.cfg.Hooks[name]
+	if !ok {
+		return Report{Hook: name, Mode: config.HookModeWarn}, nil
+	}
+	if err := config.ValidateHooks(map[string]config.HookConfig{name: hookCfg}); err != nil {
+		return Report{Hook: name, Mode: config.HookModeBlock}, err
+	}
+	report := Report{Hook: name, Mode: hookCfg.Mode}
+	if report.Mode == "" {
+		report.Mode = config.HookModeWarn
+	}
+
+	ctx := hookContext{name: name, args: args}
+	if name == "pre-push" {
+		refs, err := parsePushRefs(stdin)
+		if err != nil {
+			return report, err
+		}
+		ctx.pushRefs = refs
+	}
+
+	for _, checkName := range hookCfg.Checks {
+		fn, ok := checks[checkName]
+		if !ok {
+			return report, fmt.Errorf("unknown check %q in %s hook", checkName, name)
+		}
+		findings, err := fn(r, ctx)
+		if err != nil {
+			return report, fmt.Errorf("%s check failed: %w", checkName, err)
+		}
+		report.Findings = append(report.Findings, findings...)
+	}
+	return report, nil
+}
+
+// checkCoupling reports coupled files missing from the staged files
+// (pre-commit, commit-msg) or from the pushed commits (pre-push)
+func checkCoupling(r *Runner, ctx hookContext) ([]Finding, error) {
+	var changed []string
+	if ctx.name == "pre-push" {
+		pushed, err := r.pushedCommits(ctx.pushRefs)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, c := range pushed {
+			for _, fc := range c.FilesChanged {
+				if !seen[fc.Path] {
+					seen[fc.Path] = true
+					changed = append(changed, fc.Path)
+				}
+			}
+		}
+	} else {
+		staged, err := r.repo.GetStagedFiles()
+		if err != nil {
+			return nil, err
+		}
+		changed = staged
+	}
+
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	// Hooks run on every commit, so the history is analyzed through the cache
+	settings := analysis.Settings{IgnorePatterns: r.cfg.Impact.Ignore, Config: r.cfg, Repo: r.repo}
+	coordinator, err := analysis.NewCoordinator(r.repo, analysis.DefaultRegistry, settings, []string{"coupling"}, r.store)
+	if err != nil {
+		return nil, err
+	}
+	if err := coordinator.Run(git.LoadOptions{}); err != nil {
+		return nil, err
+	}
+	result, _ := coordinator.Result("coupling")
+
+	var findings []Finding
+	for _, s := range analysis.SuggestMissingFiles(*result.(*analysis.CouplingResults), changed, r.cfg.Impact.MinScore) {
+		findings = append(findings, Finding{
+			Check: "coupling",
+			Message: fmt.Sprintf("%s usually changes with %s (score %.2f) but is not part of this change",
+				s.File, strings.Join(s.CoupledWith, ", "), s.Confidence),
+		})
+	}
+	return findings, nil
+}
+
+// checkMessages validates the message being committed (commit-msg) or the
+// messages of the pushed commits (pre-push)
+func checkMessages(r *Runner, ctx hookContext) ([]Finding, error) {
+	switch ctx.name {
+	case "commit-msg":
+		if len(ctx.args) == 0 {
+			return nil, fmt.Errorf("commit-msg hook requires the message file argument")
+		}
+		data, err := os.ReadFile(ctx.args[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read commit message: %w", err)
+		}
+		subject, body := parseMessageFile(string(data))
+		return toFindings("", CheckMessage(subject, body, 0, r.cfg.Messages)), nil
+
+	case "pre-push":
+		pushed, err := r.pushedCommits(ctx.pushRefs)
+		if err != nil {
+			return nil, err
+		}
+		var findings []Finding
+		for _, c := range pushed {
+			findings = append(findings, toFindings(c.ShortSHA, CheckMessage(c.Subject, c.Body, c.Stats.Insertions+c.Stats.Deletions, r.cfg.Messages))...)
+		}
+		return findings, nil
+	}
+
+	// No message exists yet in other hooks
+	return nil, nil
+}
+
+// CheckMessage validates a commit message against the rules and returns a
+// description of every violated rule. linesChanged is the size of the diff,
+// 0 if it is not known yet.
+func CheckMessage(subject, body string, linesChanged int, rules config.MessageRules) []string {
+	var problems []string
+	for _, p := range analysis.CheckMessage(subject, body, linesChanged, rules) {
+		if p.Check == analysis.CheckMood && !rules.RequireImperative {
+			continue
+		}
+		problems = append(problems, p.Message)
+	}
+	return problems
+}
+
+// pushedCommits loads the commits being pushed that the remote does not have yet
+func (r *Runner) pushedCommits(refs []pushRef) ([]git.Commit, error) {
+	var commits []git.Commit
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		// Deleting a remote ref pushes no commits
+		if ref.LocalSHA == zeroSHA {
+			continue
+		}
+
+		opts := git.LoadOptions{Branch: ref.LocalSHA}
+		if ref.RemoteSHA != zeroSHA && r.repo.HasCommit(ref.RemoteSHA) {
+			opts.Branch = ref.RemoteSHA + ".." + ref.LocalSHA
+		} else {
+			// New remote branch, or a remote tip we never fetched (someone
+			// else pushed, or a force push): everything not on any remote yet
+			opts.Exclude = []string{"--remotes"}
+		}
+
+		loaded, _, _, _, err := r.repo.LoadCommits(opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range loaded {
+			if !seen[c.SHA] {
+				seen[c.SHA] = true
+				commits = append(commits, c)
+			}
+		}
+	}
+	return commits, nil
+}
+
+// parsePushRefs parses the "<local ref> <local sha> <remote ref> <remote sha>"
+// lines git writes to the pre-push hook's stdin
+func parsePushRefs(stdin io.Reader) ([]pushRef, error) {
+	if stdin == nil {
+		return nil, nil
+	}
+	var refs []pushRef
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+		refs = append(refs, pushRef{
+			LocalRef:  fields[0],
+			LocalSHA:  fields[1],
+			RemoteRef: fields[2],
+			RemoteSHA: fields[3],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pushed refs: %w", err)
+	}
+	return refs, nil
+}
+
+// parseMessageFile extracts subject and body from a commit message file,
+// dropping comment lines and everything below the scissors line
+func parseMessageFile(content string) (string, string) {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "# ------------------------ >8 ------------------------") {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	message := strings.TrimSpace(strings.Join(lines, "\n"))
+	subject, body, _ := strings.Cut(message, "\n")
+	return strings.TrimSpace(subject), strings.TrimSpace(body)
+}
+
+// toFindings converts message problems into findings, prefixed with the commit if known
+func toFindings(shortSHA string, problems []string) []Finding {
+	findings := make([]Finding, 0, len(problems))
+	for _, p := range problems {
+		if shortSHA != "" {
+			p = fmt.Sprintf("[%s] %s", shortSHA, p)
+		}
+		findings = append(findings, Finding{Check: "message", Message: p})
+	}
+	return findings
+}
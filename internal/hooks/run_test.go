@@ -0,0 +1,152 @@
+package hooks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"histui/internal/config"
+	"histui/internal/git"
+)
+
+// fakeRepo records the commits loaded for a push; methods the tests do not
+// need panic through the nil embedded interface
+type fakeRepo struct {
+	git.Repository
+	known  map[string]bool
+	loaded []git.LoadOptions
+}
+
+func (f *fakeRepo) HasCommit(sha string) bool {
+	return f.known[sha]
+}
+
+func (f *fakeRepo) LoadCommits(opts git.LoadOptions) ([]git.Commit, int, int, int, error) {
+	f.loaded = append(f.loaded, opts)
+	return []git.Commit{{SHA: opts.Branch}}, 0, 0, 0, nil
+}
+
+func TestParsePushRefs(t *testing.T) {
+	stdin := "refs/heads/main 1111 refs/heads/main 2222\n" +
+		"\n" +
+		"refs/heads/topic 3333 refs/heads/topic " + zeroSHA + "\n" +
+		"malformed line\n"
+
+	want := []pushRef{
+		{LocalRef: "refs/heads/main", LocalSHA: "1111", RemoteRef: "refs/heads/main", RemoteSHA: "2222"},
+		{LocalRef: "refs/heads/topic", LocalSHA: "3333", RemoteRef: "refs/heads/topic", RemoteSHA: zeroSHA},
+	}
+	got, err := parsePushRefs(strings.NewReader(stdin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePushRefs = %+v, want %+v", got, want)
+	}
+
+	if got, err := parsePushRefs(nil); got != nil || err != nil {
+		t.Errorf("parsePushRefs(nil) = %v, %v, want nil, nil", got, err)
+	}
+}
+
+func TestParseMessageFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantSubject string
+		wantBody    string
+	}{
+		{"subject only", "Fix the parser\n", "Fix the parser", ""},
+		{"subject and body", "Fix the parser\n\nIt crashed on empty input.\n", "Fix the parser", "It crashed on empty input."},
+		{
+			"comments dropped",
+			"# Please enter the commit message\nAdd caching\n# On branch main\n\nSpeeds up reruns.\n",
+			"Add caching",
+			"Speeds up reruns.",
+		},
+		{
+			"scissors",
+			"Add caching\n\nBody\n# ------------------------ >8 ------------------------\ndiff --git a/x b/x\n",
+			"Add caching",
+			"Body",
+		},
+		{"leading blank lines", "\n\n  Trim me  \n", "Trim me", ""},
+		{"empty", "# only a comment\n", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject, body := parseMessageFile(tt.content)
+			if subject != tt.wantSubject || body != tt.wantBody {
+				t.Errorf("parseMessageFile = %q, %q, want %q, %q", subject, body, tt.wantSubject, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestRunRejectsUnknownMode(t *testing.T) {
+	cfg := config.Default()
+	cfg.Hooks = map[string]config.HookConfig{"pre-commit": {Mode: "blok", Checks: []string{"coupling"}}}
+
+	report, err := NewRunner(nil, cfg, nil).Run("pre-commit", nil, nil)
+	if err == nil {
+		t.Fatal("Run with an unknown mode succeeded")
+	}
+	if report.Mode != config.HookModeBlock {
+		t.Errorf("report mode = %q, want %q", report.Mode, config.HookModeBlock)
+	}
+}
+
+func TestRunUnconfiguredHook(t *testing.T) {
+	cfg := config.Default()
+	cfg.Hooks = nil
+
+	report, err := NewRunner(nil, cfg, nil).Run("pre-rebase", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Mode != config.HookModeWarn || report.Blocking() {
+		t.Errorf("report = %+v, want a non-blocking warning", report)
+	}
+}
+
+func TestPushedCommits(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  pushRef
+		want []git.LoadOptions
+	}{
+		{
+			"known remote tip",
+			pushRef{LocalSHA: "local", RemoteSHA: "remote"},
+			[]git.LoadOptions{{Branch: "remote..local"}},
+		},
+		{
+			"new remote branch",
+			pushRef{LocalSHA: "local", RemoteSHA: zeroSHA},
+			[]git.LoadOptions{{Branch: "local", Exclude: []string{"--remotes"}}},
+		},
+		{
+			"unknown remote tip",
+			pushRef{LocalSHA: "local", RemoteSHA: "unfetched"},
+			[]git.LoadOptions{{Branch: "local", Exclude: []string{"--remotes"}}},
+		},
+		{
+			"deleted remote branch",
+			pushRef{LocalSHA: zeroSHA, RemoteSHA: "remote"},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepo{known: map[string]bool{"local": true, "remote": true}}
+			if _, err := NewRunner(repo, config.Default(), nil).pushedCommits([]pushRef{tt.ref}); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(repo.loaded, tt.want) {
+				t.Errorf("loaded %+v, want %+v", repo.loaded, tt.want)
+			}
+		})
+	}
+}